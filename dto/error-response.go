@@ -1,5 +1,12 @@
 package dto
 
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alkamalp/crm-golang/utils/apperror"
+)
+
 type ErrorResponse struct {
 	ResponseMeta
 	Data   any `json:"data"`
@@ -44,6 +51,62 @@ func DefaultDataInvalidResponse(validationErrors any) ErrorResponse {
 	}
 }
 
+func DefaultUnauthorizedResponse(msg string) ErrorResponse {
+	return ErrorResponse{
+		ResponseMeta: ResponseMeta{
+			Success:      false,
+			MessageTitle: "Unauthorized.",
+			Message:      msg,
+			ResponseTime: "",
+		},
+		Data: nil,
+	}
+}
+
+func DefaultForbiddenResponse() ErrorResponse {
+	return ErrorResponse{
+		ResponseMeta: ResponseMeta{
+			Success:      false,
+			MessageTitle: "Forbidden.",
+			Message:      "You do not have access to this resource",
+			ResponseTime: "",
+		},
+		Data: nil,
+	}
+}
+
 func DefaultBadRequestResponse() ErrorResponse {
 	return DefaultErrorResponseWithMessage("Bad request")
 }
+
+// NewErrorResponse status code and body for err, anything that is not an
+// apperror is reported as 500 without its message
+func NewErrorResponse(err error) (int, ErrorResponse) {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return http.StatusInternalServerError, DefaultErrorResponse()
+	}
+
+	switch appErr.Kind {
+	case apperror.ErrNotFound:
+		res := DefaultErrorResponseWithMessage(appErr.Message)
+		res.MessageTitle = "Not found."
+		return http.StatusNotFound, res
+	case apperror.ErrConflict:
+		res := DefaultErrorResponseWithMessage(appErr.Message)
+		res.MessageTitle = "Conflict."
+		res.Data = appErr.Details
+		return http.StatusConflict, res
+	case apperror.ErrValidation:
+		res := DefaultDataInvalidResponse(appErr.Details)
+		res.Message = appErr.Message
+		return http.StatusUnprocessableEntity, res
+	case apperror.ErrUnauthorized:
+		return http.StatusUnauthorized, DefaultUnauthorizedResponse(appErr.Message)
+	case apperror.ErrForbidden:
+		res := DefaultForbiddenResponse()
+		res.Message = appErr.Message
+		return http.StatusForbidden, res
+	}
+	return http.StatusInternalServerError, DefaultErrorResponse()
+}
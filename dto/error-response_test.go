@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+	}{
+		{"not found", apperror.NotFound("customer not found"), http.StatusNotFound, "customer not found"},
+		{"conflict", apperror.Conflict("customer already exists", nil), http.StatusConflict, "customer already exists"},
+		{"validation", apperror.Validation("invalid sort field", nil), http.StatusUnprocessableEntity, "invalid sort field"},
+		{"unauthorized", apperror.Unauthorized("invalid username or password"), http.StatusUnauthorized, "invalid username or password"},
+		{"forbidden", apperror.Forbidden("not allowed"), http.StatusForbidden, "not allowed"},
+		{"wrapped", fmt.Errorf("get customer: %w", apperror.NotFound("customer not found")), http.StatusNotFound, "customer not found"},
+		{"internal", errors.New("dial tcp 127.0.0.1:3306: connection refused"), http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, res := NewErrorResponse(tt.err)
+
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.message, res.Message)
+			assert.False(t, res.Success)
+		})
+	}
+}
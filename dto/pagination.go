@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Page size of list endpoints when the request leaves it out, and the most
+// a request can ask for
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+type PaginationLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+type Pagination struct {
+	Page       int             `json:"page,omitempty"`
+	Limit      int             `json:"limit"`
+	Total      int64           `json:"total"`
+	TotalPages int             `json:"totalPages"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+	Links      PaginationLinks `json:"links"`
+}
+
+// ListResponseMeta ResponseMeta of list endpoints
+type ListResponseMeta struct {
+	ResponseMeta
+	Pagination Pagination `json:"pagination"`
+}
+
+// PageOf limit and page of a list request with the defaults applied
+func PageOf(limit int, page int) (int, int) {
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+	if page <= 0 {
+		page = 1
+	}
+	return limit, page
+}
+
+// NewPagination paging metadata with links relative to the requested url,
+// page is 0 when the list was requested with a cursor
+func NewPagination(u url.URL, page int, limit int, total int64, nextCursor string) Pagination {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+	res := Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		NextCursor: nextCursor,
+		Links: PaginationLinks{
+			Self:  u.RequestURI(),
+			First: pageLink(u, 1),
+		},
+	}
+
+	if page == 0 {
+		if nextCursor != "" {
+			res.Links.Next = cursorLink(u, nextCursor)
+		}
+		return res
+	}
+	if page > 1 {
+		res.Links.Prev = pageLink(u, page-1)
+	}
+	if page < totalPages {
+		res.Links.Next = pageLink(u, page+1)
+	}
+	if totalPages > 0 {
+		res.Links.Last = pageLink(u, totalPages)
+	}
+	return res
+}
+
+func pageLink(u url.URL, page int) string {
+	query := u.Query()
+	query.Del("cursor")
+	query.Set("page", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+func cursorLink(u url.URL, cursor string) string {
+	query := u.Query()
+	query.Del("page")
+	query.Set("cursor", cursor)
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		page  int
+		want  [2]int
+	}{
+		{"defaults", 0, 0, [2]int{DefaultListLimit, 1}},
+		{"as requested", 5, 3, [2]int{5, 3}},
+		{"limit above the max", 500, 2, [2]int{MaxListLimit, 2}},
+		{"negative", -1, -1, [2]int{DefaultListLimit, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, page := PageOf(tt.limit, tt.page)
+			assert.Equal(t, tt.want, [2]int{limit, page})
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Account company that customers work for, its customers are its contacts
+type Account struct {
+	ID        uint   `gorm:"primary_key"`
+	Name      string `gorm:"column:name"`
+	Domain    string `gorm:"column:domain"`
+	Industry  string `gorm:"column:industry"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Account) TableName() string {
+	return "account"
+}
@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+// Types of an activity
+const (
+	ActivityTypeNote    = "note"
+	ActivityTypeCall    = "call"
+	ActivityTypeMeeting = "meeting"
+	ActivityTypeEmail   = "email"
+)
+
+// Activity interaction with a customer recorded by an actor, Actor_id is
+// nil once the actor is purged
+type Activity struct {
+	ID          uint      `gorm:"primary_key"`
+	Customer_id uint      `gorm:"column:customer_id"`
+	Actor_id    *uint     `gorm:"column:actor_id"`
+	Type        string    `gorm:"column:type"`
+	Subject     string    `gorm:"column:subject"`
+	Body        string    `gorm:"column:body"`
+	Occurred_at time.Time `gorm:"column:occurred_at"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Activity) TableName() string {
+	return "activity"
+}
+
+// Kinds of a timeline entry
+const (
+	TimelineKindActivity = "activity"
+	TimelineKindEvent    = "event"
+)
+
+// Types of a system event on the timeline
+const (
+	TimelineEventCreated = "created"
+	TimelineEventUpdated = "updated"
+	TimelineEventMerged  = "merged"
+)
+
+// TimelineEntry one row of a customer timeline, either an Activity, whose
+// ID it carries, or a system event with an activity type replaced by one of
+// the TimelineEvent types
+type TimelineEntry struct {
+	Kind        string    `gorm:"column:kind"`
+	ID          uint      `gorm:"column:id"`
+	Type        string    `gorm:"column:type"`
+	Subject     string    `gorm:"column:subject"`
+	Body        string    `gorm:"column:body"`
+	Actor_id    *uint     `gorm:"column:actor_id"`
+	Actor_name  *string   `gorm:"column:actor_name"`
+	Occurred_at time.Time `gorm:"column:occurred_at"`
+}
@@ -1,14 +1,22 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Actor struct {
 	ID        uint      `gorm:"primary_key"`
 	Username  string    `gorm:"column:username"`
-	Password  string    `gorm:"column:password"`
+	Password  string    `gorm:"column:password" json:"-"`
 	Role_id   uint      `gorm:"column:role_id"`
 	Verified  int       `gorm:"column:verified"`
 	Active    int       `gorm:"column:active"`
-	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;default:current_timestamp"`
-	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;default:current_timestamp;autoUpdateTime"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	// Deleted_at set while the actor is in the trash, the username stays
+	// taken until the actor is purged
+	Deleted_at gorm.DeletedAt `gorm:"column:deleted_at"`
+	Deleted_by *uint          `gorm:"column:deleted_by"`
 }
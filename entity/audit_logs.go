@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// Actions of an audit entry
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionMerge   = "merge"
+	AuditActionImport  = "import"
+)
+
+// AuditLog who did what to which record, Before and After hold JSON. Entries
+// are only ever added, Request_id and Client_ip tell which HTTP request made
+// the change
+type AuditLog struct {
+	ID          uint    `gorm:"primary_key"`
+	Actor_id    *uint   `gorm:"column:actor_id"`
+	Action      string  `gorm:"column:action"`
+	Entity_type string  `gorm:"column:entity_type"`
+	Entity_id   uint    `gorm:"column:entity_id"`
+	Before      *string `gorm:"column:before"`
+	After       *string `gorm:"column:after"`
+	Request_id  string  `gorm:"column:request_id"`
+	Client_ip   string  `gorm:"column:client_ip"`
+	CreatedAt   time.Time
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+// Types of a custom field
+const (
+	FieldTypeString  = "string"
+	FieldTypeNumber  = "number"
+	FieldTypeDate    = "date"
+	FieldTypeEnum    = "enum"
+	FieldTypeBoolean = "boolean"
+)
+
+// CustomField customer attribute defined at runtime, requests and filters
+// refer to it by Name
+type CustomField struct {
+	ID        uint             `gorm:"primary_key"`
+	Name      string           `gorm:"column:name"`
+	Label     string           `gorm:"column:label"`
+	Type      string           `gorm:"column:type"`
+	Required  bool             `gorm:"column:required"`
+	Rules     CustomFieldRules `gorm:"column:rules;serializer:json"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (CustomField) TableName() string {
+	return "custom_field"
+}
+
+// CustomFieldRules checks on the values of a field, Min, Max and Integer
+// apply to numbers, the lengths and Pattern to strings and Options lists the
+// values of an enum
+type CustomFieldRules struct {
+	Min        *float64 `json:"min,omitempty"`
+	Max        *float64 `json:"max,omitempty"`
+	Integer    bool     `json:"integer,omitempty"`
+	Min_length int      `json:"min_length,omitempty"`
+	Max_length int      `json:"max_length,omitempty"`
+	Pattern    string   `json:"pattern,omitempty"`
+	Options    []string `json:"options,omitempty"`
+}
+
+// CustomFieldValue value of a field for one customer, Value is the text
+// form (dates as YYYY-MM-DD, booleans as true or false) and Number is only
+// set for number fields
+type CustomFieldValue struct {
+	Customer_id uint     `gorm:"column:customer_id;primaryKey;autoIncrement:false"`
+	Field_id    uint     `gorm:"column:field_id;primaryKey;autoIncrement:false"`
+	Value       string   `gorm:"column:value"`
+	Number      *float64 `gorm:"column:number"`
+	UpdatedAt   time.Time
+}
+
+func (CustomFieldValue) TableName() string {
+	return "custom_field_value"
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// DuplicateScan one run of the duplicate detection over every customer.
+// Truncated_blocks counts the blocking keys shared by more customers than
+// a scan compares, pairs among the others are missing
+type DuplicateScan struct {
+	ID               uint `gorm:"primary_key"`
+	Customers        int  `gorm:"column:customers"`
+	Pairs            int  `gorm:"column:pairs"`
+	Truncated_blocks int  `gorm:"column:truncated_blocks"`
+	CreatedAt        time.Time
+}
+
+func (DuplicateScan) TableName() string {
+	return "customer_duplicate_scan"
+}
+
+// CustomerDuplicate pair of customers found by a scan, Customer_id is the
+// lower id and Reasons is comma separated
+type CustomerDuplicate struct {
+	ID          uint     `gorm:"primary_key"`
+	Scan_id     uint     `gorm:"column:scan_id"`
+	Customer_id uint     `gorm:"column:customer_id"`
+	Other_id    uint     `gorm:"column:other_id"`
+	Score       float64  `gorm:"column:score"`
+	Name_score  float64  `gorm:"column:name_score"`
+	Email_score float64  `gorm:"column:email_score"`
+	Reasons     string   `gorm:"column:reasons"`
+	Customer    Customer `gorm:"foreignKey:Customer_id"`
+	Other       Customer `gorm:"foreignKey:Other_id"`
+}
+
+func (CustomerDuplicate) TableName() string {
+	return "customer_duplicate"
+}
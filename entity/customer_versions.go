@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// CustomerVersion state of the versioned fields of a customer from CreatedAt
+// until the next version. Version 1 is the state before the first recorded
+// update, Reverted_from the version a revert restored
+type CustomerVersion struct {
+	ID            uint   `gorm:"primary_key"`
+	Customer_id   uint   `gorm:"column:customer_id"`
+	Version       uint   `gorm:"column:version"`
+	First_name    string `gorm:"column:first_name"`
+	Last_name     string `gorm:"column:last_name"`
+	Email         string `gorm:"column:email"`
+	Avatar        string `gorm:"column:avatar"`
+	Account_id    *uint  `gorm:"column:account_id"`
+	Reverted_from *uint  `gorm:"column:reverted_from"`
+	CreatedAt     time.Time
+}
+
+func (CustomerVersion) TableName() string {
+	return "customer_version"
+}
+
+// NewCustomerVersion the versioned fields of customer
+func NewCustomerVersion(customer Customer) CustomerVersion {
+	return CustomerVersion{
+		Customer_id: customer.ID,
+		First_name:  customer.First_name,
+		Last_name:   customer.Last_name,
+		Email:       customer.Email,
+		Avatar:      customer.Avatar,
+		Account_id:  customer.Account_id,
+	}
+}
+
+// SameFields true when both versions hold the same field values
+func (v CustomerVersion) SameFields(other CustomerVersion) bool {
+	sameAccount := v.Account_id == nil && other.Account_id == nil ||
+		v.Account_id != nil && other.Account_id != nil && *v.Account_id == *other.Account_id
+	return sameAccount && v.First_name == other.First_name && v.Last_name == other.Last_name &&
+		v.Email == other.Email && v.Avatar == other.Avatar
+}
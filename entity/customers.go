@@ -1,6 +1,10 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Customer struct {
 	ID         uint   `gorm:"primary_key"`
@@ -10,4 +14,35 @@ type Customer struct {
 	Avatar     string `gorm:"column:avatar"`
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+	// Email_normalized unique lower cased email, NULL for older rows that
+	// duplicated another customer's email
+	Email_normalized *string `gorm:"column:email_normalized" json:"-"`
+	// Deleted_at set while the customer is in the trash, gorm leaves
+	// trashed rows out of every query that is not Unscoped
+	Deleted_at gorm.DeletedAt `gorm:"column:deleted_at"`
+	Deleted_by *uint          `gorm:"column:deleted_by"`
+	// Avatar_key storage key of an uploaded avatar, Avatar then links to it
+	Avatar_key *string `gorm:"column:avatar_key" json:"-"`
+	// Account_id account the customer is a contact of
+	Account_id *uint `gorm:"column:account_id"`
+	Tags       []Tag `gorm:"many2many:customer_tag"`
+	// Field_values stored custom field values, Custom_fields has them by
+	// field name for responses and is left out when there are none
+	Field_values  []CustomFieldValue `gorm:"foreignKey:Customer_id" json:"-"`
+	Custom_fields map[string]any     `gorm:"-" json:",omitempty"`
+}
+
+func (Customer) TableName() string {
+	return "customer"
+}
+
+// CustomerRedirect id of a customer that was merged into Survivor_id
+type CustomerRedirect struct {
+	Old_id      uint `gorm:"column:old_id;primaryKey;autoIncrement:false"`
+	Survivor_id uint `gorm:"column:survivor_id"`
+	CreatedAt   time.Time
+}
+
+func (CustomerRedirect) TableName() string {
+	return "customer_redirect"
 }
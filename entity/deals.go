@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Deal revenue opportunity with a customer, Stage is the name of a configured
+// pipeline stage and Owner_id is nil once the owning actor is purged
+type Deal struct {
+	ID                  uint       `gorm:"primary_key"`
+	Title               string     `gorm:"column:title"`
+	Customer_id         uint       `gorm:"column:customer_id"`
+	Owner_id            *uint      `gorm:"column:owner_id"`
+	Stage               string     `gorm:"column:stage"`
+	Value               float64    `gorm:"column:value"`
+	Currency            string     `gorm:"column:currency"`
+	Expected_close_date *time.Time `gorm:"column:expected_close_date"`
+	Stage_changed_at    time.Time  `gorm:"column:stage_changed_at"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (Deal) TableName() string {
+	return "deal"
+}
+
+// DealStageChange one move of a deal between stages, From_stage is nil for
+// the stage the deal was created in
+type DealStageChange struct {
+	ID         uint      `gorm:"primary_key"`
+	Deal_id    uint      `gorm:"column:deal_id"`
+	From_stage *string   `gorm:"column:from_stage"`
+	To_stage   string    `gorm:"column:to_stage"`
+	Actor_id   *uint     `gorm:"column:actor_id"`
+	Changed_at time.Time `gorm:"column:changed_at"`
+}
+
+func (DealStageChange) TableName() string {
+	return "deal_stage_change"
+}
+
+// PipelineTotal deals of one stage and currency in the pipeline summary
+type PipelineTotal struct {
+	Stage    string  `gorm:"column:stage"`
+	Currency string  `gorm:"column:currency"`
+	Count    int64   `gorm:"column:count"`
+	Value    float64 `gorm:"column:value"`
+}
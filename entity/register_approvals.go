@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+)
+
+type RegisterApproval struct {
+	ID             uint       `gorm:"primary_key"`
+	Admin_id       uint       `gorm:"column:admin_id"`
+	Super_admin_id *uint      `gorm:"column:super_admin_id"`
+	Status         string     `gorm:"column:status"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+	DecidedAt      *time.Time `gorm:"column:decided_at"`
+	Admin          Actor      `gorm:"foreignKey:Admin_id"`
+}
+
+func (RegisterApproval) TableName() string {
+	return "register_approval"
+}
@@ -0,0 +1,20 @@
+package entity
+
+type Role struct {
+	IdRole    uint   `gorm:"column:id_role;primary_key"`
+	Role_name string `gorm:"column:role_name"`
+}
+
+func (Role) TableName() string {
+	return "role"
+}
+
+type RolePermission struct {
+	ID         uint   `gorm:"primary_key"`
+	Role_id    uint   `gorm:"column:role_id"`
+	Permission string `gorm:"column:permission"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permission"
+}
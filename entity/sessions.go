@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// Session one login of an actor, every refresh token rotated from that
+// login belongs to it and revoking it logs the whole family out
+type Session struct {
+	ID        string     `gorm:"primary_key;column:id"`
+	Actor_id  uint       `gorm:"column:actor_id"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+func (Session) TableName() string {
+	return "session"
+}
+
+// RefreshToken only the SHA-256 of the token is stored, UsedAt is set once
+// it has been exchanged for a new pair
+type RefreshToken struct {
+	ID         uint       `gorm:"primary_key"`
+	Session_id string     `gorm:"column:session_id"`
+	Token_hash string     `gorm:"column:token_hash"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at"`
+	UsedAt     *time.Time `gorm:"column:used_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	Session    Session    `gorm:"foreignKey:Session_id"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_token"
+}
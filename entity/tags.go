@@ -0,0 +1,56 @@
+package entity
+
+import "time"
+
+type Tag struct {
+	ID        uint   `gorm:"primary_key"`
+	Name      string `gorm:"column:name"`
+	Color     string `gorm:"column:color"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Tag) TableName() string {
+	return "tag"
+}
+
+// CustomerTag row of the many-to-many relation between customer and tag
+type CustomerTag struct {
+	Customer_id uint `gorm:"column:customer_id;primaryKey;autoIncrement:false"`
+	Tag_id      uint `gorm:"column:tag_id;primaryKey;autoIncrement:false"`
+	CreatedAt   time.Time
+}
+
+func (CustomerTag) TableName() string {
+	return "customer_tag"
+}
+
+// Segment saved filter of the customer list, its customers are found again
+// every time it is used
+type Segment struct {
+	ID         uint          `gorm:"primary_key"`
+	Name       string        `gorm:"column:name"`
+	Filter     SegmentFilter `gorm:"column:filter;serializer:json"`
+	Created_by *uint         `gorm:"column:created_by"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (Segment) TableName() string {
+	return "segment"
+}
+
+// SegmentFilter criteria of a segment, names match the customer list query,
+// Created_within_days counts back from the moment the segment is used,
+// customers must have every one of Tag_ids and Custom_fields holds cf
+// filters by field name
+type SegmentFilter struct {
+	First_name          string            `json:"first_name,omitempty"`
+	Last_name           string            `json:"last_name,omitempty"`
+	Email               string            `json:"email,omitempty"`
+	Created_from        string            `json:"created_from,omitempty"`
+	Created_to          string            `json:"created_to,omitempty"`
+	Created_within_days int               `json:"created_within_days,omitempty"`
+	Tag_ids             []uint            `json:"tag_ids,omitempty"`
+	Custom_fields       map[string]string `json:"custom_fields,omitempty"`
+}
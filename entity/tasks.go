@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Priorities of a task
+const (
+	TaskPriorityLow    = "low"
+	TaskPriorityNormal = "normal"
+	TaskPriorityHigh   = "high"
+)
+
+// Statuses of a task
+const (
+	TaskStatusOpen       = "open"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusDone       = "done"
+	TaskStatusCancelled  = "cancelled"
+)
+
+// TaskOpenStatuses statuses of a task still to be done, only those become
+// overdue or get reminders
+var TaskOpenStatuses = []string{TaskStatusOpen, TaskStatusInProgress}
+
+// Task to do assigned to an actor, optionally about a customer or one of its
+// deals. Assignee_id is nil once the assigned actor is purged, Overdue_at and
+// Reminded_at record when the reminder job noticed the task was past due or
+// sent its reminder
+type Task struct {
+	ID           uint       `gorm:"primary_key"`
+	Title        string     `gorm:"column:title"`
+	Description  string     `gorm:"column:description"`
+	Due_at       time.Time  `gorm:"column:due_at"`
+	Remind_at    *time.Time `gorm:"column:remind_at"`
+	Priority     string     `gorm:"column:priority"`
+	Status       string     `gorm:"column:status"`
+	Assignee_id  *uint      `gorm:"column:assignee_id"`
+	Customer_id  *uint      `gorm:"column:customer_id"`
+	Deal_id      *uint      `gorm:"column:deal_id"`
+	Created_by   *uint      `gorm:"column:created_by"`
+	Completed_at *time.Time `gorm:"column:completed_at"`
+	Reminded_at  *time.Time `gorm:"column:reminded_at"`
+	Overdue_at   *time.Time `gorm:"column:overdue_at"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (Task) TableName() string {
+	return "task"
+}
+
+// TaskOpen whether status is one of TaskOpenStatuses
+func TaskOpen(status string) bool {
+	return status == TaskStatusOpen || status == TaskStatusInProgress
+}
@@ -1,20 +1,53 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
 
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/modules/accounts"
 	"github.com/alkamalp/crm-golang/modules/actors"
+	"github.com/alkamalp/crm-golang/modules/approvals"
+	"github.com/alkamalp/crm-golang/modules/audits"
 	"github.com/alkamalp/crm-golang/modules/customers"
+	"github.com/alkamalp/crm-golang/modules/deals"
+	"github.com/alkamalp/crm-golang/modules/sessions"
+	"github.com/alkamalp/crm-golang/modules/tags"
+	"github.com/alkamalp/crm-golang/modules/tasks"
+	"github.com/alkamalp/crm-golang/utils/config"
 	"github.com/alkamalp/crm-golang/utils/db"
+	"github.com/alkamalp/crm-golang/utils/job"
+	"github.com/alkamalp/crm-golang/utils/migration"
+	"github.com/alkamalp/crm-golang/utils/notify"
+	"github.com/alkamalp/crm-golang/utils/storage"
+	"github.com/alkamalp/crm-golang/utils/token"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal("invalid configuration: ", err)
+	}
+
+	if cfg.Log.Level == "debug" {
+		gin.SetMode(gin.DebugMode)
+	} else {
+		gin.SetMode(gin.ReleaseMode)
+	}
 	router := gin.New()
+	router.Use(middleware.RequestID, requestLogger(cfg.Log), gin.Recovery())
 
 	// open connection db
-	dbCrud := db.GormMysql()
+	dbCrud, err := db.Open(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	//check connection
 	checkdb, err := dbCrud.DB()
@@ -24,21 +57,170 @@ func main() {
 
 	//ping to database
 	errconn := checkdb.Ping()
-	if err != nil {
+	if errconn != nil {
 		log.Fatal(errconn)
 	}
 
 	fmt.Println("database connected..!")
 
-	actorHandler := actors.NewRouter(dbCrud)
+	migrator, err := migration.New(dbCrud, migration.All())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if args := flag.CommandLine.Args(); len(args) > 0 && args[0] == "migrate" {
+		err = runMigrate(migrator, args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if cfg.Database.AutoMigrate {
+		applied, err := migrator.Up()
+		printMigrations("applied", applied)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	issuer, err := token.NewIssuer(cfg.JWT)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	actorHandler := actors.NewRouter(dbCrud, cfg, issuer)
 	actorHandler.Handle(router)
 
-	customerHandler := customers.NewRouter(dbCrud)
+	sessionHandler := sessions.NewRouter(dbCrud, cfg, issuer)
+	sessionHandler.Handle(router)
+
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	customerHandler := customers.NewRouter(dbCrud, cfg, issuer, store)
 	customerHandler.Handle(router)
 
-	errRouter := router.Run(":8081")
+	approvalHandler := approvals.NewRouter(dbCrud, cfg, issuer)
+	approvalHandler.Handle(router)
+
+	tagHandler := tags.NewRouter(dbCrud, cfg, issuer)
+	tagHandler.Handle(router)
+
+	accountHandler := accounts.NewRouter(dbCrud, cfg, issuer)
+	accountHandler.Handle(router)
+
+	dealHandler := deals.NewRouter(dbCrud, cfg, issuer)
+	dealHandler.Handle(router)
+
+	taskHandler := tasks.NewRouter(dbCrud, cfg, issuer)
+	taskHandler.Handle(router)
+
+	auditHandler := audits.NewRouter(dbCrud, cfg, issuer)
+	auditHandler.Handle(router)
+
+	if cfg.Trash.Retention > 0 {
+		go job.Every(context.Background(), "purge customers", cfg.Trash.PurgeInterval,
+			customers.NewPurgeJob(dbCrud, store, cfg.Trash.Retention))
+		go job.Every(context.Background(), "purge actors", cfg.Trash.PurgeInterval,
+			actors.NewPurgeJob(dbCrud, cfg.Trash.Retention))
+	}
+	if cfg.Reminder.Interval > 0 {
+		notifier, err := notify.New(cfg.Reminder)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go job.Every(context.Background(), "task reminders", cfg.Reminder.Interval,
+			tasks.NewReminderJob(dbCrud, notifier))
+	}
+	if cfg.Duplicates.ScanInterval > 0 {
+		go job.Every(context.Background(), "duplicate scan", cfg.Duplicates.ScanInterval,
+			customers.NewDuplicateScanJob(dbCrud))
+	}
+
+	errRouter := router.Run(cfg.Server.Address)
 	if errRouter != nil {
 		fmt.Println("error running server", errRouter)
 		return
 	}
 }
+
+// runMigrate handle "migrate up", "migrate down [steps]" and "migrate status"
+func runMigrate(migrator migration.Migrator, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up | down [steps] | status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		printMigrations("applied", applied)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		rolledBack, err := migrator.Down(steps)
+		printMigrations("rolled back", rolledBack)
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown migrate command %q", args[0])
+}
+
+func printMigrations(action string, migrations []migration.Migration) {
+	for _, m := range migrations {
+		fmt.Printf("%s %04d %s\n", action, m.Version, m.Name)
+	}
+}
+
+// requestLogger gin access log, warn and error levels only log failed requests
+func requestLogger(cfg config.Log) gin.HandlerFunc {
+	skip := func(param gin.LogFormatterParams) bool {
+		return (cfg.Level == "warn" && param.StatusCode < 400) ||
+			(cfg.Level == "error" && param.StatusCode < 500)
+	}
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		if skip(param) {
+			return ""
+		}
+		if cfg.Format == "json" {
+			line, _ := json.Marshal(map[string]any{
+				"time":       param.TimeStamp,
+				"status":     param.StatusCode,
+				"method":     param.Method,
+				"path":       param.Path,
+				"ip":         param.ClientIP,
+				"latency":    param.Latency.String(),
+				"error":      param.ErrorMessage,
+				"request_id": param.Keys["RequestId"],
+			})
+			return string(line) + "\n"
+		}
+		return fmt.Sprintf("%s | %3d | %13v | %15s | %-7s %s\n",
+			param.TimeStamp.Format("2006/01/02 15:04:05"),
+			param.StatusCode,
+			param.Latency,
+			param.ClientIP,
+			param.Method,
+			param.Path,
+		)
+	})
+}
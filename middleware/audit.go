@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// auditIgnored columns that change on every write and say nothing on their own
+var auditIgnored = map[string]bool{"updated_at": true}
+
+type Audit struct {
+	auditRepo repository.AuditInterfaceRepo
+}
+
+func NewAudit(dbCrud *gorm.DB) Audit {
+	return Audit{
+		auditRepo: repository.NewAuditLog(dbCrud),
+	}
+}
+
+// AuditTrail actor, request id and client ip of the request, for the entries
+// written by a use case itself
+func AuditTrail(c *gin.Context) entity.AuditLog {
+	trail := entity.AuditLog{
+		Request_id: c.GetString("RequestId"),
+		Client_ip:  c.ClientIP(),
+	}
+	if actorId, ok := c.Get("ActorId"); ok {
+		id := actorId.(uint)
+		trail.Actor_id = &id
+	}
+	return trail
+}
+
+// Record write an audit entry of action on the entityType record named by
+// the param path parameter once the handler succeeded, param is "username"
+// for routes addressing actors by name and empty for creates, whose id is
+// read from the response. Before and After only hold the changed columns,
+// a request that changed nothing leaves no entry. The actor is only known
+// after Auth
+func (a Audit) Record(action string, entityType string, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var id uint
+		var before map[string]any
+		if param != "" {
+			var value any = c.Param(param)
+			column := "username"
+			if param != "username" {
+				// the handler rejects ids that are not numbers
+				value, _ = strconv.ParseUint(c.Param(param), 10, 64)
+				column = "id"
+			}
+			var err error
+			id, before, err = a.auditRepo.Snapshot(entityType, column, value)
+			if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+				log.Printf("audit %s %s: %v", action, entityType, err)
+			}
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		if param == "" {
+			c.Writer = recorder
+		}
+		c.Next()
+		status := c.Writer.Status()
+		if status < 200 || status >= 300 {
+			return
+		}
+
+		entry := AuditTrail(c)
+		entry.Action = action
+		entry.Entity_type = entityType
+		var after map[string]any
+		if id == 0 {
+			id = createdId(recorder.body.Bytes())
+		}
+		if id != 0 {
+			var err error
+			_, after, err = a.auditRepo.Snapshot(entityType, "id", id)
+			if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+				log.Printf("audit %s %s %d: %v", action, entityType, id, err)
+				return
+			}
+		} else if param == "" {
+			// nothing to read back, an import for instance, so the
+			// response tells what happened
+			after = responseData(recorder.body.Bytes())
+		}
+		before, after = auditDiff(before, after)
+		if before == nil && after == nil {
+			return
+		}
+		entry.Entity_id = id
+
+		err := setAuditJson(&entry, before, after)
+		if err == nil {
+			err = a.auditRepo.CreateAuditLog(&entry)
+		}
+		if err != nil {
+			log.Printf("audit %s %s %d: %v", action, entityType, id, err)
+		}
+	}
+}
+
+// auditDiff the columns that differ between before and after, all of them
+// when the record did not exist on one side
+func auditDiff(before map[string]any, after map[string]any) (map[string]any, map[string]any) {
+	if before == nil || after == nil {
+		return before, after
+	}
+	changedBefore, changedAfter := map[string]any{}, map[string]any{}
+	for key, value := range after {
+		if auditIgnored[key] {
+			continue
+		}
+		previous, ok := before[key]
+		if ok && sameJson(previous, value) {
+			continue
+		}
+		changedBefore[key] = previous
+		changedAfter[key] = value
+	}
+	for key, previous := range before {
+		if _, ok := after[key]; !ok && !auditIgnored[key] {
+			changedBefore[key] = previous
+			changedAfter[key] = nil
+		}
+	}
+	if len(changedAfter) == 0 {
+		return nil, nil
+	}
+	return changedBefore, changedAfter
+}
+
+func sameJson(a any, b any) bool {
+	rawA, errA := json.Marshal(a)
+	rawB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(rawA, rawB)
+}
+
+func setAuditJson(entry *entity.AuditLog, before map[string]any, after map[string]any) error {
+	for _, side := range []struct {
+		values map[string]any
+		target **string
+	}{{before, &entry.Before}, {after, &entry.After}} {
+		if side.values == nil {
+			continue
+		}
+		raw, err := json.Marshal(side.values)
+		if err != nil {
+			return fmt.Errorf("encode audit values: %w", err)
+		}
+		encoded := string(raw)
+		*side.target = &encoded
+	}
+	return nil
+}
+
+// responseData the data object of a JSON response, nil when there is none
+func responseData(body []byte) map[string]any {
+	var response struct {
+		Data map[string]any `json:"data"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		return nil
+	}
+	return response.Data
+}
+
+// createdId id of the record in the data of a JSON response, entities
+// render it as ID and DTOs as id
+func createdId(body []byte) uint {
+	data := responseData(body)
+	for _, key := range []string{"ID", "id"} {
+		if id, ok := data[key].(float64); ok && id > 0 {
+			return uint(id)
+		}
+	}
+	return 0
+}
+
+// bodyRecorder keeps a copy of the response body
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func performAudited(handler gin.HandlerFunc, method string, path string, route string, record gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID)
+	router.Handle(method, route, func(c *gin.Context) {
+		c.Set("ActorId", uint(5))
+	}, record, handler)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(RequestIdHeader, "req-1")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuditRecord_Update(t *testing.T) {
+
+	mockRepo := mocks.NewAuditInterfaceRepo(t)
+	audit := Audit{auditRepo: mockRepo}
+
+	mockRepo.On("Snapshot", "customer", "id", uint64(3)).Return(uint(3), map[string]any{
+		"id": 3, "first_name": "Jon", "last_name": "Doe", "updated_at": "old",
+	}, nil).Once()
+	mockRepo.On("Snapshot", "customer", "id", uint(3)).Return(uint(3), map[string]any{
+		"id": 3, "first_name": "John", "last_name": "Doe", "updated_at": "new",
+	}, nil).Once()
+	mockRepo.On("CreateAuditLog", mock.MatchedBy(func(entry *entity.AuditLog) bool {
+		return *entry.Actor_id == 5 && entry.Action == entity.AuditActionUpdate && entry.Entity_type == "customer" &&
+			entry.Entity_id == 3 && entry.Request_id == "req-1" &&
+			*entry.Before == `{"first_name":"Jon"}` && *entry.After == `{"first_name":"John"}`
+	})).Return(nil)
+
+	w := performAudited(func(c *gin.Context) { c.Status(http.StatusOK) },
+		http.MethodPut, "/customer/3", "/customer/:id", audit.Record(entity.AuditActionUpdate, "customer", "id"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-1", w.Header().Get(RequestIdHeader))
+}
+
+func TestAuditRecord_Create(t *testing.T) {
+
+	mockRepo := mocks.NewAuditInterfaceRepo(t)
+	audit := Audit{auditRepo: mockRepo}
+
+	mockRepo.On("Snapshot", "tag", "id", uint(9)).Return(uint(9), map[string]any{"id": 9, "name": "vip"}, nil)
+	mockRepo.On("CreateAuditLog", mock.MatchedBy(func(entry *entity.AuditLog) bool {
+		return entry.Action == entity.AuditActionCreate && entry.Entity_id == 9 &&
+			entry.Before == nil && *entry.After == `{"id":9,"name":"vip"}`
+	})).Return(nil)
+
+	performAudited(func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": gin.H{"ID": 9}}) },
+		http.MethodPost, "/tag", "/tag", audit.Record(entity.AuditActionCreate, "tag", ""))
+}
+
+func TestAuditRecord_NothingToRecord(t *testing.T) {
+
+	mockRepo := mocks.NewAuditInterfaceRepo(t)
+	audit := Audit{auditRepo: mockRepo}
+
+	snapshot := map[string]any{"id": 3, "name": "vip"}
+	mockRepo.On("Snapshot", "tag", "id", mock.Anything).Return(uint(3), snapshot, nil)
+
+	record := audit.Record(entity.AuditActionUpdate, "tag", "id")
+	performAudited(func(c *gin.Context) { c.Status(http.StatusOK) }, http.MethodPut, "/tag/3", "/tag/:id", record)
+	performAudited(func(c *gin.Context) { c.Status(http.StatusConflict) }, http.MethodPut, "/tag/3", "/tag/:id", record)
+
+	mockRepo.AssertNotCalled(t, "CreateAuditLog", mock.Anything)
+}
+
+func TestRequestID_ReplacesInvalidId(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", RequestID, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIdHeader, "bad id\n")
+	router.ServeHTTP(w, req)
+
+	assert.Len(t, w.Header().Get(RequestIdHeader), 32)
+}
@@ -1,39 +1,77 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
 	"strings"
 
 	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/token"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
+	"gorm.io/gorm"
 )
 
-func Auth(c *gin.Context) {
+// ErrUnknownActor the actor named by a valid token no longer exists
+var ErrUnknownActor = apperror.Unauthorized("acting actor not found")
+
+type Authentication struct {
+	issuer      token.Issuer
+	sessionRepo repository.SessionInterfaceRepo
+}
+
+func NewAuthentication(dbCrud *gorm.DB, issuer token.Issuer) Authentication {
+	return Authentication{
+		issuer:      issuer,
+		sessionRepo: repository.NewSession(dbCrud),
+	}
+}
+
+func (a Authentication) Auth(c *gin.Context) {
 
 	// Token yang diterima
 	receivedToken := c.GetHeader("Authorization")
 	signedToken := strings.Split(receivedToken, " ")
+	if len(signedToken) != 2 {
+		c.JSON(401, dto.DefaultErrorInvalidDataWithMessage("token tidak valid"))
+		c.Abort()
+		return
+	}
 
 	// Verifikasi token dengan kunci rahasia
-	token, err := jwt.Parse(signedToken[1], func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method")
-		}
-		return []byte("secret-key"), nil
-	})
-	if err != nil {
+	claims, err := a.issuer.Parse(signedToken[1])
+	if err != nil || claims.SessionId == "" {
 		c.JSON(401, dto.DefaultErrorInvalidDataWithMessage("token tidak valid"))
 		c.Abort()
 		return
 	}
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		// Token valid, akses klaim-klaim yang ada
-		c.Set("Role", claims["sub"])
-	} else {
-		c.JSON(401, dto.DefaultErrorInvalidDataWithMessage("token tidak valid"))
+
+	// token dari sesi yang sudah logout atau dicabut ditolak
+	session, err := a.sessionRepo.GetSessionById(claims.SessionId)
+	if err != nil && !errors.Is(err, apperror.ErrNotFound) {
+		c.JSON(dto.NewErrorResponse(err))
+		c.Abort()
+		return
+	}
+	if err != nil || session.RevokedAt != nil {
+		c.JSON(401, dto.DefaultErrorInvalidDataWithMessage("token sudah dicabut"))
 		c.Abort()
 		return
 	}
+
+	c.Set("Role", claims.Role)
+	c.Set("Username", claims.Name)
+	c.Set("SessionId", claims.SessionId)
+	c.Set("ActorId", session.Actor_id)
 	c.Next()
 }
+
+// ActingActor actor named by the token of the request
+func ActingActor(actorRepo repository.ActorInterfaceRepo, actorName string) (entity.Actor, error) {
+	actor, err := actorRepo.GetActorByUsername(actorName)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return entity.Actor{}, ErrUnknownActor
+	}
+	return actor, err
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testJWT = config.JWT{
+	Secret:     "test-secret-0123456789",
+	AccessTTL:  time.Minute,
+	RefreshTTL: time.Hour,
+}
+
+func newTestIssuer(t *testing.T, cfg config.JWT) token.Issuer {
+	issuer, err := token.NewIssuer(cfg)
+	assert.NoError(t, err)
+	return issuer
+}
+
+func performAuthenticated(auth Authentication, authorization string) (*httptest.ResponseRecorder, *gin.Context) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var passed *gin.Context
+	router.GET("/", auth.Auth, func(c *gin.Context) {
+		passed = c
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	router.ServeHTTP(w, req)
+	return w, passed
+}
+
+func TestAuth(t *testing.T) {
+
+	mockRepo := mocks.NewSessionInterfaceRepo(t)
+
+	auth := Authentication{
+		issuer:      newTestIssuer(t, testJWT),
+		sessionRepo: mockRepo,
+	}
+
+	signed, _, err := auth.issuer.AccessToken(entity.Actor{Username: "admin1", Role_id: 2}, "session-1")
+	assert.NoError(t, err)
+	mockRepo.On("GetSessionById", "session-1").Return(entity.Session{ID: "session-1", Actor_id: 7}, nil)
+
+	w, c := performAuthenticated(auth, "Bearer "+signed)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(2), c.MustGet("Role"))
+	assert.Equal(t, "admin1", c.GetString("Username"))
+	assert.Equal(t, "session-1", c.GetString("SessionId"))
+	assert.Equal(t, uint(7), c.MustGet("ActorId"))
+}
+
+func TestAuth_RevokedSession(t *testing.T) {
+
+	mockRepo := mocks.NewSessionInterfaceRepo(t)
+
+	auth := Authentication{
+		issuer:      newTestIssuer(t, testJWT),
+		sessionRepo: mockRepo,
+	}
+
+	signed, _, err := auth.issuer.AccessToken(entity.Actor{Username: "admin1", Role_id: 2}, "session-1")
+	assert.NoError(t, err)
+	revokedAt := time.Now()
+	mockRepo.On("GetSessionById", "session-1").Return(entity.Session{ID: "session-1", RevokedAt: &revokedAt}, nil)
+
+	w, _ := performAuthenticated(auth, "Bearer "+signed)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuth_InvalidToken(t *testing.T) {
+
+	mockRepo := mocks.NewSessionInterfaceRepo(t)
+
+	auth := Authentication{
+		issuer:      newTestIssuer(t, testJWT),
+		sessionRepo: mockRepo,
+	}
+
+	otherKey := testJWT
+	otherKey.Secret = "another-secret-0123456789"
+	forged, _, err := newTestIssuer(t, otherKey).AccessToken(entity.Actor{Role_id: 1}, "session-1")
+	assert.NoError(t, err)
+	expiredConfig := testJWT
+	expiredConfig.AccessTTL = -time.Minute
+	expired, _, err := newTestIssuer(t, expiredConfig).AccessToken(entity.Actor{Role_id: 1}, "session-1")
+	assert.NoError(t, err)
+
+	for _, header := range []string{"", "Bearer", "Bearer " + forged, "Bearer " + expired} {
+		w, _ := performAuthenticated(auth, header)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, header)
+	}
+}
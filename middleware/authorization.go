@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Permissions granted to roles through the role_permission table
+const (
+	PermissionActorRead      = "actor:read"
+	PermissionActorUpdate    = "actor:update"
+	PermissionActorDelete    = "actor:delete"
+	PermissionCustomerCreate = "customer:create"
+	PermissionCustomerRead   = "customer:read"
+	PermissionCustomerUpdate = "customer:update"
+	PermissionCustomerDelete = "customer:delete"
+	PermissionCustomerMerge  = "customer:merge"
+	PermissionApprovalManage = "approval:manage"
+	PermissionTagManage      = "tag:manage"
+	PermissionSegmentManage  = "segment:manage"
+	PermissionFieldManage    = "field:manage"
+	PermissionAccountCreate  = "account:create"
+	PermissionAccountRead    = "account:read"
+	PermissionAccountUpdate  = "account:update"
+	PermissionAccountDelete  = "account:delete"
+	PermissionDealCreate     = "deal:create"
+	PermissionDealRead       = "deal:read"
+	PermissionDealUpdate     = "deal:update"
+	PermissionDealDelete     = "deal:delete"
+	PermissionTaskCreate     = "task:create"
+	PermissionTaskRead       = "task:read"
+	PermissionTaskUpdate     = "task:update"
+	PermissionTaskDelete     = "task:delete"
+	PermissionAuditRead      = "audit:read"
+)
+
+type Authorization struct {
+	roleRepo repository.RoleInterfaceRepo
+}
+
+func NewAuthorization(dbCrud *gorm.DB) Authorization {
+	return Authorization{
+		roleRepo: repository.NewRole(dbCrud),
+	}
+}
+
+// RequirePermission only let through actors whose role is granted permission,
+// must be used after Auth
+func (a Authorization) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roleId, ok := roleIdFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.DefaultForbiddenResponse())
+			return
+		}
+		permissions, err := a.roleRepo.GetPermissionsByRoleId(roleId)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+			return
+		}
+		for _, granted := range permissions {
+			if granted == permission {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.DefaultForbiddenResponse())
+	}
+}
+
+// roleIdFromContext read the Role_id that Auth stored from the "sub" claim
+func roleIdFromContext(c *gin.Context) (uint, bool) {
+	value, ok := c.Get("Role")
+	if !ok {
+		return 0, false
+	}
+	role, ok := value.(uint)
+	return role, ok
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func performAuthorized(handler gin.HandlerFunc, role any) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		if role != nil {
+			c.Set("Role", role)
+		}
+		c.Next()
+	}, handler, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequirePermission(t *testing.T) {
+
+	mockRepo := mocks.NewRoleInterfaceRepo(t)
+
+	authorization := Authorization{
+		roleRepo: mockRepo,
+	}
+
+	mockRepo.On("GetPermissionsByRoleId", uint(2)).Return([]string{PermissionCustomerRead}, nil)
+
+	w := performAuthorized(authorization.RequirePermission(PermissionCustomerRead), uint(2))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performAuthorized(authorization.RequirePermission(PermissionActorDelete), uint(2))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRequirePermission_NoRole(t *testing.T) {
+
+	mockRepo := mocks.NewRoleInterfaceRepo(t)
+
+	authorization := Authorization{
+		roleRepo: mockRepo,
+	}
+
+	w := performAuthorized(authorization.RequirePermission(PermissionCustomerRead), nil)
+
+	mockRepo.AssertNotCalled(t, "GetPermissionsByRoleId")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRequirePermission_Error(t *testing.T) {
+
+	mockRepo := mocks.NewRoleInterfaceRepo(t)
+
+	authorization := Authorization{
+		roleRepo: mockRepo,
+	}
+
+	mockRepo.On("GetPermissionsByRoleId", uint(1)).Return(nil, errors.New("connection refused"))
+
+	w := performAuthorized(authorization.RequirePermission(PermissionCustomerRead), uint(1))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
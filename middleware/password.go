@@ -12,3 +12,9 @@ func HashPassword(password string) (string, error) {
 	return string(hashedPassword), nil
 
 }
+
+// CheckPassword compare plain password with hash produced by HashPassword
+func CheckPassword(password string, hashedPassword string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	return err == nil
+}
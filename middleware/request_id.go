@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIdHeader carries the id of a request in and out
+const RequestIdHeader = "X-Request-Id"
+
+// requestIdPattern incoming ids that are kept, anything else is replaced
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID give every request an id, the one sent by the client when it is
+// sane, available as "RequestId" and echoed in the response header
+func RequestID(c *gin.Context) {
+	requestId := c.GetHeader(RequestIdHeader)
+	if !requestIdPattern.MatchString(requestId) {
+		requestId = newRequestId()
+	}
+	c.Set("RequestId", requestId)
+	c.Header(RequestIdHeader, requestId)
+	c.Next()
+}
+
+func newRequestId() string {
+	raw := make([]byte, 16)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
@@ -0,0 +1,170 @@
+package accounts
+
+import (
+	"net/url"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+type ControllerAccount interface {
+	CreateAccount(req AccountParam) (FindAccount, error)
+	GetAccountById(id uint) (FindAccount, error)
+	ListAccounts(req AccountListParam, requestUrl url.URL) (ListAccount, error)
+	UpdateAccount(req AccountParam, id uint) (FindAccount, error)
+	DeleteAccount(id uint, actorName string) (dto.ResponseMeta, error)
+	ListContacts(id uint, req ContactListParam, requestUrl url.URL) (ListContact, error)
+	LinkContacts(id uint, req ContactsParam) (FindContactsChanged, error)
+	UnlinkContacts(id uint, req ContactsParam) (FindContactsChanged, error)
+	MoveContacts(id uint, req MoveContactsParam) (FindContactsChanged, error)
+}
+
+type controllerAccount struct {
+	accountUseCase UseCaseAccount
+}
+
+func (uc controllerAccount) CreateAccount(req AccountParam) (FindAccount, error) {
+	account, err := uc.accountUseCase.CreateAccount(req)
+	if err != nil {
+		return FindAccount{}, err
+	}
+	res := FindAccount{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success create account",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: account,
+	}
+	return res, nil
+}
+
+func (uc controllerAccount) GetAccountById(id uint) (FindAccount, error) {
+	account, err := uc.accountUseCase.GetAccountById(id)
+	if err != nil {
+		return FindAccount{}, err
+	}
+	res := FindAccount{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get account",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: account,
+	}
+	return res, nil
+}
+
+func (uc controllerAccount) ListAccounts(req AccountListParam, requestUrl url.URL) (ListAccount, error) {
+	page, err := uc.accountUseCase.ListAccounts(req)
+	if err != nil {
+		return ListAccount{}, err
+	}
+	res := ListAccount{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get accounts",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Accounts,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Account{}
+	}
+	return res, nil
+}
+
+func (uc controllerAccount) UpdateAccount(req AccountParam, id uint) (FindAccount, error) {
+	account, err := uc.accountUseCase.UpdateAccount(req, id)
+	if err != nil {
+		return FindAccount{}, err
+	}
+	res := FindAccount{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success update account",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: account,
+	}
+	return res, nil
+}
+
+func (uc controllerAccount) DeleteAccount(id uint, actorName string) (dto.ResponseMeta, error) {
+	err := uc.accountUseCase.DeleteAccount(id, actorName)
+	if err != nil {
+		return dto.ResponseMeta{}, err
+	}
+	return dto.ResponseMeta{
+		Success:      true,
+		MessageTitle: "Success delete account",
+		Message:      "Success",
+		ResponseTime: "",
+	}, nil
+}
+
+func (uc controllerAccount) ListContacts(id uint, req ContactListParam, requestUrl url.URL) (ListContact, error) {
+	page, err := uc.accountUseCase.ListContacts(id, req)
+	if err != nil {
+		return ListContact{}, err
+	}
+	res := ListContact{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get contacts",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Customers,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Customer{}
+	}
+	return res, nil
+}
+
+func (uc controllerAccount) LinkContacts(id uint, req ContactsParam) (FindContactsChanged, error) {
+	changed, err := uc.accountUseCase.LinkContacts(id, req)
+	if err != nil {
+		return FindContactsChanged{}, err
+	}
+	return contactsChanged("Success link contacts", changed), nil
+}
+
+func (uc controllerAccount) UnlinkContacts(id uint, req ContactsParam) (FindContactsChanged, error) {
+	changed, err := uc.accountUseCase.UnlinkContacts(id, req)
+	if err != nil {
+		return FindContactsChanged{}, err
+	}
+	return contactsChanged("Success unlink contacts", changed), nil
+}
+
+func (uc controllerAccount) MoveContacts(id uint, req MoveContactsParam) (FindContactsChanged, error) {
+	changed, err := uc.accountUseCase.MoveContacts(id, req)
+	if err != nil {
+		return FindContactsChanged{}, err
+	}
+	return contactsChanged("Success move contacts", changed), nil
+}
+
+func contactsChanged(title string, changed int64) FindContactsChanged {
+	return FindContactsChanged{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: title,
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: ContactsChanged{Changed: changed},
+	}
+}
@@ -0,0 +1,66 @@
+package accounts
+
+import (
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+// AccountParam Domain is the web domain of the company e.g. acme.com
+type AccountParam struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	Domain   string `json:"domain" binding:"omitempty,fqdn,max=255"`
+	Industry string `json:"industry" binding:"max=255"`
+}
+
+type AccountListParam struct {
+	Page  int    `form:"page" binding:"min=0"`
+	Limit int    `form:"limit" binding:"min=0"`
+	Name  string `form:"name" binding:"max=255"`
+}
+
+type ContactListParam struct {
+	Page  int `form:"page" binding:"min=0"`
+	Limit int `form:"limit" binding:"min=0"`
+}
+
+// ContactsParam customers to link to or unlink from an account
+type ContactsParam struct {
+	Customer_ids []uint `json:"customer_ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+// MoveContactsParam without Customer_ids every contact moves
+type MoveContactsParam struct {
+	To_account_id uint   `json:"to_account_id" binding:"required"`
+	Customer_ids  []uint `json:"customer_ids" binding:"max=100,dive,required"`
+}
+
+// AccountContacts contacts an account still has, the details of a blocked
+// delete
+type AccountContacts struct {
+	Contacts int64 `json:"contacts"`
+}
+
+// ContactsChanged number of customers a link, unlink or move changed
+type ContactsChanged struct {
+	Changed int64 `json:"changed"`
+}
+
+type FindAccount struct {
+	dto.ResponseMeta
+	Data entity.Account `json:"data"`
+}
+
+type ListAccount struct {
+	dto.ListResponseMeta
+	Data []entity.Account `json:"data"`
+}
+
+type ListContact struct {
+	dto.ListResponseMeta
+	Data []entity.Customer `json:"data"`
+}
+
+type FindContactsChanged struct {
+	dto.ResponseMeta
+	Data ContactsChanged `json:"data"`
+}
@@ -0,0 +1,190 @@
+package accounts
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RequestHandlerAccount struct {
+	ctr ControllerAccount
+}
+
+func NewAccountRequestHandler(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+) RequestHandlerAccount {
+	return RequestHandlerAccount{
+		ctr: controllerAccount{
+			accountUseCase: useCaseAccount{
+				accountRepo:  repository.NewAccount(dbCrud),
+				customerRepo: repository.NewCustomer(dbCrud),
+				actorRepo:    repository.NewActor(dbCrud),
+				deletePolicy: cfg.Account.DeletePolicy,
+			},
+		}}
+}
+
+func (h RequestHandlerAccount) CreateAccount(c *gin.Context) {
+	request := AccountParam{}
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.CreateAccount(request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerAccount) GetAccountById(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.GetAccountById(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerAccount) ListAccounts(c *gin.Context) {
+	request := AccountListParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.ListAccounts(request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerAccount) UpdateAccount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := AccountParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.UpdateAccount(request, uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerAccount) DeleteAccount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.DeleteAccount(uint(id), c.GetString("Username"))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerAccount) ListContacts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := ContactListParam{}
+	err = c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.ListContacts(uint(id), request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerAccount) LinkContacts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := ContactsParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.LinkContacts(uint(id), request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerAccount) UnlinkContacts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := ContactsParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.UnlinkContacts(uint(id), request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerAccount) MoveContacts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := MoveContactsParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.MoveContacts(uint(id), request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,81 @@
+package accounts
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RouteAccount struct {
+	AccountRequestHandeler RequestHandlerAccount
+	Authentication         middleware.Authentication
+	Authorization          middleware.Authorization
+	Audit                  middleware.Audit
+}
+
+func NewRouter(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+) RouteAccount {
+	return RouteAccount{
+		AccountRequestHandeler: NewAccountRequestHandler(
+			dbCrud,
+			cfg,
+		),
+		Authentication: middleware.NewAuthentication(dbCrud, issuer),
+		Authorization:  middleware.NewAuthorization(dbCrud),
+		Audit:          middleware.NewAudit(dbCrud),
+	}
+}
+
+func (r RouteAccount) Handle(routeVersion *gin.Engine) {
+	basepath := "/account"
+	account := routeVersion.Group(basepath, r.Authentication.Auth)
+
+	account.GET("",
+		r.Authorization.RequirePermission(middleware.PermissionAccountRead),
+		r.AccountRequestHandeler.ListAccounts,
+	)
+	account.POST("",
+		r.Authorization.RequirePermission(middleware.PermissionAccountCreate),
+		r.Audit.Record(entity.AuditActionCreate, "account", ""),
+		r.AccountRequestHandeler.CreateAccount,
+	)
+	account.GET("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionAccountRead),
+		r.AccountRequestHandeler.GetAccountById,
+	)
+	account.PUT("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionAccountUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "account", "id"),
+		r.AccountRequestHandeler.UpdateAccount,
+	)
+	account.DELETE("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionAccountDelete),
+		r.Audit.Record(entity.AuditActionDelete, "account", "id"),
+		r.AccountRequestHandeler.DeleteAccount,
+	)
+	account.GET("/:id/contacts",
+		r.Authorization.RequirePermission(middleware.PermissionAccountRead),
+		r.AccountRequestHandeler.ListContacts,
+	)
+	account.PUT("/:id/contacts",
+		r.Authorization.RequirePermission(middleware.PermissionAccountUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "account", "id"),
+		r.AccountRequestHandeler.LinkContacts,
+	)
+	account.DELETE("/:id/contacts",
+		r.Authorization.RequirePermission(middleware.PermissionAccountUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "account", "id"),
+		r.AccountRequestHandeler.UnlinkContacts,
+	)
+	account.POST("/:id/contacts/move",
+		r.Authorization.RequirePermission(middleware.PermissionAccountUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "account", "id"),
+		r.AccountRequestHandeler.MoveContacts,
+	)
+}
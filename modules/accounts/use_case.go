@@ -0,0 +1,194 @@
+package accounts
+
+import (
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/config"
+)
+
+type UseCaseAccount interface {
+	CreateAccount(req AccountParam) (entity.Account, error)
+	GetAccountById(id uint) (entity.Account, error)
+	ListAccounts(req AccountListParam) (AccountPage, error)
+	UpdateAccount(req AccountParam, id uint) (entity.Account, error)
+	DeleteAccount(id uint, actorName string) error
+	ListContacts(id uint, req ContactListParam) (ContactPage, error)
+	LinkContacts(id uint, req ContactsParam) (int64, error)
+	UnlinkContacts(id uint, req ContactsParam) (int64, error)
+	MoveContacts(id uint, req MoveContactsParam) (int64, error)
+}
+
+var (
+	ErrAccountNameRequired = apperror.Validation("account name must not be blank", nil)
+	ErrMoveToSameAccount   = apperror.Validation("to_account_id must be another account", nil)
+)
+
+// AccountPage one page of accounts
+type AccountPage struct {
+	Accounts []entity.Account
+	Total    int64
+	Page     int
+	Limit    int
+}
+
+// ContactPage one page of the contacts of an account
+type ContactPage struct {
+	Customers []entity.Customer
+	Total     int64
+	Page      int
+	Limit     int
+}
+
+type useCaseAccount struct {
+	accountRepo  repository.AccountInterfaceRepo
+	customerRepo repository.CustomerInterfaceRepo
+	actorRepo    repository.ActorInterfaceRepo
+	// deletePolicy config.AccountDeleteBlock or config.AccountDeleteCascade
+	deletePolicy string
+}
+
+func (uc useCaseAccount) CreateAccount(req AccountParam) (entity.Account, error) {
+	account := entity.Account{
+		Name:      strings.TrimSpace(req.Name),
+		Domain:    strings.ToLower(req.Domain),
+		Industry:  strings.TrimSpace(req.Industry),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if account.Name == "" {
+		return account, ErrAccountNameRequired
+	}
+	err := uc.accountRepo.CreateAccount(&account)
+	return account, err
+}
+
+func (uc useCaseAccount) GetAccountById(id uint) (entity.Account, error) {
+	return uc.accountRepo.GetAccountById(id)
+}
+
+// ListAccounts page of accounts in name order
+func (uc useCaseAccount) ListAccounts(req AccountListParam) (AccountPage, error) {
+	limit, page := dto.PageOf(req.Limit, req.Page)
+	accounts, total, err := uc.accountRepo.ListAccounts(strings.TrimSpace(req.Name), limit, (page-1)*limit)
+	if err != nil {
+		return AccountPage{}, err
+	}
+	return AccountPage{
+		Accounts: accounts,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+func (uc useCaseAccount) UpdateAccount(req AccountParam, id uint) (entity.Account, error) {
+	account := entity.Account{
+		ID:        id,
+		Name:      strings.TrimSpace(req.Name),
+		Domain:    strings.ToLower(req.Domain),
+		Industry:  strings.TrimSpace(req.Industry),
+		UpdatedAt: time.Now(),
+	}
+	if account.Name == "" {
+		return account, ErrAccountNameRequired
+	}
+	err := uc.accountRepo.UpdateAccount(&account, id)
+	if err != nil {
+		return account, err
+	}
+	return uc.accountRepo.GetAccountById(id)
+}
+
+// DeleteAccount with the block policy an account that still has contacts is
+// a conflict, with the cascade policy its contacts go to the trash with it
+func (uc useCaseAccount) DeleteAccount(id uint, actorName string) error {
+	_, err := uc.accountRepo.GetAccountById(id)
+	if err != nil {
+		return err
+	}
+	if uc.deletePolicy == config.AccountDeleteCascade {
+		actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+		if err != nil {
+			return err
+		}
+		_, err = uc.accountRepo.DeleteAccountWithContacts(id, actor.ID)
+		return err
+	}
+
+	contacts, err := uc.accountRepo.CountContacts(id)
+	if err != nil {
+		return err
+	}
+	if contacts > 0 {
+		return apperror.Conflict("account still has contacts, move or unlink them first", AccountContacts{
+			Contacts: contacts,
+		})
+	}
+	return uc.accountRepo.DeleteAccount(id)
+}
+
+// ListContacts page of the customers of an account in id order
+func (uc useCaseAccount) ListContacts(id uint, req ContactListParam) (ContactPage, error) {
+	_, err := uc.accountRepo.GetAccountById(id)
+	if err != nil {
+		return ContactPage{}, err
+	}
+	limit, page := dto.PageOf(req.Limit, req.Page)
+	customers, total, err := uc.customerRepo.ListCustomers(repository.CustomerFilter{
+		Account_id: id,
+		Sort:       []repository.SortField{{Column: "id"}},
+		Limit:      limit,
+		Offset:     (page - 1) * limit,
+	})
+	if err != nil {
+		return ContactPage{}, err
+	}
+	return ContactPage{
+		Customers: customers,
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}, nil
+}
+
+// LinkContacts make customers contacts of the account, taking them from the
+// account they had
+func (uc useCaseAccount) LinkContacts(id uint, req ContactsParam) (int64, error) {
+	_, err := uc.accountRepo.GetAccountById(id)
+	if err != nil {
+		return 0, err
+	}
+	return uc.accountRepo.SetContactsAccount(req.Customer_ids, id)
+}
+
+// UnlinkContacts customers that are not contacts of the account are ignored
+func (uc useCaseAccount) UnlinkContacts(id uint, req ContactsParam) (int64, error) {
+	_, err := uc.accountRepo.GetAccountById(id)
+	if err != nil {
+		return 0, err
+	}
+	return uc.accountRepo.MoveContacts(id, nil, req.Customer_ids)
+}
+
+// MoveContacts reassign contacts of the account to another account, all of
+// them when no customer ids are given
+func (uc useCaseAccount) MoveContacts(id uint, req MoveContactsParam) (int64, error) {
+	if req.To_account_id == id {
+		return 0, ErrMoveToSameAccount
+	}
+	_, err := uc.accountRepo.GetAccountById(id)
+	if err != nil {
+		return 0, err
+	}
+	_, err = uc.accountRepo.GetAccountById(req.To_account_id)
+	if err != nil {
+		return 0, err
+	}
+	return uc.accountRepo.MoveContacts(id, &req.To_account_id, req.Customer_ids)
+}
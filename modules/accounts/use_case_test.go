@@ -0,0 +1,181 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAccount(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo: mockRepo,
+	}
+
+	mockRepo.On("CreateAccount", mock.MatchedBy(func(account *entity.Account) bool {
+		return account.Name == "Acme" && account.Domain == "acme.com"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.Account).ID = 4
+	}).Return(nil)
+
+	account, err := useCase.CreateAccount(AccountParam{Name: " Acme ", Domain: "ACME.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), account.ID)
+}
+
+func TestCreateAccount_BlankName(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo: mockRepo,
+	}
+
+	_, err := useCase.CreateAccount(AccountParam{Name: "  "})
+
+	assert.ErrorIs(t, err, ErrAccountNameRequired)
+}
+
+func TestDeleteAccount_BlockedByContacts(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo:  mockRepo,
+		deletePolicy: config.AccountDeleteBlock,
+	}
+
+	mockRepo.On("GetAccountById", uint(1)).Return(entity.Account{ID: 1}, nil)
+	mockRepo.On("CountContacts", uint(1)).Return(int64(3), nil)
+
+	err := useCase.DeleteAccount(1, "admin1")
+
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	var appErr *apperror.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, AccountContacts{Contacts: 3}, appErr.Details)
+	mockRepo.AssertNotCalled(t, "DeleteAccount", mock.Anything)
+}
+
+func TestDeleteAccount_BlockWithoutContacts(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo:  mockRepo,
+		deletePolicy: config.AccountDeleteBlock,
+	}
+
+	mockRepo.On("GetAccountById", uint(1)).Return(entity.Account{ID: 1}, nil)
+	mockRepo.On("CountContacts", uint(1)).Return(int64(0), nil)
+	mockRepo.On("DeleteAccount", uint(1)).Return(nil)
+
+	err := useCase.DeleteAccount(1, "admin1")
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteAccount_Cascade(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo:  mockRepo,
+		actorRepo:    mockActorRepo,
+		deletePolicy: config.AccountDeleteCascade,
+	}
+
+	mockRepo.On("GetAccountById", uint(1)).Return(entity.Account{ID: 1}, nil)
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockRepo.On("DeleteAccountWithContacts", uint(1), uint(5)).Return(int64(3), nil)
+
+	err := useCase.DeleteAccount(1, "admin1")
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "CountContacts", mock.Anything)
+}
+
+func TestListContacts(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+	mockCustomerRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo:  mockRepo,
+		customerRepo: mockCustomerRepo,
+	}
+
+	customers := []entity.Customer{{ID: 7, First_name: "John"}}
+	mockRepo.On("GetAccountById", uint(2)).Return(entity.Account{ID: 2}, nil)
+	mockCustomerRepo.On("ListCustomers", repository.CustomerFilter{
+		Account_id: 2,
+		Sort:       []repository.SortField{{Column: "id"}},
+		Limit:      10,
+		Offset:     10,
+	}).Return(customers, int64(11), nil)
+
+	page, err := useCase.ListContacts(2, ContactListParam{Page: 2, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, customers, page.Customers)
+	assert.Equal(t, int64(11), page.Total)
+}
+
+func TestMoveContacts(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo: mockRepo,
+	}
+
+	to := uint(3)
+	mockRepo.On("GetAccountById", uint(2)).Return(entity.Account{ID: 2}, nil)
+	mockRepo.On("GetAccountById", to).Return(entity.Account{ID: to}, nil)
+	mockRepo.On("MoveContacts", uint(2), &to, []uint{7, 8}).Return(int64(2), nil)
+
+	moved, err := useCase.MoveContacts(2, MoveContactsParam{To_account_id: to, Customer_ids: []uint{7, 8}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), moved)
+}
+
+func TestMoveContacts_SameAccount(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo: mockRepo,
+	}
+
+	_, err := useCase.MoveContacts(2, MoveContactsParam{To_account_id: 2})
+
+	assert.ErrorIs(t, err, ErrMoveToSameAccount)
+}
+
+func TestMoveContacts_UnknownTarget(t *testing.T) {
+
+	mockRepo := mocks.NewAccountInterfaceRepo(t)
+
+	useCase := useCaseAccount{
+		accountRepo: mockRepo,
+	}
+
+	mockRepo.On("GetAccountById", uint(2)).Return(entity.Account{ID: 2}, nil)
+	mockRepo.On("GetAccountById", uint(9)).Return(entity.Account{}, apperror.NotFound("account not found"))
+
+	_, err := useCase.MoveContacts(2, MoveContactsParam{To_account_id: 9})
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	mockRepo.AssertNotCalled(t, "MoveContacts", mock.Anything, mock.Anything, mock.Anything)
+}
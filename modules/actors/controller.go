@@ -1,22 +1,26 @@
 package actors
 
 import (
-	"time"
+	"net/url"
 
 	"github.com/alkamalp/crm-golang/dto"
-	"github.com/golang-jwt/jwt"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/modules/sessions"
 )
 
 type ControllerActor interface {
 	CreateActor(req ActorParam) (any, error)
 	GetActorById(id uint) (FindActor, error)
 	UpdateActor(req ActorParam, id uint) (any, error)
-	DeleteActor(username string) (any, error)
+	DeleteActor(username string, actorName string) (any, error)
 	LoginActor(req ActorParam) (SuccessLogin, error)
+	ListTrash(req TrashListParam, requestUrl url.URL) (ListActor, error)
+	RestoreActor(username string) (FindActor, error)
 }
 
 type controllerActor struct {
-	actorUseCase UseCaseActor
+	actorUseCase   UseCaseActor
+	sessionUseCase sessions.UseCaseSession
 }
 
 func (uc controllerActor) CreateActor(req ActorParam) (any, error) {
@@ -32,9 +36,9 @@ func (uc controllerActor) CreateActor(req ActorParam) (any, error) {
 			Message:      "Success Register",
 			ResponseTime: "",
 		},
-		Data: ActorParam{
+		Data: CreatedActor{
+			ID:       actor.ID,
 			Username: actor.Username,
-			Password: actor.Password,
 			Role_id:  actor.Role_id,
 			Verified: actor.Verified,
 			Active:   actor.Active,
@@ -72,9 +76,9 @@ func (uc controllerActor) UpdateActor(req ActorParam, id uint) (any, error) {
 	return res, nil
 }
 
-func (uc controllerActor) DeleteActor(email string) (any, error) {
+func (uc controllerActor) DeleteActor(email string, actorName string) (any, error) {
 	var res dto.ResponseMeta
-	_, err := uc.actorUseCase.DeleteActor(email)
+	_, err := uc.actorUseCase.DeleteActor(email, actorName)
 	if err != nil {
 		return dto.ResponseMeta{}, err
 	}
@@ -91,22 +95,12 @@ func (uc controllerActor) LoginActor(req ActorParam) (SuccessLogin, error) {
 	if err != nil {
 		return SuccessLogin{}, err
 	}
-	 // Inisialisasi klaim-klaim yang ingin Anda sertakan dalam token
-	 claims := jwt.MapClaims{
-        "sub": actor.Role_id,
-        "name": actor.Username,
-        "iat": time.Now().Unix(),
-        "exp": time.Now().Add(time.Hour * 1).Unix(),
-    }
-
-    // Tandatangani token dengan kunci rahasia
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    signedToken, err := token.SignedString([]byte("secret-key"))
-    if err != nil {
-        // Penanganan kesalahan
-    }
-
-    // Gunakan signedToken seperti yang Anda butuhkan
+
+	// access token berumur pendek, refresh token untuk memperbarui
+	tokens, err := uc.sessionUseCase.StartSession(actor)
+	if err != nil {
+		return SuccessLogin{}, err
+	}
 
 	res := SuccessLogin{
 		ResponseMeta: dto.ResponseMeta{
@@ -115,7 +109,47 @@ func (uc controllerActor) LoginActor(req ActorParam) (SuccessLogin, error) {
 			Message:      "Success actor",
 			ResponseTime: "",
 		},
-		Data: signedToken,
+		Data: sessions.NewTokenPair(tokens),
+	}
+	return res, nil
+}
+
+func (uc controllerActor) ListTrash(req TrashListParam, requestUrl url.URL) (ListActor, error) {
+	page, err := uc.actorUseCase.ListTrash(req)
+	if err != nil {
+		return ListActor{}, err
+	}
+	res := ListActor{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get deleted actors",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Actors,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Actor{}
+	}
+	return res, nil
+}
+
+func (uc controllerActor) RestoreActor(username string) (FindActor, error) {
+	actor, err := uc.actorUseCase.RestoreActor(username)
+	if err != nil {
+		return FindActor{}, err
+	}
+	res := FindActor{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success restore actor",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: actor,
 	}
 	return res, nil
 }
@@ -3,11 +3,44 @@ package actors
 import (
 	"github.com/alkamalp/crm-golang/dto"
 	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/modules/sessions"
 )
 
+// ActorParam registration, username fits actors.username varchar(16) and
+// bcrypt ignores password bytes after the 72nd
 type ActorParam struct {
+	Username string `json:"username" binding:"required,min=3,max=16,username"`
+	Password string `json:"password" binding:"required,min=8,max=72,password"`
+	Role_id  uint   `json:"role_id"`
+	Verified int    `json:"verified"`
+	Active   int    `json:"active"`
+}
+
+// UpdateActorParam bound from the query string, empty fields are left as is
+type UpdateActorParam struct {
+	Username string `json:"username" form:"username" binding:"omitempty,min=3,max=16,username"`
+	Password string `json:"password" form:"password" binding:"omitempty,min=8,max=72,password"`
+	Role_id  uint   `json:"role_id" form:"role_id"`
+	Verified int    `json:"verified" form:"verified" binding:"oneof=0 1"`
+	Active   int    `json:"active" form:"active" binding:"oneof=0 1"`
+}
+
+// LoginParam the password policy is not applied, it only matters when a
+// password is set
+type LoginParam struct {
+	Username string `json:"username" binding:"required,max=16"`
+	Password string `json:"password" binding:"required,max=72"`
+}
+
+type TrashListParam struct {
+	Page  int `form:"page" binding:"min=0"`
+	Limit int `form:"limit" binding:"min=0"`
+}
+
+// CreatedActor a new actor as returned to the client, without its password
+type CreatedActor struct {
+	ID       uint   `json:"id"`
 	Username string `json:"username"`
-	Password string `json:"password"`
 	Role_id  uint   `json:"role_id"`
 	Verified int    `json:"verified"`
 	Active   int    `json:"active"`
@@ -15,7 +48,7 @@ type ActorParam struct {
 
 type SuccessCreate struct {
 	dto.ResponseMeta
-	Data ActorParam `json:"data"`
+	Data CreatedActor `json:"data"`
 }
 
 type FindActor struct {
@@ -23,7 +56,12 @@ type FindActor struct {
 	Data entity.Actor `json:"data"`
 }
 
+type ListActor struct {
+	dto.ListResponseMeta
+	Data []entity.Actor `json:"data"`
+}
+
 type SuccessLogin struct {
 	dto.ResponseMeta
-	Data string `json:"data"`
+	Data sessions.TokenPair `json:"data"`
 }
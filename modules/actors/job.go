@@ -0,0 +1,29 @@
+package actors
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/job"
+	"gorm.io/gorm"
+)
+
+// NewPurgeJob job permanently deleting actors that have been in the trash
+// longer than retention
+func NewPurgeJob(dbCrud *gorm.DB, retention time.Duration) job.Func {
+	uc := useCaseActor{
+		actorRepo: repository.NewActor(dbCrud),
+	}
+	return func(ctx context.Context) error {
+		purged, err := uc.PurgeTrash(time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			log.Printf("purged %d actors from the trash", purged)
+		}
+		return nil
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"strconv"
 
 	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/modules/sessions"
 	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/alkamalp/crm-golang/utils/validation"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -16,25 +20,28 @@ type RequestHandlerActor struct {
 
 func NewActorRequestHandler(
 	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
 ) RequestHandlerActor {
 	return RequestHandlerActor{
 		ctr: controllerActor{
 			actorUseCase: useCaseActor{
 				actorRepo: repository.NewActor(dbCrud),
 			},
+			sessionUseCase: sessions.NewUseCaseSession(dbCrud, issuer, cfg.JWT),
 		}}
 }
 
 func (h RequestHandlerActor) CreateActor(c *gin.Context) {
 	request := ActorParam{}
-	err := c.Bind(&request)
+	err := c.ShouldBind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		c.JSON(validation.NewBindErrorResponse(err))
 		return
 	}
 	res, err := h.ctr.CreateActor(request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -50,7 +57,7 @@ func (h RequestHandlerActor) GetActorById(c *gin.Context) {
 
 	res, err := h.ctr.GetActorById(uint(actorId))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -58,19 +65,19 @@ func (h RequestHandlerActor) GetActorById(c *gin.Context) {
 
 func (h RequestHandlerActor) DeleteActor(c *gin.Context) {
 	username := c.Param("username")
-	res, err := h.ctr.DeleteActor(username)
+	res, err := h.ctr.DeleteActor(username, c.GetString("Username"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
 }
 
 func (h RequestHandlerActor) UpdateActor(c *gin.Context) {
-	request := ActorParam{}
-	err := c.BindQuery(&request)
+	request := UpdateActorParam{}
+	err := c.ShouldBindQuery(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		c.JSON(validation.NewBindErrorResponse(err))
 		return
 	}
 	actorId, err := strconv.ParseUint(c.Param("id"), 10, 64)
@@ -79,26 +86,54 @@ func (h RequestHandlerActor) UpdateActor(c *gin.Context) {
 		return
 	}
 
-	res, err := h.ctr.UpdateActor(request, uint(actorId))
+	res, err := h.ctr.UpdateActor(ActorParam(request), uint(actorId))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
 }
 
 func (h RequestHandlerActor) LoginActor(c *gin.Context) {
-	request := ActorParam{}
-	err := c.Bind(&request)
+	request := LoginParam{}
+	err := c.ShouldBind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		c.JSON(validation.NewBindErrorResponse(err))
 		return
 	}
-	res, err := h.ctr.LoginActor(request)
+	res, err := h.ctr.LoginActor(ActorParam{
+		Username: request.Username,
+		Password: request.Password,
+	})
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.Header("Authorization", res.Data.Access_token)
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerActor) ListTrash(c *gin.Context) {
+	request := TrashListParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+
+	res, err := h.ctr.ListTrash(request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerActor) RestoreActor(c *gin.Context) {
+	res, err := h.ctr.RestoreActor(c.Param("username"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
-	c.Header("Authorization", res.Data)
 	c.JSON(http.StatusOK, res)
 }
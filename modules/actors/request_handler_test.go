@@ -0,0 +1,234 @@
+package actors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActorRequestValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		fields map[string]string
+	}{
+		{
+			name:   "valid registration",
+			method: http.MethodPost,
+			target: "/actor",
+			body:   `{"username":"admin.one","password":"s3cretpass"}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "blank registration",
+			method: http.MethodPost,
+			target: "/actor",
+			body:   `{"username":"","password":""}`,
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"username": "required", "password": "required"},
+		},
+		{
+			name:   "username longer than varchar(16)",
+			method: http.MethodPost,
+			target: "/actor",
+			body:   `{"username":"a-very-long-username","password":"s3cretpass"}`,
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"username": "max"},
+		},
+		{
+			name:   "username with spaces",
+			method: http.MethodPost,
+			target: "/actor",
+			body:   `{"username":"admin one","password":"s3cretpass"}`,
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"username": "username"},
+		},
+		{
+			name:   "short password",
+			method: http.MethodPost,
+			target: "/actor",
+			body:   `{"username":"admin1","password":"s3cret"}`,
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"password": "min"},
+		},
+		{
+			name:   "password without digits",
+			method: http.MethodPost,
+			target: "/actor",
+			body:   `{"username":"admin1","password":"secretpassword"}`,
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"password": "password"},
+		},
+		{
+			name:   "password beyond bcrypt's 72 bytes",
+			method: http.MethodPost,
+			target: "/actor",
+			body:   `{"username":"admin1","password":"` + strings.Repeat("a1", 37) + `"}`,
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"password": "max"},
+		},
+		{
+			name:   "login skips the password policy",
+			method: http.MethodPost,
+			target: "/actor/login",
+			body:   `{"username":"admin1","password":"short"}`,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "login without password",
+			method: http.MethodPost,
+			target: "/actor/login",
+			body:   `{"username":"admin1"}`,
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"password": "required"},
+		},
+		{
+			name:   "update with invalid flag",
+			method: http.MethodPut,
+			target: "/actor/1?verified=2",
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"verified": "oneof"},
+		},
+		{
+			name:   "update with short username",
+			method: http.MethodPut,
+			target: "/actor/1?username=ab",
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"username": "min"},
+		},
+		{
+			name:   "update with password without digits",
+			method: http.MethodPut,
+			target: "/actor/1?password=secretpassword",
+			status: http.StatusUnprocessableEntity,
+			fields: map[string]string{"password": "password"},
+		},
+		{
+			name:   "update username only",
+			method: http.MethodPut,
+			target: "/actor/1?username=admin2",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mockRepo := new(mocks.ActorInterfaceRepo)
+			mockRepo.On("CreateActor", mock.AnythingOfType("*entity.Actor")).Return(&entity.Actor{}, nil)
+			mockRepo.On("UpdateActor", mock.AnythingOfType("*entity.Actor"), uint(1)).Return(nil, nil)
+			mockRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{}, apperror.NotFound("actor not found"))
+			h := RequestHandlerActor{
+				ctr: controllerActor{
+					actorUseCase: useCaseActor{
+						actorRepo: mockRepo,
+					},
+				},
+			}
+			router := gin.New()
+			router.POST("/actor", h.CreateActor)
+			router.PUT("/actor/:id", h.UpdateActor)
+			router.POST("/actor/login", h.LoginActor)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.status != http.StatusUnprocessableEntity {
+				return
+			}
+			var body struct {
+				Errors []validation.FieldError `json:"errors"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			fields := map[string]string{}
+			for _, fieldErr := range body.Errors {
+				fields[fieldErr.Field] = fieldErr.Rule
+			}
+			assert.Equal(t, tt.fields, fields)
+			mockRepo.AssertNotCalled(t, "CreateActor", mock.Anything)
+		})
+	}
+}
+
+func TestUpdateActor_QueryNames(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := mocks.NewActorInterfaceRepo(t)
+	var updated entity.Actor
+	mockRepo.On("UpdateActor", mock.AnythingOfType("*entity.Actor"), uint(1)).
+		Run(func(args mock.Arguments) {
+			updated = *args.Get(0).(*entity.Actor)
+		}).
+		Return(nil, nil)
+	h := RequestHandlerActor{
+		ctr: controllerActor{
+			actorUseCase: useCaseActor{
+				actorRepo: mockRepo,
+			},
+		},
+	}
+	router := gin.New()
+	router.PUT("/actor/:id", h.UpdateActor)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/actor/1?username=admin2&password=NewPassw0rd!&role_id=2&verified=1&active=1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "admin2", updated.Username)
+	assert.True(t, middleware.CheckPassword("NewPassw0rd!", updated.Password))
+	assert.Equal(t, uint(2), updated.Role_id)
+	assert.Equal(t, 1, updated.Verified)
+	assert.Equal(t, 1, updated.Active)
+}
+
+func TestTrash_HidesPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := mocks.NewActorInterfaceRepo(t)
+	deleted := entity.Actor{ID: 3, Username: "gone", Password: "$2a$10$hash"}
+	mockRepo.On("ListDeletedActors", 20, 0).Return([]entity.Actor{deleted}, int64(1), nil)
+	mockRepo.On("RestoreActor", "gone").Return(deleted, nil)
+	mockRepo.On("CreateActor", mock.AnythingOfType("*entity.Actor")).Return(&entity.Actor{}, nil)
+	h := RequestHandlerActor{
+		ctr: controllerActor{
+			actorUseCase: useCaseActor{
+				actorRepo: mockRepo,
+			},
+		},
+	}
+	router := gin.New()
+	router.GET("/actor/trash", h.ListTrash)
+	router.POST("/actor/:username/restore", h.RestoreActor)
+	router.POST("/actor", h.CreateActor)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/actor/trash", nil),
+		httptest.NewRequest(http.MethodPost, "/actor/gone/restore", nil),
+		httptest.NewRequest(http.MethodPost, "/actor", strings.NewReader(`{"username":"gone","password":"s3cretpass"}`)),
+	} {
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, strings.ToLower(w.Body.String()), `"username":"gone"`)
+		assert.NotContains(t, strings.ToLower(w.Body.String()), "password")
+		assert.NotContains(t, w.Body.String(), "$2a$")
+	}
+}
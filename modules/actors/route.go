@@ -1,21 +1,36 @@
 package actors
 
 import (
+	"github.com/alkamalp/crm-golang/entity"
 	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type RouteActor struct {
 	ActorRequestHandeler RequestHandlerActor
+	Authentication       middleware.Authentication
+	Authorization        middleware.Authorization
+	Audit                middleware.Audit
 }
 
 func NewRouter(
 	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
 ) RouteActor {
-	return RouteActor{ActorRequestHandeler: NewActorRequestHandler(
-		dbCrud,
-	)}
+	return RouteActor{
+		ActorRequestHandeler: NewActorRequestHandler(
+			dbCrud,
+			cfg,
+			issuer,
+		),
+		Authentication: middleware.NewAuthentication(dbCrud, issuer),
+		Authorization:  middleware.NewAuthorization(dbCrud),
+		Audit:          middleware.NewAudit(dbCrud),
+	}
 }
 
 func (r RouteActor) Handle(routeVersion *gin.Engine) {
@@ -23,18 +38,33 @@ func (r RouteActor) Handle(routeVersion *gin.Engine) {
 	actor := routeVersion.Group(basepath)
 
 	actor.POST("",
+		r.Audit.Record(entity.AuditActionCreate, "actor", ""),
 		r.ActorRequestHandeler.CreateActor,
 	)
 
-	actor.GET("/:id", middleware.Auth,
+	actor.GET("/:id", r.Authentication.Auth,
+		r.Authorization.RequirePermission(middleware.PermissionActorRead),
 		r.ActorRequestHandeler.GetActorById,
 	)
-	actor.PUT("/:id", middleware.Auth,
+	actor.PUT("/:id", r.Authentication.Auth,
+		r.Authorization.RequirePermission(middleware.PermissionActorUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "actor", "id"),
 		r.ActorRequestHandeler.UpdateActor,
 	)
-	actor.DELETE("/:username", middleware.Auth,
+	actor.DELETE("/:username", r.Authentication.Auth,
+		r.Authorization.RequirePermission(middleware.PermissionActorDelete),
+		r.Audit.Record(entity.AuditActionDelete, "actor", "username"),
 		r.ActorRequestHandeler.DeleteActor,
 	)
+	actor.GET("/trash", r.Authentication.Auth,
+		r.Authorization.RequirePermission(middleware.PermissionActorDelete),
+		r.ActorRequestHandeler.ListTrash,
+	)
+	actor.POST("/:username/restore", r.Authentication.Auth,
+		r.Authorization.RequirePermission(middleware.PermissionActorDelete),
+		r.Audit.Record(entity.AuditActionRestore, "actor", "username"),
+		r.ActorRequestHandeler.RestoreActor,
+	)
 	actor.POST("/login",
 		r.ActorRequestHandeler.LoginActor,
 	)
@@ -1,19 +1,43 @@
 package actors
 
 import (
+	"errors"
 	"time"
 
+	"github.com/alkamalp/crm-golang/dto"
 	"github.com/alkamalp/crm-golang/entity"
 	"github.com/alkamalp/crm-golang/middleware"
 	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
 )
 
+var (
+	ErrInvalidCredentials = apperror.Unauthorized("invalid username or password")
+	ErrActorNotVerified   = apperror.Unauthorized("actor account has not been verified")
+	ErrActorInactive      = apperror.Unauthorized("actor account is not active")
+)
+
+// dummyPasswordHash is compared against when the username is unknown, so a
+// missing actor takes as long to reject as a wrong password
+const dummyPasswordHash = "$2a$10$.ys1qmLpisUgJInWoUBUBuY7lu09/kApklNdYpIJvZxqQbKFzofIa"
+
 type UseCaseActor interface {
 	CreateActor(actor ActorParam) (entity.Actor, error)
 	GetActorById(id uint) (entity.Actor, error)
 	UpdateActor(actor ActorParam, id uint) (*entity.Actor, error)
-	DeleteActor(username string) (any, error)
+	DeleteActor(username string, actorName string) (any, error)
 	LoginActor(actor ActorParam) (entity.Actor, error)
+	ListTrash(req TrashListParam) (ActorPage, error)
+	RestoreActor(username string) (entity.Actor, error)
+	PurgeTrash(deletedBefore time.Time) (int64, error)
+}
+
+// ActorPage one page of the trash
+type ActorPage struct {
+	Actors []entity.Actor
+	Total  int64
+	Page   int
+	Limit  int
 }
 
 type useCaseActor struct {
@@ -52,15 +76,23 @@ func (uc useCaseActor) GetActorById(id uint) (entity.Actor, error) {
 	return actor, err
 }
 
+// UpdateActor an empty password leaves the current one, a new one is stored
+// hashed like on registration
 func (uc useCaseActor) UpdateActor(actor ActorParam, id uint) (*entity.Actor, error) {
 	var editActor *entity.Actor
 	editActor = &entity.Actor{
 		Username: actor.Username,
-		Password: actor.Password,
 		Role_id:  actor.Role_id,
 		Verified: actor.Verified,
 		Active:   actor.Active,
 	}
+	if actor.Password != "" {
+		hashedPassword, err := middleware.HashPassword(actor.Password)
+		if err != nil {
+			return editActor, err
+		}
+		editActor.Password = hashedPassword
+	}
 
 	_, err := uc.actorRepo.UpdateActor(editActor, id)
 	if err != nil {
@@ -69,22 +101,60 @@ func (uc useCaseActor) UpdateActor(actor ActorParam, id uint) (*entity.Actor, er
 	return editActor, nil
 }
 
-func (uc useCaseActor) DeleteActor(username string) (any, error) {
-	_, err := uc.actorRepo.DeleteActor(username)
+// DeleteActor move to the trash, recording who deleted it
+func (uc useCaseActor) DeleteActor(username string, actorName string) (any, error) {
+	deleter, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return nil, err
+	}
+	_, err = uc.actorRepo.DeleteActor(username, deleter.ID)
 	return nil, err
 }
 
-func (uc useCaseActor) LoginActor(actor ActorParam) (entity.Actor, error) {
-	var newActor *entity.Actor
+// ListTrash page of deleted actors, most recently deleted first
+func (uc useCaseActor) ListTrash(req TrashListParam) (ActorPage, error) {
+	limit, page := dto.PageOf(req.Limit, req.Page)
 
-	newActor = &entity.Actor{
-		Username: actor.Username,
-		Password: actor.Password,
+	actors, total, err := uc.actorRepo.ListDeletedActors(limit, (page-1)*limit)
+	if err != nil {
+		return ActorPage{}, err
 	}
+	return ActorPage{
+		Actors: actors,
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	}, nil
+}
 
-	newActor, err := uc.actorRepo.LoginActor(newActor)
+func (uc useCaseActor) RestoreActor(username string) (entity.Actor, error) {
+	return uc.actorRepo.RestoreActor(username)
+}
+
+// PurgeTrash permanently delete actors trashed before deletedBefore
+func (uc useCaseActor) PurgeTrash(deletedBefore time.Time) (int64, error) {
+	return uc.actorRepo.PurgeActors(deletedBefore)
+}
+
+func (uc useCaseActor) LoginActor(actor ActorParam) (entity.Actor, error) {
+	found, err := uc.actorRepo.GetActorByUsername(actor.Username)
+	if errors.Is(err, apperror.ErrNotFound) {
+		middleware.CheckPassword(actor.Password, dummyPasswordHash)
+		return entity.Actor{}, ErrInvalidCredentials
+	}
 	if err != nil {
-		return *newActor, err
+		return entity.Actor{}, err
 	}
-	return *newActor, nil
+
+	if !middleware.CheckPassword(actor.Password, found.Password) {
+		return entity.Actor{}, ErrInvalidCredentials
+	}
+	// account status is only revealed to callers who know the password
+	if found.Verified != 1 {
+		return entity.Actor{}, ErrActorNotVerified
+	}
+	if found.Active != 1 {
+		return entity.Actor{}, ErrActorInactive
+	}
+	return found, nil
 }
@@ -6,27 +6,16 @@ import (
 	"time"
 
 	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-type MockActorRepo struct {
-	mock.Mock
-}
-
-func (m *MockActorRepo) CreateActor(actor *entity.Actor) (*entity.Actor, error) {
-	args := m.Called(actor)
-	result := args.Get(0)
-	err := args.Error(1)
-	if result == nil {
-		return nil, err
-	}
-	return result.(*entity.Actor), err
-}
-
 func TestCreateActor(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := new(mocks.ActorInterfaceRepo)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -47,19 +36,9 @@ func TestCreateActor(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func (m *MockActorRepo) GetActorById(id uint) (entity.Actor, error) {
-	args := m.Called(id)
-	result := args.Get(0)
-	err := args.Error(1)
-	if result == nil {
-		return entity.Actor{}, err
-	}
-	return result.(entity.Actor), err
-}
-
 func TestGetActorById(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := new(mocks.ActorInterfaceRepo)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -85,7 +64,7 @@ func TestGetActorById(t *testing.T) {
 
 func TestGetActorById_Error(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := new(mocks.ActorInterfaceRepo)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -105,16 +84,9 @@ func TestGetActorById_Error(t *testing.T) {
 	assert.Equal(t, entity.Actor{}, result)
 }
 
-func (m *MockActorRepo) UpdateActor(actor *entity.Actor, id uint) (*entity.Actor, error) {
-	args := m.Called(actor, id)
-	result := &entity.Actor{}
-	err := args.Error(1)
-	return result, err
-}
-
 func TestUpdateActor(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := new(mocks.ActorInterfaceRepo)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -123,7 +95,6 @@ func TestUpdateActor(t *testing.T) {
 	actorID := uint(1)
 	actor := ActorParam{
 		Username: "JohnDoe",
-		Password: "password",
 		Role_id:  2,
 		Verified: 0,
 		Active:   0,
@@ -131,7 +102,6 @@ func TestUpdateActor(t *testing.T) {
 
 	updatedActor := &entity.Actor{
 		Username: actor.Username,
-		Password: actor.Password,
 		Role_id:  actor.Role_id,
 		Verified: actor.Verified,
 		Active:   actor.Active,
@@ -149,7 +119,7 @@ func TestUpdateActor(t *testing.T) {
 
 func TestUpdateActor_Error(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := new(mocks.ActorInterfaceRepo)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -158,7 +128,6 @@ func TestUpdateActor_Error(t *testing.T) {
 	actorID := uint(1)
 	actor := ActorParam{
 		Username: "JohnDoe",
-		Password: "password",
 		Role_id:  2,
 		Verified: 0,
 		Active:   0,
@@ -168,7 +137,6 @@ func TestUpdateActor_Error(t *testing.T) {
 
 	updatedActor := &entity.Actor{
 		Username: actor.Username,
-		Password: actor.Password,
 		Role_id:  actor.Role_id,
 		Verified: actor.Verified,
 		Active:   actor.Active,
@@ -185,16 +153,38 @@ func TestUpdateActor_Error(t *testing.T) {
 	assert.NotNil(t, result)
 }
 
-func (m *MockActorRepo) DeleteActor(username string) (interface{}, error) {
-	args := m.Called(username)
-	result := args.Get(0)
-	err := args.Error(1)
-	return result, err
+func TestUpdateActor_Password(t *testing.T) {
+
+	mockRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseActor{
+		actorRepo: mockRepo,
+	}
+
+	actorID := uint(1)
+	var stored entity.Actor
+	mockRepo.On("UpdateActor", mock.AnythingOfType("*entity.Actor"), actorID).
+		Run(func(args mock.Arguments) {
+			stored = *args.Get(0).(*entity.Actor)
+			stored.Username = "john"
+			stored.Verified = 1
+			stored.Active = 1
+		}).
+		Return(nil, nil)
+
+	_, err := useCase.UpdateActor(ActorParam{Password: "NewPassw0rd!"}, actorID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "NewPassw0rd!", stored.Password)
+
+	mockRepo.On("GetActorByUsername", "john").Return(stored, nil)
+
+	_, err = useCase.LoginActor(ActorParam{Username: "john", Password: "NewPassw0rd!"})
+	assert.NoError(t, err)
 }
 
 func TestDeleteActor(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := new(mocks.ActorInterfaceRepo)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -202,11 +192,12 @@ func TestDeleteActor(t *testing.T) {
 
 	username := "JohnDoe"
 
-	mockRepo.On("DeleteActor", username).Return(nil, nil)
+	mockRepo.On("GetActorByUsername", "superadmin").Return(entity.Actor{ID: 1, Username: "superadmin"}, nil)
+	mockRepo.On("DeleteActor", username, uint(1)).Return(nil, nil)
 
-	result, err := useCase.DeleteActor(username)
+	result, err := useCase.DeleteActor(username, "superadmin")
 
-	mockRepo.AssertCalled(t, "DeleteActor", username)
+	mockRepo.AssertCalled(t, "DeleteActor", username, uint(1))
 
 	assert.NoError(t, err)
 	assert.Nil(t, result)
@@ -214,7 +205,7 @@ func TestDeleteActor(t *testing.T) {
 
 func TestDeleteActor_Error(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := new(mocks.ActorInterfaceRepo)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -224,27 +215,34 @@ func TestDeleteActor_Error(t *testing.T) {
 
 	expectedError := errors.New("failed to delete actor")
 
-	mockRepo.On("DeleteActor", username).Return(nil, expectedError)
+	mockRepo.On("GetActorByUsername", "superadmin").Return(entity.Actor{ID: 1, Username: "superadmin"}, nil)
+	mockRepo.On("DeleteActor", username, uint(1)).Return(nil, expectedError)
 
-	result, err := useCase.DeleteActor(username)
+	result, err := useCase.DeleteActor(username, "superadmin")
 
-	mockRepo.AssertCalled(t, "DeleteActor", username)
+	mockRepo.AssertCalled(t, "DeleteActor", username, uint(1))
 
 	assert.Error(t, err)
 	assert.EqualError(t, err, expectedError.Error())
 	assert.Nil(t, result)
 }
 
-func (m *MockActorRepo) LoginActor(actor *entity.Actor) (*entity.Actor, error) {
-	args := m.Called(actor)
-	result := args.Get(0)
-	err := args.Error(1)
-	return result.(*entity.Actor), err
+func newLoginActor(t *testing.T, password string, verified int, active int) entity.Actor {
+	hashedPassword, err := middleware.HashPassword(password)
+	assert.NoError(t, err)
+	return entity.Actor{
+		ID:       1,
+		Username: "john",
+		Password: hashedPassword,
+		Role_id:  2,
+		Verified: verified,
+		Active:   active,
+	}
 }
 
 func TestLoginActor(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := mocks.NewActorInterfaceRepo(t)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -255,24 +253,61 @@ func TestLoginActor(t *testing.T) {
 		Password: "password",
 	}
 
-	expectedActor := &entity.Actor{
-		Username: actor.Username,
-		Password: actor.Password,
+	expectedActor := newLoginActor(t, actor.Password, 1, 1)
+
+	mockRepo.On("GetActorByUsername", actor.Username).Return(expectedActor, nil)
+
+	result, err := useCase.LoginActor(actor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedActor, result)
+}
+
+func TestLoginActor_WrongPassword(t *testing.T) {
+
+	mockRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseActor{
+		actorRepo: mockRepo,
+	}
+
+	actor := ActorParam{
+		Username: "john",
+		Password: "wrong-password",
 	}
 
-	mockRepo.On("LoginActor", expectedActor).Return(expectedActor, nil)
+	mockRepo.On("GetActorByUsername", actor.Username).Return(newLoginActor(t, "password", 1, 1), nil)
 
 	result, err := useCase.LoginActor(actor)
 
-	mockRepo.AssertCalled(t, "LoginActor", expectedActor)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, entity.Actor{}, result)
+}
 
-	assert.NoError(t, err)
-	assert.Equal(t, *expectedActor, result)
+func TestLoginActor_UnknownUsername(t *testing.T) {
+
+	mockRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseActor{
+		actorRepo: mockRepo,
+	}
+
+	actor := ActorParam{
+		Username: "nobody",
+		Password: "password",
+	}
+
+	mockRepo.On("GetActorByUsername", actor.Username).Return(entity.Actor{}, apperror.NotFound("actor not found"))
+
+	result, err := useCase.LoginActor(actor)
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, entity.Actor{}, result)
 }
 
-func TestLoginActor_Error(t *testing.T) {
+func TestLoginActor_NotVerified(t *testing.T) {
 
-	mockRepo := new(MockActorRepo)
+	mockRepo := mocks.NewActorInterfaceRepo(t)
 
 	useCase := useCaseActor{
 		actorRepo: mockRepo,
@@ -283,21 +318,87 @@ func TestLoginActor_Error(t *testing.T) {
 		Password: "password",
 	}
 
-	expectedActor := &entity.Actor{
-		Username: actor.Username,
-		Password: actor.Password,
+	mockRepo.On("GetActorByUsername", actor.Username).Return(newLoginActor(t, actor.Password, 0, 1), nil)
+
+	_, err := useCase.LoginActor(actor)
+
+	assert.ErrorIs(t, err, ErrActorNotVerified)
+}
+
+func TestLoginActor_Inactive(t *testing.T) {
+
+	mockRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseActor{
+		actorRepo: mockRepo,
 	}
 
-	expectedError := errors.New("login failed")
+	actor := ActorParam{
+		Username: "john",
+		Password: "password",
+	}
 
-	mockRepo.On("LoginActor", expectedActor).Return(expectedActor, expectedError) // Return expectedActor instead of nil
+	mockRepo.On("GetActorByUsername", actor.Username).Return(newLoginActor(t, actor.Password, 1, 0), nil)
 
-	result, err := useCase.LoginActor(actor)
+	_, err := useCase.LoginActor(actor)
 
-	mockRepo.AssertCalled(t, "LoginActor", expectedActor)
+	assert.ErrorIs(t, err, ErrActorInactive)
+}
+
+func TestLoginActor_Error(t *testing.T) {
+
+	mockRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseActor{
+		actorRepo: mockRepo,
+	}
+
+	actor := ActorParam{
+		Username: "john",
+		Password: "password",
+	}
+
+	expectedError := errors.New("connection refused")
+
+	mockRepo.On("GetActorByUsername", actor.Username).Return(entity.Actor{}, expectedError)
+
+	result, err := useCase.LoginActor(actor)
 
-	assert.Error(t, err)
 	assert.EqualError(t, err, expectedError.Error())
-	assert.Equal(t, *expectedActor, result)
+	assert.Equal(t, entity.Actor{}, result)
+}
+
+func TestDeleteActor_UnknownDeleter(t *testing.T) {
+
+	mockRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseActor{
+		actorRepo: mockRepo,
+	}
+
+	mockRepo.On("GetActorByUsername", "ghost").Return(entity.Actor{}, apperror.NotFound("actor not found"))
+
+	_, err := useCase.DeleteActor("JohnDoe", "ghost")
+
+	assert.ErrorIs(t, err, middleware.ErrUnknownActor)
+	mockRepo.AssertNotCalled(t, "DeleteActor", mock.Anything, mock.Anything)
+}
+
+func TestListTrash(t *testing.T) {
+
+	mockRepo := mocks.NewActorInterfaceRepo(t)
 
+	useCase := useCaseActor{
+		actorRepo: mockRepo,
+	}
+
+	actors := []entity.Actor{{ID: 3, Username: "admin3"}}
+	mockRepo.On("ListDeletedActors", 5, 5).Return(actors, int64(6), nil)
+
+	result, err := useCase.ListTrash(TrashListParam{Page: 2, Limit: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, actors, result.Actors)
+	assert.Equal(t, int64(6), result.Total)
+	assert.Equal(t, 2, result.Page)
 }
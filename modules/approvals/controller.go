@@ -0,0 +1,82 @@
+package approvals
+
+import (
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+type ControllerApproval interface {
+	GetApprovals(req ApprovalParam) (ListApproval, error)
+	ApproveRegistration(id uint, superAdmin string) (SuccessDecide, error)
+	RejectRegistration(id uint, superAdmin string) (SuccessDecide, error)
+}
+
+type controllerApproval struct {
+	approvalUseCase UseCaseApproval
+}
+
+func (uc controllerApproval) GetApprovals(req ApprovalParam) (ListApproval, error) {
+	approvals, err := uc.approvalUseCase.GetApprovals(req.Status)
+	if err != nil {
+		return ListApproval{}, err
+	}
+	res := ListApproval{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get approvals",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: []ApprovalData{},
+	}
+	for _, approval := range approvals {
+		res.Data = append(res.Data, toApprovalData(approval))
+	}
+	return res, nil
+}
+
+func (uc controllerApproval) ApproveRegistration(id uint, superAdmin string) (SuccessDecide, error) {
+	approval, err := uc.approvalUseCase.ApproveRegistration(id, superAdmin)
+	if err != nil {
+		return SuccessDecide{}, err
+	}
+	res := SuccessDecide{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success approve registration",
+			Message:      "Actor verified and activated",
+			ResponseTime: "",
+		},
+		Data: toApprovalData(approval),
+	}
+	return res, nil
+}
+
+func (uc controllerApproval) RejectRegistration(id uint, superAdmin string) (SuccessDecide, error) {
+	approval, err := uc.approvalUseCase.RejectRegistration(id, superAdmin)
+	if err != nil {
+		return SuccessDecide{}, err
+	}
+	res := SuccessDecide{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success reject registration",
+			Message:      "Registration rejected",
+			ResponseTime: "",
+		},
+		Data: toApprovalData(approval),
+	}
+	return res, nil
+}
+
+func toApprovalData(approval entity.RegisterApproval) ApprovalData {
+	return ApprovalData{
+		ID:           approval.ID,
+		AdminId:      approval.Admin_id,
+		Username:     approval.Admin.Username,
+		Status:       approval.Status,
+		SuperAdminId: approval.Super_admin_id,
+		CreatedAt:    approval.CreatedAt,
+		DecidedAt:    approval.DecidedAt,
+	}
+}
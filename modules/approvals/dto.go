@@ -0,0 +1,31 @@
+package approvals
+
+import (
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+)
+
+type ApprovalParam struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
+
+type ApprovalData struct {
+	ID           uint       `json:"id"`
+	AdminId      uint       `json:"admin_id"`
+	Username     string     `json:"username"`
+	Status       string     `json:"status"`
+	SuperAdminId *uint      `json:"super_admin_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DecidedAt    *time.Time `json:"decided_at"`
+}
+
+type ListApproval struct {
+	dto.ResponseMeta
+	Data []ApprovalData `json:"data"`
+}
+
+type SuccessDecide struct {
+	dto.ResponseMeta
+	Data ApprovalData `json:"data"`
+}
@@ -0,0 +1,71 @@
+package approvals
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RequestHandlerApproval struct {
+	ctr ControllerApproval
+}
+
+func NewApprovalRequestHandler(
+	dbCrud *gorm.DB,
+) RequestHandlerApproval {
+	return RequestHandlerApproval{
+		ctr: controllerApproval{
+			approvalUseCase: useCaseApproval{
+				approvalRepo: repository.NewApproval(dbCrud),
+				actorRepo:    repository.NewActor(dbCrud),
+			},
+		}}
+}
+
+func (h RequestHandlerApproval) GetApprovals(c *gin.Context) {
+	request := ApprovalParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.GetApprovals(request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerApproval) ApproveRegistration(c *gin.Context) {
+	approvalId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.ApproveRegistration(uint(approvalId), c.GetString("Username"))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerApproval) RejectRegistration(c *gin.Context) {
+	approvalId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.RejectRegistration(uint(approvalId), c.GetString("Username"))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,52 @@
+package approvals
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RouteApproval struct {
+	ApprovalRequestHandeler RequestHandlerApproval
+	Authentication          middleware.Authentication
+	Authorization           middleware.Authorization
+	Audit                   middleware.Audit
+}
+
+func NewRouter(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+) RouteApproval {
+	return RouteApproval{
+		ApprovalRequestHandeler: NewApprovalRequestHandler(
+			dbCrud,
+		),
+		Authentication: middleware.NewAuthentication(dbCrud, issuer),
+		Authorization:  middleware.NewAuthorization(dbCrud),
+		Audit:          middleware.NewAudit(dbCrud),
+	}
+}
+
+func (r RouteApproval) Handle(routeVersion *gin.Engine) {
+	basepath := "/approval"
+	approval := routeVersion.Group(basepath,
+		r.Authentication.Auth,
+		r.Authorization.RequirePermission(middleware.PermissionApprovalManage),
+	)
+
+	approval.GET("",
+		r.ApprovalRequestHandeler.GetApprovals,
+	)
+	approval.PUT("/:id/approve",
+		r.Audit.Record(entity.AuditActionUpdate, "approval", "id"),
+		r.ApprovalRequestHandeler.ApproveRegistration,
+	)
+	approval.PUT("/:id/reject",
+		r.Audit.Record(entity.AuditActionUpdate, "approval", "id"),
+		r.ApprovalRequestHandeler.RejectRegistration,
+	)
+}
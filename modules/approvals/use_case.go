@@ -0,0 +1,76 @@
+package approvals
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+)
+
+var (
+	ErrInvalidStatus      = apperror.Validation("status must be pending, approved or rejected", nil)
+	ErrUnknownSuperAdmin  = apperror.Unauthorized("deciding actor not found")
+	ErrApprovalNotPending = repository.ErrApprovalNotPending
+)
+
+type UseCaseApproval interface {
+	GetApprovals(status string) ([]entity.RegisterApproval, error)
+	ApproveRegistration(id uint, superAdmin string) (entity.RegisterApproval, error)
+	RejectRegistration(id uint, superAdmin string) (entity.RegisterApproval, error)
+}
+
+type useCaseApproval struct {
+	approvalRepo repository.ApprovalInterfaceRepo
+	actorRepo    repository.ActorInterfaceRepo
+}
+
+func (uc useCaseApproval) GetApprovals(status string) ([]entity.RegisterApproval, error) {
+	if status == "" {
+		status = entity.ApprovalStatusPending
+	}
+	if status != entity.ApprovalStatusPending &&
+		status != entity.ApprovalStatusApproved &&
+		status != entity.ApprovalStatusRejected {
+		return nil, ErrInvalidStatus
+	}
+	return uc.approvalRepo.GetApprovals(status)
+}
+
+func (uc useCaseApproval) ApproveRegistration(id uint, superAdmin string) (entity.RegisterApproval, error) {
+	return uc.decide(id, superAdmin, entity.ApprovalStatusApproved)
+}
+
+func (uc useCaseApproval) RejectRegistration(id uint, superAdmin string) (entity.RegisterApproval, error) {
+	return uc.decide(id, superAdmin, entity.ApprovalStatusRejected)
+}
+
+func (uc useCaseApproval) decide(id uint, superAdmin string, status string) (entity.RegisterApproval, error) {
+	decider, err := uc.actorRepo.GetActorByUsername(superAdmin)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return entity.RegisterApproval{}, ErrUnknownSuperAdmin
+	}
+	if err != nil {
+		return entity.RegisterApproval{}, err
+	}
+
+	approval, err := uc.approvalRepo.GetApprovalById(id)
+	if err != nil {
+		return entity.RegisterApproval{}, err
+	}
+	if approval.Status != entity.ApprovalStatusPending {
+		return approval, ErrApprovalNotPending
+	}
+
+	decidedAt := time.Now()
+	approval.Status = status
+	approval.Super_admin_id = &decider.ID
+	approval.DecidedAt = &decidedAt
+
+	err = uc.approvalRepo.DecideApproval(&approval)
+	if err != nil {
+		return approval, err
+	}
+	return approval, nil
+}
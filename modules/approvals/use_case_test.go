@@ -0,0 +1,154 @@
+package approvals
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetApprovals(t *testing.T) {
+
+	mockRepo := mocks.NewApprovalInterfaceRepo(t)
+
+	useCase := useCaseApproval{
+		approvalRepo: mockRepo,
+	}
+
+	approvals := []entity.RegisterApproval{
+		{ID: 1, Admin_id: 4, Status: entity.ApprovalStatusPending},
+	}
+
+	mockRepo.On("GetApprovals", entity.ApprovalStatusPending).Return(approvals, nil)
+
+	result, err := useCase.GetApprovals("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, approvals, result)
+}
+
+func TestGetApprovals_InvalidStatus(t *testing.T) {
+
+	mockRepo := mocks.NewApprovalInterfaceRepo(t)
+
+	useCase := useCaseApproval{
+		approvalRepo: mockRepo,
+	}
+
+	_, err := useCase.GetApprovals("deleted")
+
+	mockRepo.AssertNotCalled(t, "GetApprovals", mock.Anything)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
+
+func TestApproveRegistration(t *testing.T) {
+
+	mockApprovalRepo := mocks.NewApprovalInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseApproval{
+		approvalRepo: mockApprovalRepo,
+		actorRepo:    mockActorRepo,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "superadmin").Return(entity.Actor{ID: 2, Username: "superadmin"}, nil)
+	mockApprovalRepo.On("GetApprovalById", uint(1)).
+		Return(entity.RegisterApproval{ID: 1, Admin_id: 4, Status: entity.ApprovalStatusPending}, nil)
+	mockApprovalRepo.On("DecideApproval", mock.MatchedBy(func(approval *entity.RegisterApproval) bool {
+		return approval.ID == 1 &&
+			approval.Status == entity.ApprovalStatusApproved &&
+			approval.Super_admin_id != nil && *approval.Super_admin_id == 2 &&
+			approval.DecidedAt != nil
+	})).Return(nil)
+
+	result, err := useCase.ApproveRegistration(1, "superadmin")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ApprovalStatusApproved, result.Status)
+}
+
+func TestRejectRegistration(t *testing.T) {
+
+	mockApprovalRepo := mocks.NewApprovalInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseApproval{
+		approvalRepo: mockApprovalRepo,
+		actorRepo:    mockActorRepo,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "superadmin").Return(entity.Actor{ID: 2, Username: "superadmin"}, nil)
+	mockApprovalRepo.On("GetApprovalById", uint(1)).
+		Return(entity.RegisterApproval{ID: 1, Admin_id: 4, Status: entity.ApprovalStatusPending}, nil)
+	mockApprovalRepo.On("DecideApproval", mock.AnythingOfType("*entity.RegisterApproval")).Return(nil)
+
+	result, err := useCase.RejectRegistration(1, "superadmin")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ApprovalStatusRejected, result.Status)
+	assert.Equal(t, uint(2), *result.Super_admin_id)
+}
+
+func TestApproveRegistration_AlreadyDecided(t *testing.T) {
+
+	mockApprovalRepo := mocks.NewApprovalInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseApproval{
+		approvalRepo: mockApprovalRepo,
+		actorRepo:    mockActorRepo,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "superadmin").Return(entity.Actor{ID: 2, Username: "superadmin"}, nil)
+	mockApprovalRepo.On("GetApprovalById", uint(1)).
+		Return(entity.RegisterApproval{ID: 1, Admin_id: 4, Status: entity.ApprovalStatusRejected}, nil)
+
+	_, err := useCase.ApproveRegistration(1, "superadmin")
+
+	mockApprovalRepo.AssertNotCalled(t, "DecideApproval", mock.Anything)
+	assert.ErrorIs(t, err, ErrApprovalNotPending)
+}
+
+func TestApproveRegistration_NotFound(t *testing.T) {
+
+	mockApprovalRepo := mocks.NewApprovalInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseApproval{
+		approvalRepo: mockApprovalRepo,
+		actorRepo:    mockActorRepo,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "superadmin").Return(entity.Actor{ID: 2, Username: "superadmin"}, nil)
+	mockApprovalRepo.On("GetApprovalById", uint(9)).Return(entity.RegisterApproval{}, apperror.NotFound("registration approval not found"))
+
+	_, err := useCase.ApproveRegistration(9, "superadmin")
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestApproveRegistration_Error(t *testing.T) {
+
+	mockApprovalRepo := mocks.NewApprovalInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseApproval{
+		approvalRepo: mockApprovalRepo,
+		actorRepo:    mockActorRepo,
+	}
+
+	expectedError := errors.New("failed to decide approval")
+
+	mockActorRepo.On("GetActorByUsername", "superadmin").Return(entity.Actor{ID: 2, Username: "superadmin"}, nil)
+	mockApprovalRepo.On("GetApprovalById", uint(1)).
+		Return(entity.RegisterApproval{ID: 1, Admin_id: 4, Status: entity.ApprovalStatusPending}, nil)
+	mockApprovalRepo.On("DecideApproval", mock.AnythingOfType("*entity.RegisterApproval")).Return(expectedError)
+
+	_, err := useCase.ApproveRegistration(1, "superadmin")
+
+	assert.EqualError(t, err, expectedError.Error())
+}
@@ -0,0 +1,35 @@
+package audits
+
+import (
+	"net/url"
+
+	"github.com/alkamalp/crm-golang/dto"
+)
+
+type ControllerAudit interface {
+	ListAuditLogs(req AuditListParam, requestUrl url.URL) (ListAudit, error)
+}
+
+type controllerAudit struct {
+	auditUseCase UseCaseAudit
+}
+
+func (uc controllerAudit) ListAuditLogs(req AuditListParam, requestUrl url.URL) (ListAudit, error) {
+	page, err := uc.auditUseCase.ListAuditLogs(req)
+	if err != nil {
+		return ListAudit{}, err
+	}
+	res := ListAudit{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get audit logs",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Entries,
+	}
+	return res, nil
+}
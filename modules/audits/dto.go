@@ -0,0 +1,42 @@
+package audits
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+)
+
+// AuditListParam zero fields match every entry, From and To are RFC 3339
+// times, entries written at or after From and before To are listed
+type AuditListParam struct {
+	Page        int    `form:"page" binding:"min=0"`
+	Limit       int    `form:"limit" binding:"min=0"`
+	Actor       uint   `form:"actor"`
+	Action      string `form:"action" binding:"omitempty,oneof=create update delete restore merge import"`
+	Entity_type string `form:"entity_type" binding:"max=32"`
+	Entity_id   uint   `form:"entity_id"`
+	Request_id  string `form:"request_id" binding:"max=64"`
+	From        string `form:"from" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To          string `form:"to" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// AuditEntry entity.AuditLog with Before and After as JSON objects, null
+// for the side of a create or hard delete that has no record
+type AuditEntry struct {
+	ID          uint            `json:"id"`
+	Actor_id    *uint           `json:"actor_id"`
+	Action      string          `json:"action"`
+	Entity_type string          `json:"entity_type"`
+	Entity_id   uint            `json:"entity_id"`
+	Before      json.RawMessage `json:"before"`
+	After       json.RawMessage `json:"after"`
+	Request_id  string          `json:"request_id"`
+	Client_ip   string          `json:"client_ip"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type ListAudit struct {
+	dto.ListResponseMeta
+	Data []AuditEntry `json:"data"`
+}
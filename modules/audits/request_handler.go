@@ -0,0 +1,41 @@
+package audits
+
+import (
+	"net/http"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RequestHandlerAudit struct {
+	ctr ControllerAudit
+}
+
+func NewAuditRequestHandler(
+	dbCrud *gorm.DB,
+) RequestHandlerAudit {
+	return RequestHandlerAudit{
+		ctr: controllerAudit{
+			auditUseCase: useCaseAudit{
+				auditRepo: repository.NewAuditLog(dbCrud),
+			},
+		}}
+}
+
+func (h RequestHandlerAudit) ListAuditLogs(c *gin.Context) {
+	request := AuditListParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.ListAuditLogs(request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,39 @@
+package audits
+
+import (
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RouteAudit struct {
+	AuditRequestHandeler RequestHandlerAudit
+	Authentication       middleware.Authentication
+	Authorization        middleware.Authorization
+}
+
+func NewRouter(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+) RouteAudit {
+	return RouteAudit{
+		AuditRequestHandeler: NewAuditRequestHandler(
+			dbCrud,
+		),
+		Authentication: middleware.NewAuthentication(dbCrud, issuer),
+		Authorization:  middleware.NewAuthorization(dbCrud),
+	}
+}
+
+func (r RouteAudit) Handle(routeVersion *gin.Engine) {
+	basepath := "/audit"
+	audit := routeVersion.Group(basepath, r.Authentication.Auth)
+
+	audit.GET("",
+		r.Authorization.RequirePermission(middleware.PermissionAuditRead),
+		r.AuditRequestHandeler.ListAuditLogs,
+	)
+}
@@ -0,0 +1,99 @@
+package audits
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+)
+
+type UseCaseAudit interface {
+	ListAuditLogs(req AuditListParam) (AuditPage, error)
+}
+
+var ErrInvalidRange = apperror.Validation("from must be before to", nil)
+
+// AuditPage one page of audit entries
+type AuditPage struct {
+	Entries []AuditEntry
+	Total   int64
+	Page    int
+	Limit   int
+}
+
+type useCaseAudit struct {
+	auditRepo repository.AuditInterfaceRepo
+}
+
+// ListAuditLogs page of audit entries matching req, the latest first
+func (uc useCaseAudit) ListAuditLogs(req AuditListParam) (AuditPage, error) {
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	filter := repository.AuditFilter{
+		Actor_id:    req.Actor,
+		Action:      req.Action,
+		Entity_type: req.Entity_type,
+		Entity_id:   req.Entity_id,
+		Request_id:  req.Request_id,
+		From:        parseTime(req.From),
+		To:          parseTime(req.To),
+		Limit:       limit,
+		Offset:      (page - 1) * limit,
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return AuditPage{}, ErrInvalidRange
+	}
+
+	audits, total, err := uc.auditRepo.ListAuditLogs(filter)
+	if err != nil {
+		return AuditPage{}, err
+	}
+	entries := make([]AuditEntry, len(audits))
+	for i, audit := range audits {
+		entries[i] = newAuditEntry(audit)
+	}
+	return AuditPage{
+		Entries: entries,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+func newAuditEntry(audit entity.AuditLog) AuditEntry {
+	return AuditEntry{
+		ID:          audit.ID,
+		Actor_id:    audit.Actor_id,
+		Action:      audit.Action,
+		Entity_type: audit.Entity_type,
+		Entity_id:   audit.Entity_id,
+		Before:      rawJson(audit.Before),
+		After:       rawJson(audit.After),
+		Request_id:  audit.Request_id,
+		Client_ip:   audit.Client_ip,
+		CreatedAt:   audit.CreatedAt,
+	}
+}
+
+// rawJson stored JSON as is, null when missing or not valid JSON
+func rawJson(value *string) json.RawMessage {
+	if value == nil || !json.Valid([]byte(*value)) {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(*value)
+}
+
+// parseTime nil for an empty value, binding already checked the format
+func parseTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
@@ -0,0 +1,51 @@
+package audits
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListAuditLogs(t *testing.T) {
+
+	mockRepo := mocks.NewAuditInterfaceRepo(t)
+
+	useCase := useCaseAudit{
+		auditRepo: mockRepo,
+	}
+
+	before, after := `{"first_name":"Jon"}`, `{"first_name":"John"}`
+	mockRepo.On("ListAuditLogs", mock.MatchedBy(func(filter repository.AuditFilter) bool {
+		return filter.Entity_type == "customer" && filter.Entity_id == 3 && filter.Limit == 10 && filter.Offset == 10 &&
+			filter.From.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) && filter.To == nil
+	})).Return([]entity.AuditLog{
+		{ID: 8, Action: entity.AuditActionUpdate, Entity_type: "customer", Entity_id: 3, Before: &before, After: &after},
+		{ID: 7, Action: entity.AuditActionCreate, Entity_type: "customer", Entity_id: 3, After: &after},
+	}, int64(12), nil)
+
+	page, err := useCase.ListAuditLogs(AuditListParam{Page: 2, Limit: 10, Entity_type: "customer", Entity_id: 3, From: "2026-10-01T07:00:00+07:00"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), page.Total)
+	assert.JSONEq(t, before, string(page.Entries[0].Before))
+	assert.Equal(t, "null", string(page.Entries[1].Before))
+}
+
+func TestListAuditLogs_InvalidRange(t *testing.T) {
+
+	mockRepo := mocks.NewAuditInterfaceRepo(t)
+
+	useCase := useCaseAudit{
+		auditRepo: mockRepo,
+	}
+
+	_, err := useCase.ListAuditLogs(AuditListParam{From: "2026-10-02T00:00:00Z", To: "2026-10-01T00:00:00Z"})
+
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	mockRepo.AssertNotCalled(t, "ListAuditLogs", mock.Anything)
+}
@@ -0,0 +1,127 @@
+package customers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+)
+
+var (
+	ErrActivityEmpty    = apperror.Validation("an activity needs a subject or a body", nil)
+	ErrActivityNotFound = apperror.NotFound("activity not found")
+)
+
+// TimelinePage one page of a customer timeline
+type TimelinePage struct {
+	Entries []entity.TimelineEntry
+	Total   int64
+	Page    int
+	Limit   int
+}
+
+// CreateActivity record an interaction with a customer by the acting actor,
+// OccurredAt defaults to now
+func (uc useCaseCustomer) CreateActivity(customerId uint, req ActivityParam, actorName string) (entity.Activity, error) {
+	actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return entity.Activity{}, err
+	}
+	_, err = uc.customerRepo.GetCustomerById(customerId)
+	if err != nil {
+		return entity.Activity{}, err
+	}
+	activity, err := activityOf(req)
+	if err != nil {
+		return activity, err
+	}
+	activity.Customer_id = customerId
+	activity.Actor_id = &actor.ID
+	activity.CreatedAt = time.Now()
+	activity.UpdatedAt = time.Now()
+
+	err = uc.activityRepo.CreateActivity(&activity)
+	return activity, err
+}
+
+// UpdateActivity replace what an activity of the customer says happened
+func (uc useCaseCustomer) UpdateActivity(customerId uint, id uint, req ActivityParam) (entity.Activity, error) {
+	activity, err := uc.customerActivity(customerId, id)
+	if err != nil {
+		return activity, err
+	}
+	update, err := activityOf(req)
+	if err != nil {
+		return activity, err
+	}
+	activity.Type = update.Type
+	activity.Subject = update.Subject
+	activity.Body = update.Body
+	activity.Occurred_at = update.Occurred_at
+	activity.UpdatedAt = time.Now()
+
+	err = uc.activityRepo.UpdateActivity(&activity, id)
+	return activity, err
+}
+
+func (uc useCaseCustomer) DeleteActivity(customerId uint, id uint) error {
+	_, err := uc.customerActivity(customerId, id)
+	if err != nil {
+		return err
+	}
+	return uc.activityRepo.DeleteActivity(id)
+}
+
+// GetTimeline page of the activities and system events of a customer, most
+// recent first, ids of merged customers resolve to the survivor
+func (uc useCaseCustomer) GetTimeline(customerId uint, req TimelineParam) (TimelinePage, error) {
+	customer, err := uc.GetCustomerById(customerId)
+	if err != nil {
+		return TimelinePage{}, err
+	}
+
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	entries, total, err := uc.activityRepo.ListTimeline(customer.ID, limit, (page-1)*limit)
+	if err != nil {
+		return TimelinePage{}, err
+	}
+	return TimelinePage{
+		Entries: entries,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// customerActivity activity id, which must belong to customerId
+func (uc useCaseCustomer) customerActivity(customerId uint, id uint) (entity.Activity, error) {
+	activity, err := uc.activityRepo.GetActivityById(id)
+	if err != nil {
+		return activity, err
+	}
+	if activity.Customer_id != customerId {
+		return entity.Activity{}, ErrActivityNotFound
+	}
+	return activity, nil
+}
+
+// activityOf checked Activity of req
+func activityOf(req ActivityParam) (entity.Activity, error) {
+	activity := entity.Activity{
+		Type:        req.Type,
+		Subject:     strings.TrimSpace(req.Subject),
+		Body:        strings.TrimSpace(req.Body),
+		Occurred_at: time.Now(),
+	}
+	if activity.Subject == "" && activity.Body == "" {
+		return activity, ErrActivityEmpty
+	}
+	if req.Occurred_at != nil {
+		activity.Occurred_at = *req.Occurred_at
+	}
+	return activity, nil
+}
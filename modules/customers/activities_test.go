@@ -0,0 +1,90 @@
+package customers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateActivity(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+	mockActivityRepo := mocks.NewActivityInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, actorRepo: mockActorRepo, activityRepo: mockActivityRepo}
+	occurred := time.Date(2024, 5, 2, 14, 0, 0, 0, time.UTC)
+
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5}, nil)
+	mockRepo.On("GetCustomerById", uint(3)).Return(entity.Customer{ID: 3}, nil)
+	mockActivityRepo.On("CreateActivity", mock.MatchedBy(func(activity *entity.Activity) bool {
+		return activity.Customer_id == 3 && *activity.Actor_id == 5 && activity.Occurred_at.Equal(occurred)
+	})).Return(nil)
+
+	activity, err := useCase.CreateActivity(3, ActivityParam{
+		Type:        entity.ActivityTypeCall,
+		Subject:     " Pricing questions ",
+		Occurred_at: &occurred,
+	}, "admin1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Pricing questions", activity.Subject)
+}
+
+func TestCreateActivity_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		customer error
+		req      ActivityParam
+		err      error
+	}{
+		{name: "blank", req: ActivityParam{Type: entity.ActivityTypeNote, Body: "  "}, err: ErrActivityEmpty},
+		{name: "unknown customer", customer: apperror.NotFound("customer not found"), req: ActivityParam{Type: entity.ActivityTypeNote, Body: "hi"}, err: apperror.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewCustomerInterfaceRepo(t)
+			mockActorRepo := mocks.NewActorInterfaceRepo(t)
+			useCase := useCaseCustomer{customerRepo: mockRepo, actorRepo: mockActorRepo, activityRepo: mocks.NewActivityInterfaceRepo(t)}
+			mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5}, nil)
+			mockRepo.On("GetCustomerById", uint(3)).Return(entity.Customer{ID: 3}, tt.customer)
+
+			_, err := useCase.CreateActivity(3, tt.req, "admin1")
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestUpdateActivity_OfAnotherCustomer(t *testing.T) {
+	mockActivityRepo := mocks.NewActivityInterfaceRepo(t)
+	useCase := useCaseCustomer{activityRepo: mockActivityRepo}
+	mockActivityRepo.On("GetActivityById", uint(8)).Return(entity.Activity{ID: 8, Customer_id: 4}, nil)
+
+	_, err := useCase.UpdateActivity(3, 8, ActivityParam{Type: entity.ActivityTypeNote, Body: "hi"})
+	assert.ErrorIs(t, err, ErrActivityNotFound)
+	err = useCase.DeleteActivity(3, 8)
+	assert.ErrorIs(t, err, ErrActivityNotFound)
+}
+
+func TestGetTimeline_MergedCustomer(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockActivityRepo := mocks.NewActivityInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, activityRepo: mockActivityRepo}
+	mockRepo.On("GetCustomerById", uint(2)).Return(entity.Customer{}, apperror.NotFound("customer not found"))
+	mockRepo.On("GetCustomerRedirect", uint(2)).Return(entity.CustomerRedirect{Old_id: 2, Survivor_id: 1}, nil)
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1}, nil)
+	entries := []entity.TimelineEntry{{Kind: entity.TimelineKindEvent, ID: 1, Type: entity.TimelineEventCreated}}
+	mockActivityRepo.On("ListTimeline", uint(1), dto.MaxListLimit, dto.MaxListLimit).Return(entries, int64(101), nil)
+
+	page, err := useCase.GetTimeline(2, TimelineParam{Page: 2, Limit: 500})
+
+	require.NoError(t, err)
+	assert.Equal(t, entries, page.Entries)
+	assert.Equal(t, dto.MaxListLimit, page.Limit)
+}
@@ -0,0 +1,207 @@
+package customers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/storage"
+	"github.com/alkamalp/crm-golang/utils/thumbnail"
+)
+
+// AvatarFull size of the uploaded image itself, re-encoded without metadata
+const AvatarFull = "full"
+
+// avatarSizes square thumbnails stored next to the full image
+var avatarSizes = map[string]int{
+	"256": 256,
+	"64":  64,
+}
+
+// avatarTypes accepted image types and the extension they are stored with,
+// GIFs keep their first frame as PNG
+var avatarTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".png",
+}
+
+// maxAvatarPixels larger images are refused before they are decoded
+const maxAvatarPixels = 4096 * 4096
+
+var (
+	ErrAvatarType       = apperror.Validation("avatar must be a JPEG, PNG or GIF image", nil)
+	ErrAvatarDimensions = apperror.Validation("avatar must not have more than 4096x4096 pixels", nil)
+	ErrAvatarSize       = apperror.Validation("size must be full, 256 or 64", nil)
+	ErrNoAvatar         = apperror.NotFound("customer has no uploaded avatar")
+)
+
+// AvatarFile stored avatar ready to be served, Version changes with every
+// upload and the caller closes Body
+type AvatarFile struct {
+	Body    io.ReadCloser
+	Object  storage.Object
+	Version string
+}
+
+// UploadAvatar store the image and its thumbnails and link the customer to
+// them, the files of the previous upload are deleted
+func (uc useCaseCustomer) UploadAvatar(id uint, file io.Reader, contentType string) (entity.Customer, error) {
+	customer, err := uc.GetCustomerById(id)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, int64(uc.avatarMaxSize)+1))
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	if len(data) > uc.avatarMaxSize {
+		return entity.Customer{}, apperror.Validation(fmt.Sprintf("avatar must not be larger than %d KB", uc.avatarMaxSize/1024), nil)
+	}
+	if len(data) == 0 {
+		return entity.Customer{}, ErrAvatarType
+	}
+	if declared, _, err := mime.ParseMediaType(contentType); err == nil && declared != "application/octet-stream" {
+		if _, ok := avatarTypes[declared]; !ok {
+			return entity.Customer{}, ErrAvatarType
+		}
+	}
+	ext, ok := avatarTypes[http.DetectContentType(data)]
+	if !ok {
+		return entity.Customer{}, ErrAvatarType
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return entity.Customer{}, ErrAvatarType
+	}
+	if config.Width*config.Height > maxAvatarPixels {
+		return entity.Customer{}, ErrAvatarDimensions
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return entity.Customer{}, ErrAvatarType
+	}
+
+	version, err := avatarVersion()
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	key := fmt.Sprintf("avatars/%d/%s%s", customer.ID, version, ext)
+	variants := map[string]image.Image{AvatarFull: img}
+	for size, side := range avatarSizes {
+		variants[size] = thumbnail.Square(img, side)
+	}
+	for size, variant := range variants {
+		err = uc.putAvatar(avatarKey(key, size), variant)
+		if err != nil {
+			uc.deleteAvatarFiles(key)
+			return entity.Customer{}, err
+		}
+	}
+
+	avatar := fmt.Sprintf("/customer/%d/avatar?v=%s", customer.ID, version)
+	err = uc.customerRepo.SetCustomerAvatar(customer.ID, avatar, &key)
+	if err != nil {
+		uc.deleteAvatarFiles(key)
+		return entity.Customer{}, err
+	}
+	if customer.Avatar_key != nil {
+		uc.deleteAvatarFiles(*customer.Avatar_key)
+	}
+	customer.Avatar = avatar
+	customer.Avatar_key = &key
+	return customer, nil
+}
+
+// GetAvatar one size of the uploaded avatar of a customer
+func (uc useCaseCustomer) GetAvatar(id uint, size string) (AvatarFile, error) {
+	if _, ok := avatarSizes[size]; !ok && size != AvatarFull {
+		return AvatarFile{}, ErrAvatarSize
+	}
+	customer, err := uc.GetCustomerById(id)
+	if err != nil {
+		return AvatarFile{}, err
+	}
+	if customer.Avatar_key == nil {
+		return AvatarFile{}, ErrNoAvatar
+	}
+
+	body, object, err := uc.avatarStore.Get(context.Background(), avatarKey(*customer.Avatar_key, size))
+	if errors.Is(err, storage.ErrNotFound) {
+		return AvatarFile{}, ErrNoAvatar
+	}
+	if err != nil {
+		return AvatarFile{}, err
+	}
+	return AvatarFile{Body: body, Object: object, Version: avatarKeyVersion(*customer.Avatar_key)}, nil
+}
+
+func (uc useCaseCustomer) putAvatar(key string, img image.Image) error {
+	var buf bytes.Buffer
+	var err error
+	contentType := "image/png"
+	if path.Ext(key) == ".jpg" {
+		contentType = "image/jpeg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return err
+	}
+	return uc.avatarStore.Put(context.Background(), key, &buf, int64(buf.Len()), contentType)
+}
+
+// deleteAvatarFiles remove every size stored under key, files that cannot
+// be removed are only logged since no customer points at them anymore
+func (uc useCaseCustomer) deleteAvatarFiles(key string) {
+	keys := []string{key}
+	for size := range avatarSizes {
+		keys = append(keys, avatarKey(key, size))
+	}
+	for _, key := range keys {
+		err := uc.avatarStore.Delete(context.Background(), key)
+		if err != nil {
+			log.Printf("delete avatar file %s: %v", key, err)
+		}
+	}
+}
+
+// avatarKey key of one size, avatars/1/abc.jpg holds the full image and
+// avatars/1/abc_64.jpg its 64 pixel thumbnail
+func avatarKey(key string, size string) string {
+	if size == AvatarFull {
+		return key
+	}
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_" + size + ext
+}
+
+func avatarKeyVersion(key string) string {
+	return strings.TrimSuffix(path.Base(key), path.Ext(key))
+}
+
+func avatarVersion() (string, error) {
+	random := make([]byte, 8)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random), nil
+}
@@ -0,0 +1,189 @@
+package customers
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func newAvatarUseCase(t *testing.T) (useCaseCustomer, *mocks.CustomerInterfaceRepo, storage.Local) {
+	store, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	return useCaseCustomer{customerRepo: mockRepo, avatarStore: store, avatarMaxSize: 1 << 20}, mockRepo, store
+}
+
+func storedSize(t *testing.T, store storage.Storage, key string) image.Point {
+	t.Helper()
+	body, _, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer body.Close()
+	config, _, err := image.DecodeConfig(body)
+	require.NoError(t, err)
+	return image.Pt(config.Width, config.Height)
+}
+
+func TestUploadAvatar(t *testing.T) {
+	useCase, mockRepo, store := newAvatarUseCase(t)
+
+	oldKey := "avatars/1/old.png"
+	require.NoError(t, store.Put(context.Background(), oldKey, strings.NewReader("old"), 3, "image/png"))
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1, Avatar_key: &oldKey}, nil)
+	var key *string
+	mockRepo.On("SetCustomerAvatar", uint(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		key = args.Get(2).(*string)
+	}).Return(nil)
+
+	customer, err := useCase.UploadAvatar(1, bytes.NewReader(testPNG(t, 600, 400)), "image/png")
+
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	version := avatarKeyVersion(*key)
+	assert.Equal(t, "/customer/1/avatar?v="+version, customer.Avatar)
+	assert.Equal(t, image.Pt(600, 400), storedSize(t, store, *key))
+	assert.Equal(t, image.Pt(256, 256), storedSize(t, store, avatarKey(*key, "256")))
+	assert.Equal(t, image.Pt(64, 64), storedSize(t, store, avatarKey(*key, "64")))
+	_, _, err = store.Get(context.Background(), oldKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestUploadAvatar_Invalid(t *testing.T) {
+	valid := testPNG(t, 10, 10)
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		message     string
+	}{
+		{name: "not an image", body: []byte("hello, world"), contentType: "image/png", message: ErrAvatarType.Message},
+		{name: "declared as something else", body: valid, contentType: "text/html", message: ErrAvatarType.Message},
+		{name: "too large", body: append(valid, make([]byte, 1<<20)...), contentType: "image/png", message: "avatar must not be larger than 1024 KB"},
+		{name: "too many pixels", body: testPNG(t, 5000, 4000), contentType: "image/png", message: ErrAvatarDimensions.Message},
+		{name: "empty", body: nil, contentType: "", message: ErrAvatarType.Message},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase, mockRepo, _ := newAvatarUseCase(t)
+			mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1}, nil)
+
+			_, err := useCase.UploadAvatar(1, bytes.NewReader(tt.body), tt.contentType)
+
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+			assert.EqualError(t, err, tt.message)
+			mockRepo.AssertNotCalled(t, "SetCustomerAvatar", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUploadAvatar_SaveFailsRemovesFiles(t *testing.T) {
+	useCase, mockRepo, store := newAvatarUseCase(t)
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1}, nil)
+	var key *string
+	mockRepo.On("SetCustomerAvatar", uint(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		key = args.Get(2).(*string)
+	}).Return(apperror.NotFound("customer not found"))
+
+	_, err := useCase.UploadAvatar(1, bytes.NewReader(testPNG(t, 10, 10)), "image/png")
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	_, _, err = store.Get(context.Background(), *key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestGetAvatar(t *testing.T) {
+	useCase, mockRepo, store := newAvatarUseCase(t)
+	key := "avatars/1/abc.png"
+	require.NoError(t, store.Put(context.Background(), "avatars/1/abc_64.png", strings.NewReader("png"), 3, "image/png"))
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1, Avatar_key: &key}, nil)
+	mockRepo.On("GetCustomerById", uint(2)).Return(entity.Customer{ID: 2}, nil)
+
+	file, err := useCase.GetAvatar(1, "64")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(file.Body)
+	file.Body.Close()
+	assert.Equal(t, "png", string(content))
+	assert.Equal(t, "abc", file.Version)
+	assert.Equal(t, "image/png", file.Object.ContentType)
+
+	_, err = useCase.GetAvatar(1, "1024")
+	assert.ErrorIs(t, err, ErrAvatarSize)
+	_, err = useCase.GetAvatar(1, AvatarFull)
+	assert.ErrorIs(t, err, ErrNoAvatar)
+	_, err = useCase.GetAvatar(2, AvatarFull)
+	assert.ErrorIs(t, err, ErrNoAvatar)
+}
+
+func TestPurgeTrash_RemovesAvatarFiles(t *testing.T) {
+	useCase, mockRepo, store := newAvatarUseCase(t)
+	key := "avatars/1/abc.png"
+	for _, size := range []string{AvatarFull, "256", "64"} {
+		require.NoError(t, store.Put(context.Background(), avatarKey(key, size), strings.NewReader("png"), 3, "image/png"))
+	}
+	before := time.Now()
+	mockRepo.On("PurgeCustomers", before).Return([]entity.Customer{{ID: 1, Avatar_key: &key}, {ID: 2}}, nil)
+
+	purged, err := useCase.PurgeTrash(before)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	for _, size := range []string{AvatarFull, "256", "64"} {
+		_, _, err = store.Get(context.Background(), avatarKey(key, size))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	}
+}
+
+func TestMergeCustomers_KeepsChosenAvatarFiles(t *testing.T) {
+	useCase, mockRepo, store := newAvatarUseCase(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+	useCase.actorRepo = mockActorRepo
+
+	survivorKey, victimKey := "avatars/1/old.png", "avatars/2/new.png"
+	for _, key := range []string{survivorKey, victimKey} {
+		require.NoError(t, store.Put(context.Background(), key, strings.NewReader("png"), 3, "image/png"))
+	}
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5}, nil)
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1, Avatar_key: &survivorKey}, nil)
+	mockRepo.On("GetCustomerById", uint(2)).Return(entity.Customer{ID: 2, Avatar_key: &victimKey}, nil)
+	mockRepo.On("MergeCustomers", mock.MatchedBy(func(survivor *entity.Customer) bool {
+		return *survivor.Avatar_key == victimKey
+	}), []uint{2}, mock.Anything).Return(nil)
+
+	_, err := useCase.MergeCustomers(MergeCustomerParam{
+		Survivor_id: 1,
+		Victim_ids:  []uint{2},
+		Fields:      map[string]uint{"avatar": 2},
+	}, "admin1", entity.AuditLog{})
+
+	require.NoError(t, err)
+	_, _, err = store.Get(context.Background(), survivorKey)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	body, _, err := store.Get(context.Background(), victimKey)
+	require.NoError(t, err)
+	body.Close()
+}
@@ -1,14 +1,48 @@
 package customers
 
 import (
+	"io"
+	"net/url"
+	"time"
+
 	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
 )
 
 type ControllerCustomer interface {
 	CreateCustomer(req CustomerParam) (any, error)
 	GetCustomerById(id uint) (FindCustomer, error)
 	UpdateCustomer(req CustomerParam, id uint) (any, error)
-	DeleteCustomer(id uint) (any, error)
+	DeleteCustomer(id uint, actorName string) (any, error)
+	ListCustomers(req CustomerListParam, requestUrl url.URL) (ListCustomer, error)
+	ListDuplicates(req DuplicateListParam, requestUrl url.URL) (ListDuplicates, error)
+	MergeCustomers(req MergeCustomerParam, actorName string, trail entity.AuditLog) (SuccessMerge, error)
+	ListTrash(req TrashListParam, requestUrl url.URL) (ListCustomer, error)
+	RestoreCustomer(id uint) (FindCustomer, error)
+	ImportCustomers(req ImportParam, mapping map[string]string, file io.Reader) (SuccessImport, error)
+	ExportCustomers(filter CustomerListParam, req ExportParam, w io.Writer) error
+	UploadAvatar(id uint, file io.Reader, contentType string) (FindCustomer, error)
+	GetAvatar(id uint, size string) (AvatarFile, error)
+	TagCustomer(id uint, req CustomerTagsParam) (FindCustomer, error)
+	UntagCustomer(id uint, req CustomerTagsParam) (FindCustomer, error)
+	CreateSegment(req SegmentParam, actorName string) (FindSegment, error)
+	GetSegment(id uint) (FindSegment, error)
+	ListSegments(req SegmentListParam, requestUrl url.URL) (ListSegment, error)
+	UpdateSegment(req SegmentParam, id uint) (FindSegment, error)
+	DeleteSegment(id uint) (dto.ResponseMeta, error)
+	CreateCustomField(req CustomFieldParam) (FindCustomField, error)
+	GetCustomField(id uint) (FindCustomField, error)
+	ListCustomFields() (ListCustomField, error)
+	UpdateCustomField(req UpdateCustomFieldParam, id uint) (FindCustomField, error)
+	DeleteCustomField(id uint) (dto.ResponseMeta, error)
+	SetCustomFields(id uint, req CustomerFieldsParam) (FindCustomer, error)
+	CreateActivity(customerId uint, req ActivityParam, actorName string) (FindActivity, error)
+	UpdateActivity(customerId uint, id uint, req ActivityParam) (FindActivity, error)
+	DeleteActivity(customerId uint, id uint) (dto.ResponseMeta, error)
+	GetTimeline(customerId uint, req TimelineParam, requestUrl url.URL) (ListTimeline, error)
+	GetCustomerAsOf(id uint, at time.Time) (FindCustomer, error)
+	GetCustomerHistory(id uint, req HistoryParam, requestUrl url.URL) (ListHistory, error)
+	RevertCustomer(id uint, version uint) (FindCustomer, error)
 }
 
 type controllerCustomer struct {
@@ -28,11 +62,16 @@ func (uc controllerCustomer) CreateCustomer(req CustomerParam) (any, error) {
 			Message:      "Success Register",
 			ResponseTime: "",
 		},
-		Data: CustomerParam{
-			First_name: customer.First_name,
-			Last_name:  customer.Last_name,
-			Email:      customer.Email,
-			Avatar:     customer.Avatar,
+		Data: CreatedCustomer{
+			ID: customer.ID,
+			CustomerParam: CustomerParam{
+				First_name:    customer.First_name,
+				Last_name:     customer.Last_name,
+				Email:         customer.Email,
+				Avatar:        customer.Avatar,
+				Custom_fields: customer.Custom_fields,
+				Account_id:    customer.Account_id,
+			},
 		},
 	}
 	return res, nil
@@ -67,9 +106,9 @@ func (uc controllerCustomer) UpdateCustomer(req CustomerParam, id uint) (any, er
 	return res, nil
 }
 
-func (uc controllerCustomer) DeleteCustomer(id uint) (any, error) {
+func (uc controllerCustomer) DeleteCustomer(id uint, actorName string) (any, error) {
 	var res dto.ResponseMeta
-	_, err := uc.customerUseCase.DeleteCustomer(id)
+	_, err := uc.customerUseCase.DeleteCustomer(id, actorName)
 	if err != nil {
 		return dto.ResponseMeta{}, err
 	}
@@ -79,3 +118,518 @@ func (uc controllerCustomer) DeleteCustomer(id uint) (any, error) {
 
 	return res, nil
 }
+
+func (uc controllerCustomer) ListCustomers(req CustomerListParam, requestUrl url.URL) (ListCustomer, error) {
+	page, err := uc.customerUseCase.ListCustomers(req)
+	if err != nil {
+		return ListCustomer{}, err
+	}
+	res := ListCustomer{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get customers",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, page.NextCursor),
+		},
+		Data: page.Customers,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Customer{}
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) ListDuplicates(req DuplicateListParam, requestUrl url.URL) (ListDuplicates, error) {
+	page, err := uc.customerUseCase.ListDuplicates(req)
+	if err != nil {
+		return ListDuplicates{}, err
+	}
+	res := ListDuplicates{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get duplicate customers",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Scan: DuplicateScanInfo{
+			Scanned_at:       page.Scan.CreatedAt,
+			Customers:        page.Scan.Customers,
+			Truncated_blocks: page.Scan.Truncated_blocks,
+		},
+		Data: page.Pairs,
+	}
+	if res.Data == nil {
+		res.Data = []DuplicatePair{}
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) MergeCustomers(req MergeCustomerParam, actorName string, trail entity.AuditLog) (SuccessMerge, error) {
+	customer, err := uc.customerUseCase.MergeCustomers(req, actorName, trail)
+	if err != nil {
+		return SuccessMerge{}, err
+	}
+	res := SuccessMerge{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success merge customers",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: MergeResult{
+			Customer:   customer,
+			Merged_ids: req.Victim_ids,
+		},
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) ListTrash(req TrashListParam, requestUrl url.URL) (ListCustomer, error) {
+	page, err := uc.customerUseCase.ListTrash(req)
+	if err != nil {
+		return ListCustomer{}, err
+	}
+	res := ListCustomer{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get deleted customers",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Customers,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Customer{}
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) RestoreCustomer(id uint) (FindCustomer, error) {
+	customer, err := uc.customerUseCase.RestoreCustomer(id)
+	if err != nil {
+		return FindCustomer{}, err
+	}
+	res := FindCustomer{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success restore customer",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: customer,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) ImportCustomers(req ImportParam, mapping map[string]string, file io.Reader) (SuccessImport, error) {
+	opts := ImportOptions{
+		DryRun:      req.DryRun,
+		OnDuplicate: req.OnDuplicate,
+		BatchSize:   req.BatchSize,
+		Mapping:     mapping,
+	}
+	if req.Delimiter != "" {
+		opts.Delimiter = []rune(req.Delimiter)[0]
+	}
+	report, err := uc.customerUseCase.ImportCustomers(file, opts)
+	if err != nil {
+		return SuccessImport{}, err
+	}
+	title := "Success import customers"
+	if req.DryRun {
+		title = "Success check customers import, nothing saved"
+	}
+	res := SuccessImport{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: title,
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: report,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) ExportCustomers(filter CustomerListParam, req ExportParam, w io.Writer) error {
+	return uc.customerUseCase.ExportCustomers(filter, req, w)
+}
+
+func (uc controllerCustomer) UploadAvatar(id uint, file io.Reader, contentType string) (FindCustomer, error) {
+	customer, err := uc.customerUseCase.UploadAvatar(id, file, contentType)
+	if err != nil {
+		return FindCustomer{}, err
+	}
+	res := FindCustomer{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success upload avatar",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: customer,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) GetAvatar(id uint, size string) (AvatarFile, error) {
+	return uc.customerUseCase.GetAvatar(id, size)
+}
+
+func (uc controllerCustomer) TagCustomer(id uint, req CustomerTagsParam) (FindCustomer, error) {
+	customer, err := uc.customerUseCase.TagCustomer(id, req.Tag_ids)
+	if err != nil {
+		return FindCustomer{}, err
+	}
+	res := FindCustomer{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success tag customer",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: customer,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) UntagCustomer(id uint, req CustomerTagsParam) (FindCustomer, error) {
+	customer, err := uc.customerUseCase.UntagCustomer(id, req.Tag_ids)
+	if err != nil {
+		return FindCustomer{}, err
+	}
+	res := FindCustomer{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success untag customer",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: customer,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) CreateSegment(req SegmentParam, actorName string) (FindSegment, error) {
+	segment, err := uc.customerUseCase.CreateSegment(req, actorName)
+	if err != nil {
+		return FindSegment{}, err
+	}
+	res := FindSegment{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success create segment",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: segment,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) GetSegment(id uint) (FindSegment, error) {
+	segment, err := uc.customerUseCase.GetSegment(id)
+	if err != nil {
+		return FindSegment{}, err
+	}
+	res := FindSegment{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get segment",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: segment,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) ListSegments(req SegmentListParam, requestUrl url.URL) (ListSegment, error) {
+	page, err := uc.customerUseCase.ListSegments(req)
+	if err != nil {
+		return ListSegment{}, err
+	}
+	res := ListSegment{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get segments",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Segments,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Segment{}
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) UpdateSegment(req SegmentParam, id uint) (FindSegment, error) {
+	segment, err := uc.customerUseCase.UpdateSegment(req, id)
+	if err != nil {
+		return FindSegment{}, err
+	}
+	res := FindSegment{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success update segment",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: segment,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) DeleteSegment(id uint) (dto.ResponseMeta, error) {
+	err := uc.customerUseCase.DeleteSegment(id)
+	if err != nil {
+		return dto.ResponseMeta{}, err
+	}
+	return dto.ResponseMeta{
+		Success:      true,
+		MessageTitle: "Success delete segment",
+		Message:      "Success",
+		ResponseTime: "",
+	}, nil
+}
+
+func (uc controllerCustomer) CreateCustomField(req CustomFieldParam) (FindCustomField, error) {
+	field, err := uc.customerUseCase.CreateCustomField(req)
+	if err != nil {
+		return FindCustomField{}, err
+	}
+	res := FindCustomField{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success create custom field",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: field,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) GetCustomField(id uint) (FindCustomField, error) {
+	field, err := uc.customerUseCase.GetCustomField(id)
+	if err != nil {
+		return FindCustomField{}, err
+	}
+	res := FindCustomField{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get custom field",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: field,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) ListCustomFields() (ListCustomField, error) {
+	fields, err := uc.customerUseCase.ListCustomFields()
+	if err != nil {
+		return ListCustomField{}, err
+	}
+	res := ListCustomField{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get custom fields",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: fields,
+	}
+	if res.Data == nil {
+		res.Data = []entity.CustomField{}
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) UpdateCustomField(req UpdateCustomFieldParam, id uint) (FindCustomField, error) {
+	field, err := uc.customerUseCase.UpdateCustomField(req, id)
+	if err != nil {
+		return FindCustomField{}, err
+	}
+	res := FindCustomField{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success update custom field",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: field,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) DeleteCustomField(id uint) (dto.ResponseMeta, error) {
+	err := uc.customerUseCase.DeleteCustomField(id)
+	if err != nil {
+		return dto.ResponseMeta{}, err
+	}
+	return dto.ResponseMeta{
+		Success:      true,
+		MessageTitle: "Success delete custom field",
+		Message:      "Success",
+		ResponseTime: "",
+	}, nil
+}
+
+func (uc controllerCustomer) SetCustomFields(id uint, req CustomerFieldsParam) (FindCustomer, error) {
+	customer, err := uc.customerUseCase.SetCustomFields(id, req.Custom_fields)
+	if err != nil {
+		return FindCustomer{}, err
+	}
+	res := FindCustomer{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success update custom fields",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: customer,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) CreateActivity(customerId uint, req ActivityParam, actorName string) (FindActivity, error) {
+	activity, err := uc.customerUseCase.CreateActivity(customerId, req, actorName)
+	if err != nil {
+		return FindActivity{}, err
+	}
+	res := FindActivity{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success create activity",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: activity,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) UpdateActivity(customerId uint, id uint, req ActivityParam) (FindActivity, error) {
+	activity, err := uc.customerUseCase.UpdateActivity(customerId, id, req)
+	if err != nil {
+		return FindActivity{}, err
+	}
+	res := FindActivity{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success update activity",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: activity,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) DeleteActivity(customerId uint, id uint) (dto.ResponseMeta, error) {
+	err := uc.customerUseCase.DeleteActivity(customerId, id)
+	if err != nil {
+		return dto.ResponseMeta{}, err
+	}
+	return dto.ResponseMeta{
+		Success:      true,
+		MessageTitle: "Success delete activity",
+		Message:      "Success",
+		ResponseTime: "",
+	}, nil
+}
+
+func (uc controllerCustomer) GetTimeline(customerId uint, req TimelineParam, requestUrl url.URL) (ListTimeline, error) {
+	page, err := uc.customerUseCase.GetTimeline(customerId, req)
+	if err != nil {
+		return ListTimeline{}, err
+	}
+	res := ListTimeline{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get timeline",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Entries,
+	}
+	if res.Data == nil {
+		res.Data = []entity.TimelineEntry{}
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) GetCustomerAsOf(id uint, at time.Time) (FindCustomer, error) {
+	customer, err := uc.customerUseCase.GetCustomerAsOf(id, at)
+	if err != nil {
+		return FindCustomer{}, err
+	}
+	res := FindCustomer{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get customer",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: customer,
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) GetCustomerHistory(id uint, req HistoryParam, requestUrl url.URL) (ListHistory, error) {
+	page, err := uc.customerUseCase.GetCustomerHistory(id, req)
+	if err != nil {
+		return ListHistory{}, err
+	}
+	res := ListHistory{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get customer history",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Entries,
+	}
+	if res.Data == nil {
+		res.Data = []HistoryEntry{}
+	}
+	return res, nil
+}
+
+func (uc controllerCustomer) RevertCustomer(id uint, version uint) (FindCustomer, error) {
+	customer, err := uc.customerUseCase.RevertCustomer(id, version)
+	if err != nil {
+		return FindCustomer{}, err
+	}
+	res := FindCustomer{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success revert customer",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: customer,
+	}
+	return res, nil
+}
@@ -1,23 +1,286 @@
 package customers
 
 import (
+	"time"
+
 	"github.com/alkamalp/crm-golang/dto"
 	"github.com/alkamalp/crm-golang/entity"
 )
 
+// CustomerParam lengths follow the varchar(255) columns of customer,
+// Custom_fields holds values by field name and Account_id makes the customer
+// a contact of that account
 type CustomerParam struct {
+	First_name    string         `json:"first_name" binding:"required,max=255"`
+	Last_name     string         `json:"last_name" binding:"max=255"`
+	Email         string         `json:"email" binding:"required,email,max=255"`
+	Avatar        string         `json:"avatar" binding:"max=255"`
+	Custom_fields map[string]any `json:"custom_fields,omitempty"`
+	Account_id    *uint          `json:"account_id,omitempty" binding:"omitempty,min=1"`
+}
+
+// UpdateCustomerParam bound from the query string, empty fields are left as is
+type UpdateCustomerParam struct {
+	First_name string `json:"first_name" form:"first_name" binding:"max=255"`
+	Last_name  string `json:"last_name" form:"last_name" binding:"max=255"`
+	Email      string `json:"email" form:"email" binding:"omitempty,email,max=255"`
+	Avatar     string `json:"avatar" form:"avatar" binding:"max=255"`
+}
+
+type CustomerListParam struct {
+	Page        int    `form:"page" binding:"min=0"`
+	Limit       int    `form:"limit" binding:"min=0"`
+	Cursor      string `form:"cursor"`
+	First_name  string `form:"first_name" binding:"max=255"`
+	Last_name   string `form:"last_name" binding:"max=255"`
+	Email       string `form:"email" binding:"max=255"`
+	CreatedFrom string `form:"created_from"`
+	CreatedTo   string `form:"created_to"`
+	Tags        string `form:"tags"`
+	Segment     uint   `form:"segment"`
+	Account     uint   `form:"account"`
+	Sort        string `form:"sort"`
+	// Custom_fields cf[name]=value filters, read with QueryMap
+	Custom_fields map[string]string `form:"-"`
+}
+
+type TrashListParam struct {
+	Page  int `form:"page" binding:"min=0"`
+	Limit int `form:"limit" binding:"min=0"`
+}
+
+// CustomerTagsParam tags to put on or take off a customer
+type CustomerTagsParam struct {
+	Tag_ids []uint `json:"tag_ids" binding:"required,min=1,max=50,dive,required"`
+}
+
+// SegmentFilterParam criteria of a saved segment, see entity.SegmentFilter
+type SegmentFilterParam struct {
+	First_name          string            `json:"first_name" binding:"max=255"`
+	Last_name           string            `json:"last_name" binding:"max=255"`
+	Email               string            `json:"email" binding:"max=255"`
+	Created_from        string            `json:"created_from"`
+	Created_to          string            `json:"created_to"`
+	Created_within_days int               `json:"created_within_days" binding:"min=0,max=36500"`
+	Tag_ids             []uint            `json:"tag_ids" binding:"max=50,dive,required"`
+	Custom_fields       map[string]string `json:"custom_fields"`
+}
+
+type SegmentParam struct {
+	Name   string             `json:"name" binding:"required,max=100"`
+	Filter SegmentFilterParam `json:"filter"`
+}
+
+// CustomerFieldsParam values by field name, null removes a value
+type CustomerFieldsParam struct {
+	Custom_fields map[string]any `json:"custom_fields" binding:"required"`
+}
+
+// CustomFieldRulesParam see entity.CustomFieldRules
+
+type CustomFieldRulesParam struct {
+	Min        *float64 `json:"min"`
+	Max        *float64 `json:"max"`
+	Integer    bool     `json:"integer"`
+	Min_length int      `json:"min_length" binding:"min=0,max=1000"`
+	Max_length int      `json:"max_length" binding:"min=0,max=1000"`
+	Pattern    string   `json:"pattern" binding:"max=255"`
+	Options    []string `json:"options" binding:"max=100,dive,required,max=100"`
+}
+
+type CustomFieldParam struct {
+	Name     string                `json:"name" binding:"required,max=64"`
+	Label    string                `json:"label" binding:"required,max=100"`
+	Type     string                `json:"type" binding:"required,oneof=string number date enum boolean"`
+	Required bool                  `json:"required"`
+	Rules    CustomFieldRulesParam `json:"rules"`
+}
+
+// UpdateCustomFieldParam the name and type of a field cannot change
+type UpdateCustomFieldParam struct {
+	Label    string                `json:"label" binding:"required,max=100"`
+	Required bool                  `json:"required"`
+	Rules    CustomFieldRulesParam `json:"rules"`
+}
+
+// ActivityParam Occurred_at is RFC 3339 and defaults to now
+type ActivityParam struct {
+	Type        string     `json:"type" binding:"required,oneof=note call meeting email"`
+	Subject     string     `json:"subject" binding:"max=255"`
+	Body        string     `json:"body" binding:"max=65535"`
+	Occurred_at *time.Time `json:"occurred_at"`
+}
+
+type TimelineParam struct {
+	Page  int `form:"page" binding:"min=0"`
+	Limit int `form:"limit" binding:"min=0"`
+}
+
+// CustomerAsOfParam As_of is an RFC 3339 time the customer is shown as of,
+// empty for its current state
+type CustomerAsOfParam struct {
+	As_of string `form:"as_of" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type HistoryParam struct {
+	Page  int `form:"page" binding:"min=0"`
+	Limit int `form:"limit" binding:"min=0"`
+}
+
+// CustomerValues versioned fields of a customer
+type CustomerValues struct {
 	First_name string `json:"first_name"`
 	Last_name  string `json:"last_name"`
 	Email      string `json:"email"`
 	Avatar     string `json:"avatar"`
+	Account_id *uint  `json:"account_id"`
+}
+
+// FieldChange value of a field before and after a version
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// HistoryEntry a version of a customer with the fields it changed compared
+// to the version before, Changes is empty for version 1
+type HistoryEntry struct {
+	Version       uint           `json:"version"`
+	Changed_at    time.Time      `json:"changed_at"`
+	Reverted_from *uint          `json:"reverted_from,omitempty"`
+	Values        CustomerValues `json:"values"`
+	Changes       []FieldChange  `json:"changes"`
+}
+
+type SegmentListParam struct {
+	Page  int `form:"page" binding:"min=0"`
+	Limit int `form:"limit" binding:"min=0"`
+}
+
+// DuplicateListParam MinScore defaults to 0.85, scans keep no pairs below
+// 0.7
+type DuplicateListParam struct {
+	Page     int     `form:"page" binding:"min=0"`
+	Limit    int     `form:"limit" binding:"min=0"`
+	MinScore float64 `form:"min_score" binding:"omitempty,min=0.7,max=1"`
+}
+
+// MergeCustomerParam Fields maps first_name, last_name, email or avatar to
+// the id of the customer whose value is kept, unlisted fields keep the
+// survivor's value
+type MergeCustomerParam struct {
+	Survivor_id uint            `json:"survivor_id" binding:"required"`
+	Victim_ids  []uint          `json:"victim_ids" binding:"required,min=1,max=50,dive,required"`
+	Fields      map[string]uint `json:"fields"`
+}
+
+// ImportParam bound from the query string next to map[field]=header,
+// OnDuplicate defaults to fail and BatchSize to 500
+type ImportParam struct {
+	DryRun      bool   `form:"dry_run"`
+	OnDuplicate string `form:"on_duplicate" binding:"omitempty,oneof=skip update fail"`
+	BatchSize   int    `form:"batch_size" binding:"min=0,max=5000"`
+	Delimiter   string `form:"delimiter" binding:"omitempty,len=1"`
+}
+
+// ExportParam bound from the query string next to the list filters, Columns
+// is comma separated and Format defaults to csv
+type ExportParam struct {
+	Format  string `form:"format" binding:"omitempty,oneof=csv ndjson xlsx"`
+	Columns string `form:"columns"`
+}
+
+type SuccessImport struct {
+	dto.ResponseMeta
+	Data ImportReport `json:"data"`
+}
+
+type MergeResult struct {
+	Customer   entity.Customer `json:"customer"`
+	Merged_ids []uint          `json:"merged_ids"`
+}
+
+type SuccessMerge struct {
+	dto.ResponseMeta
+	Data MergeResult `json:"data"`
+}
+
+// ExistingCustomer data of a 409 response, the customer already holding
+// the email
+type ExistingCustomer struct {
+	ID    uint   `json:"id"`
+	Email string `json:"email"`
+	Href  string `json:"href"`
+}
+
+// CreatedCustomer CustomerParam of a new customer together with its id
+type CreatedCustomer struct {
+	ID uint `json:"id"`
+	CustomerParam
 }
 
 type SuccessCreate struct {
 	dto.ResponseMeta
-	Data CustomerParam `json:"data"`
+	Data CreatedCustomer `json:"data"`
 }
 
 type FindCustomer struct {
 	dto.ResponseMeta
 	Data entity.Customer `json:"data"`
 }
+
+type ListCustomer struct {
+	dto.ListResponseMeta
+	Data []entity.Customer `json:"data"`
+}
+
+type FindSegment struct {
+	dto.ResponseMeta
+	Data entity.Segment `json:"data"`
+}
+
+type ListSegment struct {
+	dto.ListResponseMeta
+	Data []entity.Segment `json:"data"`
+}
+
+type FindActivity struct {
+	dto.ResponseMeta
+	Data entity.Activity `json:"data"`
+}
+
+type ListTimeline struct {
+	dto.ListResponseMeta
+	Data []entity.TimelineEntry `json:"data"`
+}
+
+type ListHistory struct {
+	dto.ListResponseMeta
+	Data []HistoryEntry `json:"data"`
+}
+
+type FindCustomField struct {
+	dto.ResponseMeta
+	Data entity.CustomField `json:"data"`
+}
+
+type ListCustomField struct {
+	dto.ResponseMeta
+	Data []entity.CustomField `json:"data"`
+}
+
+// DuplicateScanInfo when the listed pairs were found. Truncated_blocks
+// counts the blocking keys shared by more than 200 customers, only the first
+// 200 of them by id were compared so pairs among the others are missing
+type DuplicateScanInfo struct {
+	Scanned_at       time.Time `json:"scanned_at"`
+	Customers        int       `json:"customers"`
+	Truncated_blocks int       `json:"truncated_blocks"`
+}
+
+type ListDuplicates struct {
+	dto.ListResponseMeta
+	Scan DuplicateScanInfo `json:"scan"`
+	Data []DuplicatePair   `json:"data"`
+}
@@ -0,0 +1,327 @@
+package customers
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+)
+
+const (
+	defaultDuplicateScore = 0.85
+	// minDuplicateScore lowest score a scan keeps, min_score cannot go below
+	minDuplicateScore = 0.7
+	// maxBlockSize caps the pairwise comparisons of one blocking key, a very
+	// common name would otherwise compare every customer with every other
+	maxBlockSize = 200
+)
+
+// DuplicatePair two customers that are likely the same person, Score is the
+// mean of the name and email similarity between 0 and 1
+type DuplicatePair struct {
+	Score       float64           `json:"score"`
+	Name_score  float64           `json:"name_score"`
+	Email_score float64           `json:"email_score"`
+	Reasons     []string          `json:"reasons"`
+	Customers   []entity.Customer `json:"customers"`
+}
+
+// duplicateCandidate what a scan keeps of a customer, its id and the
+// normalized name and email it is scored on
+type duplicateCandidate struct {
+	id      uint
+	name    string
+	swapped string
+	email   string
+}
+
+// duplicateIndex customers grouped by blocking key (email, email local part,
+// name or sounds-like name). It is filled a batch at a time, so a scan holds
+// candidates rather than whole customers
+type duplicateIndex struct {
+	candidates map[uint]duplicateCandidate
+	blocks     map[string][]uint
+}
+
+func newDuplicateIndex() duplicateIndex {
+	return duplicateIndex{
+		candidates: map[uint]duplicateCandidate{},
+		blocks:     map[string][]uint{},
+	}
+}
+
+// add index a batch of customers
+func (idx duplicateIndex) add(customers []entity.Customer) {
+	for _, customer := range customers {
+		idx.candidates[customer.ID] = duplicateCandidate{
+			id:      customer.ID,
+			name:    normalizeName(customer.First_name + " " + customer.Last_name),
+			swapped: normalizeName(customer.Last_name + " " + customer.First_name),
+			email:   repository.NormalizeEmail(customer.Email),
+		}
+		for _, key := range blockingKeys(customer) {
+			idx.blocks[key] = append(idx.blocks[key], customer.ID)
+		}
+	}
+}
+
+// pairs of indexed customers scoring at least minScore, best first, and the
+// number of blocks cut to maxBlockSize. Only customers sharing a blocking key
+// are compared. Identical emails are always reported
+func (idx duplicateIndex) pairs(minScore float64) ([]entity.CustomerDuplicate, int) {
+	seen := map[[2]uint]bool{}
+	var pairs []entity.CustomerDuplicate
+	truncated := 0
+	for _, members := range idx.blocks {
+		if len(members) > maxBlockSize {
+			members = members[:maxBlockSize]
+			truncated++
+		}
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				key := [2]uint{members[x], members[y]}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				pair := comparePair(idx.candidates[key[0]], idx.candidates[key[1]])
+				if pair.Score >= minScore || pair.Email_score == 1 {
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		if pairs[i].Customer_id != pairs[j].Customer_id {
+			return pairs[i].Customer_id < pairs[j].Customer_id
+		}
+		return pairs[i].Other_id < pairs[j].Other_id
+	})
+	return pairs, truncated
+}
+
+func comparePair(a duplicateCandidate, b duplicateCandidate) entity.CustomerDuplicate {
+	nameScore := jaroWinkler(a.name, b.name)
+	// "Doe John" against "John Doe"
+	swapped := jaroWinkler(a.name, b.swapped)
+	if swapped > nameScore {
+		nameScore = swapped
+	}
+
+	var emailScore float64
+	var reasons []string
+	if a.email != "" && a.email == b.email {
+		emailScore = 1
+		reasons = append(reasons, "same email")
+	} else {
+		localA, domainA := splitEmail(a.email)
+		localB, domainB := splitEmail(b.email)
+		emailScore = jaroWinkler(localA, localB)
+		if domainA != domainB {
+			emailScore *= 0.9
+		}
+		if localA != "" && localA == localB {
+			reasons = append(reasons, "same email address name")
+		} else if emailScore >= defaultDuplicateScore {
+			reasons = append(reasons, "similar email")
+		}
+	}
+
+	if a.name != "" && nameScore == 1 {
+		reasons = append(reasons, "same name")
+	} else if nameScore >= defaultDuplicateScore {
+		reasons = append(reasons, "similar name")
+	}
+
+	if b.id < a.id {
+		a, b = b, a
+	}
+	return entity.CustomerDuplicate{
+		Customer_id: a.id,
+		Other_id:    b.id,
+		Score:       round2((nameScore + emailScore) / 2),
+		Name_score:  round2(nameScore),
+		Email_score: round2(emailScore),
+		Reasons:     strings.Join(reasons, ","),
+	}
+}
+
+func blockingKeys(customer entity.Customer) []string {
+	var keys []string
+	email := repository.NormalizeEmail(customer.Email)
+	if email != "" {
+		keys = append(keys, "email:"+email)
+	}
+	if local, _ := splitEmail(email); len(local) >= 3 {
+		keys = append(keys, "local:"+local)
+	}
+
+	name := normalizeName(customer.First_name + " " + customer.Last_name)
+	if name != "" {
+		tokens := strings.Fields(name)
+		sort.Strings(tokens)
+		keys = append(keys, "name:"+strings.Join(tokens, " "))
+	}
+	// surnames without ASCII letters have no soundex code, they would all
+	// share one block
+	first, last := normalizeName(customer.First_name), normalizeName(customer.Last_name)
+	if code := soundex(last); first != "" && code != "" {
+		initial, _ := utf8.DecodeRuneInString(first)
+		keys = append(keys, "sound:"+code+string(initial))
+	}
+	return keys
+}
+
+// splitEmail canonical local part and domain, dots and "+tag" suffixes are
+// dropped from the local part since most providers ignore them
+func splitEmail(email string) (string, string) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email, ""
+	}
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	return strings.ReplaceAll(local, ".", ""), domain
+}
+
+// normalizeName lower case letters and digits separated by single spaces
+func normalizeName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		} else {
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// soundex American Soundex code of the ASCII letters of s, empty when it has
+// none
+func soundex(s string) string {
+	codes := map[rune]byte{
+		'b': '1', 'f': '1', 'p': '1', 'v': '1',
+		'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+		'd': '3', 't': '3',
+		'l': '4',
+		'm': '5', 'n': '5',
+		'r': '6',
+	}
+	var res []byte
+	var last byte
+	for _, r := range s {
+		if r < 'a' || r > 'z' {
+			continue
+		}
+		code := codes[r]
+		if len(res) == 0 {
+			res = append(res, byte(unicode.ToUpper(r)))
+			last = code
+			continue
+		}
+		if code != 0 && code != last {
+			res = append(res, code)
+		}
+		// h and w do not separate letters with the same code, vowels do
+		if r != 'h' && r != 'w' {
+			last = code
+		}
+		if len(res) == 4 {
+			break
+		}
+	}
+	for len(res) > 0 && len(res) < 4 {
+		res = append(res, '0')
+	}
+	return string(res)
+}
+
+// jaroWinkler similarity of a and b between 0 and 1
+func jaroWinkler(a string, b string) float64 {
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	window := maxInt(len(s1), len(s2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		from, to := maxInt(0, i-window), minInt(len(s2), i+window+1)
+		for j := from; j < to; j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < minInt(4, minInt(len(s1), len(s2))) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+func round2(f float64) float64 {
+	return float64(int(f*100+0.5)) / 100
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,79 @@
+package customers
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJaroWinkler(t *testing.T) {
+	assert.Equal(t, 1.0, jaroWinkler("martha", "martha"))
+	assert.InDelta(t, 0.961, jaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, jaroWinkler("dwayne", "duane"), 0.001)
+	assert.Equal(t, 0.0, jaroWinkler("abc", "xyz"))
+	assert.Equal(t, 0.0, jaroWinkler("", "abc"))
+}
+
+func TestSoundex(t *testing.T) {
+	assert.Equal(t, "R163", soundex("robert"))
+	assert.Equal(t, "R163", soundex("rupert"))
+	assert.Equal(t, "A261", soundex("ashcraft"))
+	assert.Equal(t, "T522", soundex("tymczak"))
+	assert.Equal(t, "P236", soundex("pfister"))
+	assert.Equal(t, "L000", soundex("lee"))
+	assert.Equal(t, "", soundex("иванов"))
+}
+
+func indexPairs(customers []entity.Customer, minScore float64) ([]entity.CustomerDuplicate, int) {
+	index := newDuplicateIndex()
+	index.add(customers[:len(customers)/2])
+	index.add(customers[len(customers)/2:])
+	return index.pairs(minScore)
+}
+
+func TestFindDuplicates(t *testing.T) {
+	customers := []entity.Customer{
+		{ID: 1, First_name: "Katherine", Last_name: "Smith", Email: "kathy@mail.com"},
+		{ID: 2, First_name: "Catherine", Last_name: "Smyth", Email: "catherine@other.com"},
+		{ID: 3, First_name: "Budi", Last_name: "Santoso", Email: "budi@example.co.id"},
+		{ID: 4, First_name: "Budi", Last_name: "Santoso", Email: "BUDI@example.co.id"},
+		{ID: 5, First_name: "Siti", Last_name: "Rahayu", Email: "siti.rahayu@example.co.id"},
+		{ID: 6, First_name: "Siti", Last_name: "Rahayu", Email: "sitirahayu@example.co.id"},
+		{ID: 7, First_name: "Andi", Last_name: "Wijaya", Email: "andi@example.co.id"},
+	}
+
+	pairs, truncated := indexPairs(customers, defaultDuplicateScore)
+
+	assert.Zero(t, truncated)
+	var ids [][2]uint
+	for _, pair := range pairs {
+		ids = append(ids, [2]uint{pair.Customer_id, pair.Other_id})
+	}
+	assert.Equal(t, [][2]uint{{3, 4}, {5, 6}}, ids)
+	assert.Equal(t, 1.0, pairs[0].Score)
+	assert.Equal(t, "same email,same name", pairs[0].Reasons)
+	assert.Equal(t, "same email address name,same name", pairs[1].Reasons)
+}
+
+func TestFindDuplicates_TruncatedBlock(t *testing.T) {
+	var customers []entity.Customer
+	for i := 1; i <= maxBlockSize+1; i++ {
+		customers = append(customers, entity.Customer{ID: uint(i), First_name: "Budi", Last_name: "Santoso"})
+	}
+
+	pairs, truncated := indexPairs(customers, 0)
+
+	// the name and sounds-like blocks hold every customer
+	assert.Equal(t, 2, truncated)
+	assert.Len(t, pairs, maxBlockSize*(maxBlockSize-1)/2)
+	for _, pair := range pairs {
+		assert.NotEqual(t, uint(maxBlockSize+1), pair.Other_id)
+	}
+}
+
+func TestBlockingKeys_NonAscii(t *testing.T) {
+	assert.Equal(t, []string{"name:ivan иванов"}, blockingKeys(entity.Customer{First_name: "Ivan", Last_name: "Иванов"}))
+	// the initial is a whole rune, not its first byte
+	assert.Equal(t, []string{"name:müller özil", "sound:M460ö"}, blockingKeys(entity.Customer{First_name: "Özil", Last_name: "Müller"}))
+}
@@ -0,0 +1,218 @@
+package customers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/xlsx"
+)
+
+// Formats of an export
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+	ExportXLSX   = "xlsx"
+)
+
+// exportColumns every column an export can have, in their default order
+var exportColumns = []string{"id", "first_name", "last_name", "email", "avatar", "created_at", "updated_at"}
+
+var ErrExportUnknownColumn = apperror.Validation("columns may only list id, first_name, last_name, email, avatar, created_at or updated_at", nil)
+
+// ExportContentType content type and file extension of format
+func ExportContentType(format string) (string, string) {
+	switch format {
+	case ExportNDJSON:
+		return "application/x-ndjson", "ndjson"
+	case ExportXLSX:
+		return xlsx.ContentType, "xlsx"
+	default:
+		return "text/csv; charset=utf-8", "csv"
+	}
+}
+
+// exportEncoder writes the rows of one export format, Header is always
+// called once before the first row
+type exportEncoder interface {
+	Header(columns []string) error
+	Row(values []any) error
+	Close() error
+}
+
+// ExportCustomers write every customer matching filter to w, the filter is
+// checked before anything is written so a bad request fails cleanly
+func (uc useCaseCustomer) ExportCustomers(filter CustomerListParam, req ExportParam, w io.Writer) error {
+	columns, err := parseColumns(req.Columns)
+	if err != nil {
+		return err
+	}
+	repoFilter, err := uc.customerFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	var encoder exportEncoder
+	switch req.Format {
+	case ExportNDJSON:
+		encoder = &ndjsonEncoder{w: bufio.NewWriter(w)}
+	case ExportXLSX:
+		encoder = &xlsxEncoder{w: xlsx.NewWriter(w, "Customers")}
+	default:
+		encoder = &csvEncoder{w: csv.NewWriter(w)}
+	}
+
+	// the header waits for the first row so a failing query writes nothing
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		return encoder.Header(columns)
+	}
+	err = uc.customerRepo.ExportCustomers(repoFilter, func(customer entity.Customer) error {
+		if err := start(); err != nil {
+			return err
+		}
+		return encoder.Row(exportValues(customer, columns))
+	})
+	if err != nil {
+		return err
+	}
+	if err := start(); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// parseColumns comma separated columns, all of them when empty
+func parseColumns(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return exportColumns, nil
+	}
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		column = strings.TrimSpace(column)
+		known := false
+		for _, exportColumn := range exportColumns {
+			known = known || column == exportColumn
+		}
+		if !known {
+			return nil, ErrExportUnknownColumn
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+func exportValues(customer entity.Customer, columns []string) []any {
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		switch column {
+		case "id":
+			values[i] = customer.ID
+		case "first_name":
+			values[i] = customer.First_name
+		case "last_name":
+			values[i] = customer.Last_name
+		case "email":
+			values[i] = customer.Email
+		case "avatar":
+			values[i] = customer.Avatar
+		case "created_at":
+			values[i] = customer.CreatedAt.UTC()
+		case "updated_at":
+			values[i] = customer.UpdatedAt.UTC()
+		}
+	}
+	return values
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) Header(columns []string) error {
+	return e.w.Write(columns)
+}
+
+func (e *csvEncoder) Row(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case uint:
+			record[i] = strconv.FormatUint(uint64(v), 10)
+		case time.Time:
+			record[i] = v.Format(time.RFC3339)
+		case string:
+			record[i] = v
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ndjsonEncoder one JSON object per line, keys in column order
+type ndjsonEncoder struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+func (e *ndjsonEncoder) Header(columns []string) error {
+	e.columns = columns
+	return nil
+}
+
+func (e *ndjsonEncoder) Row(values []any) error {
+	e.w.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			e.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.columns[i])
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		e.w.Write(key)
+		e.w.WriteByte(':')
+		e.w.Write(encoded)
+	}
+	_, err := e.w.WriteString("}\n")
+	return err
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return e.w.Flush()
+}
+
+type xlsxEncoder struct {
+	w *xlsx.Writer
+}
+
+func (e *xlsxEncoder) Header(columns []string) error {
+	header := make([]any, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	return e.w.WriteRow(header)
+}
+
+func (e *xlsxEncoder) Row(values []any) error {
+	return e.w.WriteRow(values)
+}
+
+func (e *xlsxEncoder) Close() error {
+	return e.w.Close()
+}
@@ -0,0 +1,118 @@
+package customers
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func mockExport(mockRepo *mocks.CustomerInterfaceRepo, customers ...entity.Customer) {
+	mockRepo.On("ExportCustomers", mock.Anything, mock.Anything).Return(func(filter repository.CustomerFilter, fn func(entity.Customer) error) error {
+		for _, customer := range customers {
+			if err := fn(customer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func TestExportCustomers(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	customers := []entity.Customer{
+		{ID: 1, First_name: "John", Last_name: "Doe, Jr.", Email: "john@example.com", CreatedAt: created},
+		{ID: 2, First_name: "Jane", Email: "jane@example.com", CreatedAt: created},
+	}
+
+	tests := []struct {
+		name string
+		req  ExportParam
+		want string
+	}{
+		{
+			name: "csv",
+			req:  ExportParam{Columns: "id, last_name,created_at"},
+			want: "id,last_name,created_at\n1,\"Doe, Jr.\",2024-01-02T03:04:05Z\n2,,2024-01-02T03:04:05Z\n",
+		},
+		{
+			name: "ndjson",
+			req:  ExportParam{Format: ExportNDJSON, Columns: "email,id"},
+			want: "{\"email\":\"john@example.com\",\"id\":1}\n{\"email\":\"jane@example.com\",\"id\":2}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CustomerInterfaceRepo)
+			useCase := useCaseCustomer{customerRepo: mockRepo}
+			mockExport(mockRepo, customers...)
+
+			var out bytes.Buffer
+			err := useCase.ExportCustomers(CustomerListParam{}, tt.req, &out)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestExportCustomers_Filter(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	useCase := useCaseCustomer{customerRepo: mockRepo}
+	mockExport(mockRepo)
+
+	var out bytes.Buffer
+	err := useCase.ExportCustomers(CustomerListParam{Email: "example", Sort: "-last_name", Limit: 5}, ExportParam{Columns: "email"}, &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, "email\n", out.String())
+	filter := mockRepo.Calls[0].Arguments.Get(0).(repository.CustomerFilter)
+	assert.Equal(t, "example", filter.Email)
+	assert.Equal(t, "last_name", filter.Sort[0].Column)
+	assert.Zero(t, filter.Limit)
+}
+
+func TestExportCustomers_NothingWrittenOnError(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter CustomerListParam
+		req    ExportParam
+		err    error
+	}{
+		{name: "unknown column", req: ExportParam{Columns: "id,password"}, err: ErrExportUnknownColumn},
+		{name: "invalid sort", filter: CustomerListParam{Sort: "password"}, err: ErrInvalidSort},
+		{name: "query fails", err: apperror.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CustomerInterfaceRepo)
+			useCase := useCaseCustomer{customerRepo: mockRepo}
+			mockRepo.On("ExportCustomers", mock.Anything, mock.Anything).Return(apperror.Conflict("locked", nil)).Maybe()
+
+			var out bytes.Buffer
+			err := useCase.ExportCustomers(tt.filter, tt.req, &out)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Zero(t, out.Len())
+		})
+	}
+}
+
+func TestExportCustomers_Xlsx(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	useCase := useCaseCustomer{customerRepo: mockRepo}
+	mockExport(mockRepo, entity.Customer{ID: 1, First_name: "John"})
+
+	var out bytes.Buffer
+	err := useCase.ExportCustomers(CustomerListParam{}, ExportParam{Format: ExportXLSX}, &out)
+
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out.Bytes(), []byte("PK")), "xlsx is a zip file")
+}
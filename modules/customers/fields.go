@@ -0,0 +1,423 @@
+package customers
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/validation"
+)
+
+// maxFieldValueLength longest string value, also when the field sets no
+// max_length
+const maxFieldValueLength = 1000
+
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var (
+	ErrFieldName        = apperror.Validation("name must start with a lower case letter followed by lower case letters, digits or underscores", nil)
+	ErrFieldNameTaken   = apperror.Conflict("a custom field with this name already exists", nil)
+	ErrFieldOptions     = apperror.Validation("options are required for enum fields and only allowed for them", nil)
+	ErrFieldOptionTwice = apperror.Validation("options must not repeat", nil)
+	ErrFieldNumberRules = apperror.Validation("min, max and integer only apply to number fields", nil)
+	ErrFieldNumberRange = apperror.Validation("min must not be greater than max", nil)
+	ErrFieldStringRules = apperror.Validation("min_length, max_length and pattern only apply to string fields", nil)
+	ErrFieldLengthRange = apperror.Validation("min_length must not be greater than max_length", nil)
+	ErrFieldPattern     = apperror.Validation("pattern must be a valid regular expression", nil)
+)
+
+// CreateCustomField define a new customer attribute
+func (uc useCaseCustomer) CreateCustomField(req CustomFieldParam) (entity.CustomField, error) {
+	field := entity.CustomField{
+		Name:      req.Name,
+		Label:     strings.TrimSpace(req.Label),
+		Type:      req.Type,
+		Required:  req.Required,
+		Rules:     entity.CustomFieldRules(req.Rules),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if !fieldNamePattern.MatchString(field.Name) {
+		return field, ErrFieldName
+	}
+	err := checkFieldRules(field.Type, field.Rules)
+	if err != nil {
+		return field, err
+	}
+	err = uc.fieldRepo.CreateCustomField(&field)
+	if errors.Is(err, apperror.ErrConflict) {
+		return field, ErrFieldNameTaken
+	}
+	return field, err
+}
+
+func (uc useCaseCustomer) GetCustomField(id uint) (entity.CustomField, error) {
+	return uc.fieldRepo.GetCustomFieldById(id)
+}
+
+func (uc useCaseCustomer) ListCustomFields() ([]entity.CustomField, error) {
+	return uc.fieldRepo.ListCustomFields()
+}
+
+// UpdateCustomField change the label, whether it is required and the rules,
+// stored values are not checked again
+func (uc useCaseCustomer) UpdateCustomField(req UpdateCustomFieldParam, id uint) (entity.CustomField, error) {
+	field, err := uc.fieldRepo.GetCustomFieldById(id)
+	if err != nil {
+		return field, err
+	}
+	field.Label = strings.TrimSpace(req.Label)
+	field.Required = req.Required
+	field.Rules = entity.CustomFieldRules(req.Rules)
+	field.UpdatedAt = time.Now()
+	err = checkFieldRules(field.Type, field.Rules)
+	if err != nil {
+		return field, err
+	}
+	err = uc.fieldRepo.UpdateCustomField(&field, id)
+	return field, err
+}
+
+// DeleteCustomField the values of every customer are deleted too
+func (uc useCaseCustomer) DeleteCustomField(id uint) error {
+	return uc.fieldRepo.DeleteCustomField(id)
+}
+
+// SetCustomFields change custom field values of a customer, a null value
+// removes it and fields left out keep their value
+func (uc useCaseCustomer) SetCustomFields(id uint, values map[string]any) (entity.Customer, error) {
+	_, err := uc.customerRepo.GetCustomerById(id)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	fields, err := uc.fieldRepo.ListCustomFields()
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	set, clear, err := customFieldValues(fields, values, false)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	err = uc.fieldRepo.SetCustomFieldValues(id, set, clear)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	return uc.GetCustomerById(id)
+}
+
+// checkFieldRules rules must fit the type of the field
+func checkFieldRules(fieldType string, rules entity.CustomFieldRules) error {
+	if (fieldType == entity.FieldTypeEnum) != (len(rules.Options) > 0) {
+		return ErrFieldOptions
+	}
+	seen := make(map[string]bool, len(rules.Options))
+	for _, option := range rules.Options {
+		if seen[option] {
+			return ErrFieldOptionTwice
+		}
+		seen[option] = true
+	}
+
+	if fieldType != entity.FieldTypeNumber && (rules.Min != nil || rules.Max != nil || rules.Integer) {
+		return ErrFieldNumberRules
+	}
+	if rules.Min != nil && rules.Max != nil && *rules.Min > *rules.Max {
+		return ErrFieldNumberRange
+	}
+
+	if fieldType != entity.FieldTypeString && (rules.Min_length > 0 || rules.Max_length > 0 || rules.Pattern != "") {
+		return ErrFieldStringRules
+	}
+	if rules.Max_length > 0 && rules.Min_length > rules.Max_length {
+		return ErrFieldLengthRange
+	}
+	if _, err := regexp.Compile(rules.Pattern); err != nil {
+		return ErrFieldPattern
+	}
+	return nil
+}
+
+// customFieldValues check values sent for a customer against fields, the
+// values to save are returned along with the ids of fields set to null.
+// creating also requires the required fields
+func customFieldValues(fields []entity.CustomField, values map[string]any, creating bool) ([]entity.CustomFieldValue, []uint, error) {
+	byName := make(map[string]entity.CustomField, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+
+	var set []entity.CustomFieldValue
+	var clear []uint
+	var invalid []validation.FieldError
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field, ok := byName[name]
+		if !ok {
+			invalid = append(invalid, validation.NewFieldError("custom_fields."+name, "unknown", "", false))
+			continue
+		}
+		if values[name] == nil {
+			if field.Required {
+				invalid = append(invalid, validation.NewFieldError("custom_fields."+name, "required", "", false))
+			} else {
+				clear = append(clear, field.ID)
+			}
+			continue
+		}
+		value, fieldErr := fieldValue(field, values[name])
+		if fieldErr != nil {
+			invalid = append(invalid, *fieldErr)
+			continue
+		}
+		set = append(set, value)
+	}
+	if creating {
+		for _, field := range fields {
+			if _, ok := values[field.Name]; field.Required && !ok {
+				invalid = append(invalid, validation.NewFieldError("custom_fields."+field.Name, "required", "", false))
+			}
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, nil, apperror.Validation("invalid custom fields", invalid)
+	}
+	return set, clear, nil
+}
+
+// fieldValue stored form of a JSON value of field
+func fieldValue(field entity.CustomField, raw any) (entity.CustomFieldValue, *validation.FieldError) {
+	name := "custom_fields." + field.Name
+	invalid := func(rule string, param string, text bool) (entity.CustomFieldValue, *validation.FieldError) {
+		fieldErr := validation.NewFieldError(name, rule, param, text)
+		return entity.CustomFieldValue{}, &fieldErr
+	}
+	value := entity.CustomFieldValue{Field_id: field.ID}
+	rules := field.Rules
+
+	switch field.Type {
+	case entity.FieldTypeNumber:
+		number, ok := raw.(float64)
+		if !ok {
+			return invalid(entity.FieldTypeNumber, "", false)
+		}
+		if rules.Integer && number != math.Trunc(number) {
+			return invalid("integer", "", false)
+		}
+		if rules.Min != nil && number < *rules.Min {
+			return invalid("min", formatNumber(*rules.Min), false)
+		}
+		if rules.Max != nil && number > *rules.Max {
+			return invalid("max", formatNumber(*rules.Max), false)
+		}
+		value.Value = formatNumber(number)
+		value.Number = &number
+	case entity.FieldTypeBoolean:
+		flag, ok := raw.(bool)
+		if !ok {
+			return invalid(entity.FieldTypeBoolean, "", false)
+		}
+		value.Value = strconv.FormatBool(flag)
+	default:
+		text, ok := raw.(string)
+		if !ok {
+			return invalid(entity.FieldTypeString, "", false)
+		}
+		text = strings.TrimSpace(text)
+		switch field.Type {
+		case entity.FieldTypeDate:
+			if _, err := time.Parse("2006-01-02", text); err != nil {
+				return invalid(entity.FieldTypeDate, "", false)
+			}
+		case entity.FieldTypeEnum:
+			if !containsString(rules.Options, text) {
+				return invalid("oneof", strings.Join(rules.Options, " "), false)
+			}
+		default:
+			length := utf8.RuneCountInString(text)
+			maxLength := rules.Max_length
+			if maxLength <= 0 || maxLength > maxFieldValueLength {
+				maxLength = maxFieldValueLength
+			}
+			if length < rules.Min_length {
+				return invalid("min", strconv.Itoa(rules.Min_length), true)
+			}
+			if length > maxLength {
+				return invalid("max", strconv.Itoa(maxLength), true)
+			}
+			if rules.Pattern != "" && !regexp.MustCompile(rules.Pattern).MatchString(text) {
+				return invalid("pattern", "", false)
+			}
+		}
+		value.Value = text
+	}
+	return value, nil
+}
+
+// customFieldMap values of a customer by field name as JSON values, values
+// of fields that no longer exist are left out
+func customFieldMap(fields []entity.CustomField, values []entity.CustomFieldValue) map[string]any {
+	if len(values) == 0 {
+		return nil
+	}
+	byId := make(map[uint]entity.CustomField, len(fields))
+	for _, field := range fields {
+		byId[field.ID] = field
+	}
+	res := make(map[string]any, len(values))
+	for _, value := range values {
+		field, ok := byId[value.Field_id]
+		if !ok {
+			continue
+		}
+		switch {
+		case field.Type == entity.FieldTypeNumber && value.Number != nil:
+			res[field.Name] = *value.Number
+		case field.Type == entity.FieldTypeBoolean:
+			res[field.Name] = value.Value == "true"
+		default:
+			res[field.Name] = value.Value
+		}
+	}
+	return res
+}
+
+// withCustomFields fill Custom_fields of customers from their Field_values
+func (uc useCaseCustomer) withCustomFields(customers []entity.Customer) error {
+	var fields []entity.CustomField
+	for i := range customers {
+		if len(customers[i].Field_values) > 0 && fields == nil {
+			var err error
+			fields, err = uc.fieldRepo.ListCustomFields()
+			if err != nil {
+				return err
+			}
+		}
+		customers[i].Custom_fields = customFieldMap(fields, customers[i].Field_values)
+	}
+	return nil
+}
+
+// fieldConditions read cf[name]=value filters of the customer list. Strings
+// match a part of the value, enums any of comma separated values, booleans
+// true or false and numbers and dates one value or a min..max range with
+// either end left open
+func (uc useCaseCustomer) fieldConditions(filters map[string]string) ([]repository.FieldCondition, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	fields, err := uc.fieldRepo.ListCustomFields()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]entity.CustomField, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+
+	var conditions []repository.FieldCondition
+	var invalid []validation.FieldError
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		field, ok := byName[name]
+		if !ok {
+			invalid = append(invalid, validation.NewFieldError("cf["+name+"]", "unknown", "", false))
+			continue
+		}
+		condition, ok := fieldCondition(field, strings.TrimSpace(filters[name]))
+		if !ok {
+			invalid = append(invalid, validation.NewFieldError("cf["+name+"]", field.Type, "", false))
+			continue
+		}
+		conditions = append(conditions, condition)
+	}
+	if len(invalid) > 0 {
+		return nil, apperror.Validation("invalid custom field filter", invalid)
+	}
+	return conditions, nil
+}
+
+func fieldCondition(field entity.CustomField, filter string) (repository.FieldCondition, bool) {
+	condition := repository.FieldCondition{Field_id: field.ID}
+	switch field.Type {
+	case entity.FieldTypeString:
+		condition.Contains = filter
+	case entity.FieldTypeEnum:
+		for _, value := range strings.Split(filter, ",") {
+			condition.Values = append(condition.Values, strings.TrimSpace(value))
+		}
+	case entity.FieldTypeBoolean:
+		if filter != "true" && filter != "false" {
+			return condition, false
+		}
+		condition.Values = []string{filter}
+	case entity.FieldTypeNumber:
+		from, to := splitRange(filter)
+		for _, bound := range []struct {
+			text   string
+			target **float64
+		}{{from, &condition.MinNumber}, {to, &condition.MaxNumber}} {
+			if bound.text == "" {
+				continue
+			}
+			number, err := strconv.ParseFloat(bound.text, 64)
+			if err != nil {
+				return condition, false
+			}
+			*bound.target = &number
+		}
+	case entity.FieldTypeDate:
+		from, to := splitRange(filter)
+		for _, bound := range []struct {
+			text   string
+			target **string
+		}{{from, &condition.MinText}, {to, &condition.MaxText}} {
+			if bound.text == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", bound.text); err != nil {
+				return condition, false
+			}
+			text := bound.text
+			*bound.target = &text
+		}
+	}
+	return condition, true
+}
+
+// splitRange "10..20", "10..", "..20" or just "10", which is both ends
+func splitRange(filter string) (string, string) {
+	from, to, isRange := strings.Cut(filter, "..")
+	if !isRange {
+		return filter, filter
+	}
+	return strings.TrimSpace(from), strings.TrimSpace(to)
+}
+
+func formatNumber(number float64) string {
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
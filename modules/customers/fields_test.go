@@ -0,0 +1,256 @@
+package customers
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testCustomFields() []entity.CustomField {
+	min, max := 1.0, 500.0
+	return []entity.CustomField{
+		{ID: 1, Name: "plan", Type: entity.FieldTypeEnum, Required: true, Rules: entity.CustomFieldRules{Options: []string{"free", "pro"}}},
+		{ID: 2, Name: "seats", Type: entity.FieldTypeNumber, Rules: entity.CustomFieldRules{Min: &min, Max: &max, Integer: true}},
+		{ID: 3, Name: "renewal", Type: entity.FieldTypeDate},
+		{ID: 4, Name: "vat_id", Type: entity.FieldTypeString, Rules: entity.CustomFieldRules{Max_length: 12, Pattern: `^[A-Z]{2}[0-9]+$`}},
+		{ID: 5, Name: "newsletter", Type: entity.FieldTypeBoolean},
+	}
+}
+
+func fieldErrorNames(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var appErr *apperror.Error
+	require.ErrorAs(t, err, &appErr)
+	names := map[string]string{}
+	for _, fieldErr := range appErr.Details.([]validation.FieldError) {
+		names[fieldErr.Field] = fieldErr.Message.EN
+	}
+	return names
+}
+
+func TestCreateCustomField(t *testing.T) {
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{fieldRepo: mockFieldRepo}
+	mockFieldRepo.On("CreateCustomField", mock.MatchedBy(func(field *entity.CustomField) bool {
+		return field.Name == "plan" && field.Label == "Plan"
+	})).Return(nil)
+
+	field, err := useCase.CreateCustomField(CustomFieldParam{
+		Name:  "plan",
+		Label: " Plan ",
+		Type:  entity.FieldTypeEnum,
+		Rules: CustomFieldRulesParam{Options: []string{"free", "pro"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"free", "pro"}, field.Rules.Options)
+}
+
+func TestCreateCustomField_Invalid(t *testing.T) {
+	one, two := 1.0, 2.0
+	tests := []struct {
+		name string
+		req  CustomFieldParam
+		err  error
+	}{
+		{name: "name with spaces", req: CustomFieldParam{Name: "Vat ID", Type: entity.FieldTypeString}, err: ErrFieldName},
+		{name: "enum without options", req: CustomFieldParam{Name: "plan", Type: entity.FieldTypeEnum}, err: ErrFieldOptions},
+		{
+			name: "options on a string",
+			req:  CustomFieldParam{Name: "plan", Type: entity.FieldTypeString, Rules: CustomFieldRulesParam{Options: []string{"a"}}},
+			err:  ErrFieldOptions,
+		},
+		{
+			name: "repeated option",
+			req:  CustomFieldParam{Name: "plan", Type: entity.FieldTypeEnum, Rules: CustomFieldRulesParam{Options: []string{"a", "a"}}},
+			err:  ErrFieldOptionTwice,
+		},
+		{
+			name: "min above max",
+			req:  CustomFieldParam{Name: "seats", Type: entity.FieldTypeNumber, Rules: CustomFieldRulesParam{Min: &two, Max: &one}},
+			err:  ErrFieldNumberRange,
+		},
+		{
+			name: "number rule on a date",
+			req:  CustomFieldParam{Name: "renewal", Type: entity.FieldTypeDate, Rules: CustomFieldRulesParam{Integer: true}},
+			err:  ErrFieldNumberRules,
+		},
+		{
+			name: "pattern on a number",
+			req:  CustomFieldParam{Name: "seats", Type: entity.FieldTypeNumber, Rules: CustomFieldRulesParam{Pattern: "^a"}},
+			err:  ErrFieldStringRules,
+		},
+		{
+			name: "broken pattern",
+			req:  CustomFieldParam{Name: "vat_id", Type: entity.FieldTypeString, Rules: CustomFieldRulesParam{Pattern: "("}},
+			err:  ErrFieldPattern,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := useCaseCustomer{fieldRepo: mocks.NewCustomFieldInterfaceRepo(t)}
+			_, err := useCase.CreateCustomField(tt.req)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCreateCustomField_NameTaken(t *testing.T) {
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{fieldRepo: mockFieldRepo}
+	mockFieldRepo.On("CreateCustomField", mock.Anything).Return(apperror.Conflict("custom field already exists", nil))
+
+	_, err := useCase.CreateCustomField(CustomFieldParam{Name: "plan", Type: entity.FieldTypeString})
+
+	assert.ErrorIs(t, err, ErrFieldNameTaken)
+}
+
+func TestUpdateCustomField_RulesFollowType(t *testing.T) {
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{fieldRepo: mockFieldRepo}
+	mockFieldRepo.On("GetCustomFieldById", uint(2)).Return(testCustomFields()[1], nil)
+
+	_, err := useCase.UpdateCustomField(UpdateCustomFieldParam{Label: "Seats", Rules: CustomFieldRulesParam{Max_length: 3}}, 2)
+
+	assert.ErrorIs(t, err, ErrFieldStringRules)
+}
+
+func TestCreateCustomer_CustomFields(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, fieldRepo: mockFieldRepo}
+	mockFieldRepo.On("ListCustomFields").Return(testCustomFields(), nil)
+	mockRepo.On("GetCustomerByEmail", "jane@example.com").Return(entity.Customer{}, apperror.NotFound("customer not found"))
+	mockRepo.On("CreateCustomer", mock.MatchedBy(func(customer *entity.Customer) bool {
+		return len(customer.Field_values) == 3
+	})).Return(&entity.Customer{}, nil)
+
+	customer, err := useCase.CreateCustomer(CustomerParam{
+		First_name:    "Jane",
+		Email:         "jane@example.com",
+		Custom_fields: map[string]any{"plan": "pro", "seats": 25.0, "newsletter": true},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"plan": "pro", "seats": 25.0, "newsletter": true}, customer.Custom_fields)
+	var seats *entity.CustomFieldValue
+	for i := range customer.Field_values {
+		if customer.Field_values[i].Field_id == 2 {
+			seats = &customer.Field_values[i]
+		}
+	}
+	require.NotNil(t, seats)
+	assert.Equal(t, "25", seats.Value)
+	assert.Equal(t, 25.0, *seats.Number)
+}
+
+func TestCreateCustomer_InvalidCustomFields(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, fieldRepo: mockFieldRepo}
+	mockFieldRepo.On("ListCustomFields").Return(testCustomFields(), nil)
+
+	_, err := useCase.CreateCustomer(CustomerParam{
+		First_name: "Jane",
+		Email:      "jane@example.com",
+		Custom_fields: map[string]any{
+			"seats":      2.5,
+			"renewal":    "next year",
+			"vat_id":     "de123",
+			"newsletter": "yes",
+			"shoe_size":  42.0,
+		},
+	})
+
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	assert.Equal(t, map[string]string{
+		"custom_fields.plan":       "custom_fields.plan is required",
+		"custom_fields.seats":      "custom_fields.seats must be a whole number",
+		"custom_fields.renewal":    "custom_fields.renewal must be a date as YYYY-MM-DD",
+		"custom_fields.vat_id":     "custom_fields.vat_id is invalid",
+		"custom_fields.newsletter": "custom_fields.newsletter must be true or false",
+		"custom_fields.shoe_size":  "custom_fields.shoe_size is not a known field",
+	}, fieldErrorNames(t, err))
+	mockRepo.AssertNotCalled(t, "CreateCustomer", mock.Anything)
+}
+
+func TestSetCustomFields(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, fieldRepo: mockFieldRepo}
+	stored := entity.Customer{ID: 1, Field_values: []entity.CustomFieldValue{{Field_id: 3, Value: "2025-01-31"}}}
+	mockRepo.On("GetCustomerById", uint(1)).Return(stored, nil)
+	mockFieldRepo.On("ListCustomFields").Return(testCustomFields(), nil)
+	mockFieldRepo.On("SetCustomFieldValues", uint(1), []entity.CustomFieldValue{{Field_id: 3, Value: "2025-01-31"}}, []uint{4}).Return(nil)
+
+	customer, err := useCase.SetCustomFields(1, map[string]any{"renewal": " 2025-01-31 ", "vat_id": nil})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"renewal": "2025-01-31"}, customer.Custom_fields)
+}
+
+func TestSetCustomFields_ClearRequired(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, fieldRepo: mockFieldRepo}
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1}, nil)
+	mockFieldRepo.On("ListCustomFields").Return(testCustomFields(), nil)
+
+	_, err := useCase.SetCustomFields(1, map[string]any{"plan": nil})
+
+	assert.Equal(t, map[string]string{"custom_fields.plan": "custom_fields.plan is required"}, fieldErrorNames(t, err))
+}
+
+func TestListCustomers_CustomFieldFilters(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, fieldRepo: mockFieldRepo}
+	mockFieldRepo.On("ListCustomFields").Return(testCustomFields(), nil)
+	ten := 10.0
+	from, to := "2025-01-01", "2025-03-31"
+	mockRepo.On("ListCustomers", mock.MatchedBy(func(filter repository.CustomerFilter) bool {
+		return assert.ObjectsAreEqual([]repository.FieldCondition{
+			{Field_id: 5, Values: []string{"true"}},
+			{Field_id: 1, Values: []string{"free", "pro"}},
+			{Field_id: 3, MinText: &from, MaxText: &to},
+			{Field_id: 2, MinNumber: &ten},
+			{Field_id: 4, Contains: "DE"},
+		}, filter.Fields)
+	})).Return([]entity.Customer{{ID: 1, Field_values: []entity.CustomFieldValue{{Field_id: 2, Value: "12", Number: &[]float64{12}[0]}}}}, int64(1), nil)
+
+	page, err := useCase.ListCustomers(CustomerListParam{Custom_fields: map[string]string{
+		"plan":       "free, pro",
+		"seats":      "10..",
+		"renewal":    "2025-01-01..2025-03-31",
+		"vat_id":     "DE",
+		"newsletter": "true",
+	}})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"seats": 12.0}, page.Customers[0].Custom_fields)
+}
+
+func TestListCustomers_InvalidCustomFieldFilters(t *testing.T) {
+	mockFieldRepo := mocks.NewCustomFieldInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mocks.NewCustomerInterfaceRepo(t), fieldRepo: mockFieldRepo}
+	mockFieldRepo.On("ListCustomFields").Return(testCustomFields(), nil)
+
+	_, err := useCase.ListCustomers(CustomerListParam{Custom_fields: map[string]string{
+		"seats":      "ten..",
+		"newsletter": "maybe",
+		"color":      "red",
+	}})
+
+	assert.Equal(t, map[string]string{
+		"cf[seats]":      "cf[seats] must be a number",
+		"cf[newsletter]": "cf[newsletter] must be true or false",
+		"cf[color]":      "cf[color] is not a known field",
+	}, fieldErrorNames(t, err))
+}
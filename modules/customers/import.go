@@ -0,0 +1,451 @@
+package customers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/validation"
+)
+
+// What an import does with a row whose email already belongs to a customer
+const (
+	DuplicateSkip   = "skip"
+	DuplicateUpdate = "update"
+	DuplicateFail   = "fail"
+)
+
+const (
+	defaultImportBatch = 500
+	maxImportErrors    = 1000
+)
+
+var (
+	ErrImportEmpty        = apperror.Validation("the file is empty, a header row is expected", nil)
+	ErrImportUnknownField = apperror.Validation("map may only set first_name, last_name, email or avatar", nil)
+)
+
+// ImportOptions Mapping is customer field to CSV header, fields left out are
+// matched to a header with the same name
+type ImportOptions struct {
+	DryRun      bool
+	OnDuplicate string
+	BatchSize   int
+	Delimiter   rune
+	Mapping     map[string]string
+}
+
+// ImportRowError why one row was not imported, Row is the line in the file
+// with the header on line 1
+type ImportRowError struct {
+	Row     int                     `json:"row"`
+	Email   string                  `json:"email,omitempty"`
+	Message string                  `json:"message"`
+	Errors  []validation.FieldError `json:"errors,omitempty"`
+}
+
+// ImportReport outcome of an import, on a dry run the counts are what the
+// import would have done
+type ImportReport struct {
+	Dry_run          bool             `json:"dry_run"`
+	Rows             int              `json:"rows"`
+	Created          int              `json:"created"`
+	Updated          int              `json:"updated"`
+	Skipped          int              `json:"skipped"`
+	Failed           int              `json:"failed"`
+	Errors           []ImportRowError `json:"errors"`
+	Errors_truncated bool             `json:"errors_truncated,omitempty"`
+}
+
+func (report *ImportReport) fail(rowErr ImportRowError) {
+	report.Failed++
+	if len(report.Errors) >= maxImportErrors {
+		report.Errors_truncated = true
+		return
+	}
+	report.Errors = append(report.Errors, rowErr)
+}
+
+type importRow struct {
+	line     int
+	customer CustomerParam
+}
+
+// importer state kept across the batches of one file
+type importer struct {
+	uc     useCaseCustomer
+	opts   ImportOptions
+	report ImportReport
+	fields []entity.CustomField
+	// seen emails created by earlier batches of a dry run, a real import
+	// finds them in the database instead
+	seen map[string]bool
+}
+
+// ImportCustomers read customers from a CSV file one batch at a time, every
+// batch is written in its own transaction so a failed batch does not undo
+// the ones before it. Columns named after a custom field fill it, created
+// customers need every required one like on CreateCustomer
+func (uc useCaseCustomer) ImportCustomers(r io.Reader, opts ImportOptions) (ImportReport, error) {
+	if opts.OnDuplicate == "" {
+		opts.OnDuplicate = DuplicateFail
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatch
+	}
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+
+	imp := importer{
+		uc:     uc,
+		opts:   opts,
+		report: ImportReport{Dry_run: opts.DryRun, Errors: []ImportRowError{}},
+		seen:   map[string]bool{},
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return imp.report, ErrImportEmpty
+	}
+	if err != nil {
+		return imp.report, apperror.Validation("the header row is not valid CSV", err.Error())
+	}
+	columns, err := mapColumns(header, opts.Mapping)
+	if err != nil {
+		return imp.report, err
+	}
+	imp.fields, err = uc.fieldRepo.ListCustomFields()
+	if err != nil {
+		return imp.report, err
+	}
+	fieldColumns := customFieldColumns(header, columns, imp.fields)
+
+	batch := make([]importRow, 0, opts.BatchSize)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			imp.report.Rows++
+			imp.report.fail(ImportRowError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return imp.report, err
+		}
+		imp.report.Rows++
+
+		line, _ := reader.FieldPos(0)
+		row := importRow{line: line, customer: rowToParam(record, columns, fieldColumns)}
+		if fieldErrors := validation.Struct(row.customer); fieldErrors != nil {
+			imp.report.fail(ImportRowError{Row: line, Email: row.customer.Email, Message: "invalid row", Errors: fieldErrors})
+			continue
+		}
+		batch = append(batch, row)
+		if len(batch) == opts.BatchSize {
+			if err := imp.flush(batch); err != nil {
+				return imp.report, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := imp.flush(batch); err != nil {
+		return imp.report, err
+	}
+	return imp.report, nil
+}
+
+// flush apply the duplicate policy to a batch and write it, only a failed
+// lookup stops the import, a failed write fails the rows of its batch
+func (imp *importer) flush(batch []importRow) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	emails := make([]string, len(batch))
+	for i, row := range batch {
+		emails[i] = row.customer.Email
+	}
+	existing, err := imp.uc.customerRepo.GetCustomersByEmails(emails)
+	if err != nil {
+		return err
+	}
+	existingByEmail := make(map[string]entity.Customer, len(existing))
+	for _, customer := range existing {
+		existingByEmail[repository.NormalizeEmail(customer.Email)] = customer
+	}
+
+	var creates, updates []*entity.Customer
+	var written []importRow
+	// pending customers of this batch by email, with the line they came from
+	pending := map[string]*entity.Customer{}
+	pendingLine := map[string]int{}
+	skipped := 0
+	now := time.Now()
+	for _, row := range batch {
+		email := repository.NormalizeEmail(row.customer.Email)
+		customer := &entity.Customer{
+			First_name: row.customer.First_name,
+			Last_name:  row.customer.Last_name,
+			Email:      row.customer.Email,
+			Avatar:     row.customer.Avatar,
+			UpdatedAt:  now,
+		}
+
+		target, inBatch := pending[email]
+		found, inDatabase := existingByEmail[email]
+		if !inBatch && !inDatabase && !imp.seen[email] {
+			values, _, fieldErr := customFieldValues(imp.fields, row.customer.Custom_fields, true)
+			if fieldErr != nil {
+				imp.failInvalid(row, fieldErr)
+				continue
+			}
+			customer.Field_values = values
+			customer.CreatedAt = now
+			creates = append(creates, customer)
+			written = append(written, row)
+			pending[email] = customer
+			pendingLine[email] = row.line
+			continue
+		}
+
+		switch imp.opts.OnDuplicate {
+		case DuplicateSkip:
+			skipped++
+		case DuplicateFail:
+			message := "a customer with this email already exists"
+			if inDatabase {
+				message = fmt.Sprintf("customer %d already has this email", found.ID)
+			}
+			if inBatch {
+				message = fmt.Sprintf("email repeats row %d", pendingLine[email])
+			}
+			imp.report.fail(ImportRowError{Row: row.line, Email: row.customer.Email, Message: message})
+		case DuplicateUpdate:
+			values, _, fieldErr := customFieldValues(imp.fields, row.customer.Custom_fields, false)
+			if fieldErr != nil {
+				imp.failInvalid(row, fieldErr)
+				continue
+			}
+			customer.Field_values = values
+			if inBatch {
+				mergeImportRow(target, customer)
+				written = append(written, row)
+				continue
+			}
+			customer.ID = found.ID
+			updates = append(updates, customer)
+			written = append(written, row)
+			pending[email] = customer
+			pendingLine[email] = row.line
+		}
+	}
+
+	if !imp.opts.DryRun && len(written) > 0 {
+		err = imp.uc.customerRepo.ImportCustomers(creates, updates)
+	}
+	if err != nil {
+		message := "batch could not be saved"
+		var appErr *apperror.Error
+		if errors.As(err, &appErr) {
+			message = "batch could not be saved: " + appErr.Message
+		}
+		for _, row := range written {
+			imp.report.fail(ImportRowError{Row: row.line, Email: row.customer.Email, Message: message})
+		}
+		imp.report.Skipped += skipped
+		return nil
+	}
+
+	imp.report.Created += len(creates)
+	imp.report.Updated += len(written) - len(creates)
+	imp.report.Skipped += skipped
+	if imp.opts.DryRun {
+		for _, customer := range creates {
+			imp.seen[repository.NormalizeEmail(customer.Email)] = true
+		}
+	}
+	return nil
+}
+
+// failInvalid fail row with the invalid fields err carries
+func (imp *importer) failInvalid(row importRow, err error) {
+	rowErr := ImportRowError{Row: row.line, Email: row.customer.Email, Message: err.Error()}
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		rowErr.Errors, _ = appErr.Details.([]validation.FieldError)
+	}
+	imp.report.fail(rowErr)
+}
+
+// mergeImportRow a later row of the same batch wins over the non empty
+// fields of an earlier one
+func mergeImportRow(target *entity.Customer, row *entity.Customer) {
+	if row.First_name != "" {
+		target.First_name = row.First_name
+	}
+	if row.Last_name != "" {
+		target.Last_name = row.Last_name
+	}
+	if row.Avatar != "" {
+		target.Avatar = row.Avatar
+	}
+	target.Email = row.Email
+	for _, value := range row.Field_values {
+		replaced := false
+		for i := range target.Field_values {
+			if target.Field_values[i].Field_id == value.Field_id {
+				target.Field_values[i] = value
+				replaced = true
+			}
+		}
+		if !replaced {
+			target.Field_values = append(target.Field_values, value)
+		}
+	}
+}
+
+// mapColumns index of the column each customer field is read from
+func mapColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		key := headerKey(name)
+		if _, ok := index[key]; !ok {
+			index[key] = i
+		}
+	}
+
+	columns := map[string]int{}
+	var missing []string
+	for field, column := range mapping {
+		if !customerFields[field] {
+			return nil, ErrImportUnknownField
+		}
+		i, ok := index[headerKey(column)]
+		if !ok {
+			missing = append(missing, column)
+			continue
+		}
+		columns[field] = i
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, apperror.Validation("mapped columns are not in the header", missing)
+	}
+
+	for field := range customerFields {
+		if _, mapped := mapping[field]; mapped {
+			continue
+		}
+		if i, ok := index[field]; ok {
+			columns[field] = i
+		}
+	}
+	for _, field := range []string{"first_name", "email"} {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, apperror.Validation("no column for a required field, add it or map it", missing)
+	}
+	return columns, nil
+}
+
+// headerKey "First Name" and "first-name" both match first_name
+func headerKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// fieldColumn column a custom field is read from
+type fieldColumn struct {
+	field entity.CustomField
+	index int
+}
+
+// customFieldColumns columns named after a custom field, columns already
+// read into a customer field are left to it
+func customFieldColumns(header []string, columns map[string]int, fields []entity.CustomField) []fieldColumn {
+	taken := make(map[int]bool, len(columns))
+	for _, i := range columns {
+		taken[i] = true
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		key := headerKey(name)
+		if _, ok := index[key]; !ok && !taken[i] {
+			index[key] = i
+		}
+	}
+	var fieldColumns []fieldColumn
+	for _, field := range fields {
+		if i, ok := index[headerKey(field.Name)]; ok {
+			fieldColumns = append(fieldColumns, fieldColumn{field: field, index: i})
+		}
+	}
+	return fieldColumns
+}
+
+// importFieldValue JSON form of the text of a cell, text that does not parse
+// as the type of field is kept for customFieldValues to reject
+func importFieldValue(field entity.CustomField, text string) any {
+	switch field.Type {
+	case entity.FieldTypeNumber:
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number
+		}
+	case entity.FieldTypeBoolean:
+		if flag, err := strconv.ParseBool(text); err == nil {
+			return flag
+		}
+	}
+	return text
+}
+
+func rowToParam(record []string, columns map[string]int, fieldColumns []fieldColumn) CustomerParam {
+	cell := func(i int) string {
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	value := func(field string) string {
+		i, ok := columns[field]
+		if !ok {
+			return ""
+		}
+		return cell(i)
+	}
+	param := CustomerParam{
+		First_name: value("first_name"),
+		Last_name:  value("last_name"),
+		Email:      value("email"),
+		Avatar:     value("avatar"),
+	}
+	// an empty cell leaves the field out rather than setting it to ""
+	for _, column := range fieldColumns {
+		if text := cell(column.index); text != "" {
+			if param.Custom_fields == nil {
+				param.Custom_fields = map[string]any{}
+			}
+			param.Custom_fields[column.field.Name] = importFieldValue(column.field, text)
+		}
+	}
+	return param
+}
@@ -0,0 +1,213 @@
+package customers
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newImportUseCase(mockRepo *mocks.CustomerInterfaceRepo, fields ...entity.CustomField) useCaseCustomer {
+	fieldRepo := new(mocks.CustomFieldInterfaceRepo)
+	fieldRepo.On("ListCustomFields").Return(fields, nil)
+	return useCaseCustomer{customerRepo: mockRepo, fieldRepo: fieldRepo}
+}
+
+func TestImportCustomers(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	useCase := newImportUseCase(mockRepo)
+
+	file := "\ufeffFirst Name,Last Name,E-mail\n" +
+		"John,Doe,john@example.com\n" +
+		"Jane,Doe,jane@example.com\n" +
+		",Doe,nobody\n" +
+		"Janet,,JANE@example.com\n"
+
+	var creates []*entity.Customer
+	mockRepo.On("GetCustomersByEmails", mock.Anything).Return([]entity.Customer{}, nil)
+	mockRepo.On("ImportCustomers", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		creates = args.Get(0).([]*entity.Customer)
+	}).Return(nil)
+
+	report, err := useCase.ImportCustomers(strings.NewReader(file), ImportOptions{
+		Mapping: map[string]string{"email": "e-mail"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Rows)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, 4, report.Errors[0].Row)
+	assert.Len(t, report.Errors[0].Errors, 2)
+	assert.Equal(t, 5, report.Errors[1].Row)
+	assert.Equal(t, "email repeats row 3", report.Errors[1].Message)
+	require.Len(t, creates, 2)
+	assert.Equal(t, "John", creates[0].First_name)
+	assert.Equal(t, "jane@example.com", creates[1].Email)
+}
+
+func TestImportCustomers_DuplicatePolicy(t *testing.T) {
+	file := "first_name,email\nJohnny,JOHN@example.com\nJane,jane@example.com\n"
+	john := entity.Customer{ID: 7, First_name: "John", Email: "john@example.com"}
+
+	tests := []struct {
+		policy  string
+		created int
+		updated int
+		skipped int
+		failed  int
+	}{
+		{policy: DuplicateSkip, created: 1, skipped: 1},
+		{policy: DuplicateUpdate, created: 1, updated: 1},
+		{policy: DuplicateFail, created: 1, failed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			mockRepo := new(mocks.CustomerInterfaceRepo)
+			useCase := newImportUseCase(mockRepo)
+
+			var updates []*entity.Customer
+			mockRepo.On("GetCustomersByEmails", []string{"JOHN@example.com", "jane@example.com"}).Return([]entity.Customer{john}, nil)
+			mockRepo.On("ImportCustomers", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				updates = args.Get(1).([]*entity.Customer)
+			}).Return(nil)
+
+			report, err := useCase.ImportCustomers(strings.NewReader(file), ImportOptions{OnDuplicate: tt.policy})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.created, report.Created)
+			assert.Equal(t, tt.updated, report.Updated)
+			assert.Equal(t, tt.skipped, report.Skipped)
+			assert.Equal(t, tt.failed, report.Failed)
+			if tt.policy == DuplicateUpdate {
+				require.Len(t, updates, 1)
+				assert.Equal(t, john.ID, updates[0].ID)
+				assert.Equal(t, "Johnny", updates[0].First_name)
+			}
+		})
+	}
+}
+
+func TestImportCustomers_DryRunAcrossBatches(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	useCase := newImportUseCase(mockRepo)
+
+	file := "first_name;email\nJohn;john@example.com\nJane;jane@example.com\nJohnny;john@example.com\n"
+	mockRepo.On("GetCustomersByEmails", mock.Anything).Return([]entity.Customer{}, nil)
+
+	report, err := useCase.ImportCustomers(strings.NewReader(file), ImportOptions{
+		DryRun:    true,
+		BatchSize: 2,
+		Delimiter: ';',
+	})
+
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "ImportCustomers", mock.Anything, mock.Anything)
+	mockRepo.AssertNumberOfCalls(t, "GetCustomersByEmails", 2)
+	assert.True(t, report.Dry_run)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 1, report.Failed)
+}
+
+func TestImportCustomers_FailedBatch(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	useCase := newImportUseCase(mockRepo)
+
+	file := "first_name,email\nJohn,john@example.com\nJane,jane@example.com\nAnn,ann@example.com\n"
+	mockRepo.On("GetCustomersByEmails", mock.Anything).Return([]entity.Customer{}, nil)
+	mockRepo.On("ImportCustomers", mock.Anything, mock.Anything).Return(apperror.Conflict("customer already exists", nil)).Once()
+	mockRepo.On("ImportCustomers", mock.Anything, mock.Anything).Return(nil).Once()
+
+	report, err := useCase.ImportCustomers(strings.NewReader(file), ImportOptions{BatchSize: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, "batch could not be saved: customer already exists", report.Errors[0].Message)
+}
+
+func TestImportCustomers_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		mapping map[string]string
+		err     error
+	}{
+		{name: "empty", file: "", err: ErrImportEmpty},
+		{name: "unknown field", file: "first_name,email\n", mapping: map[string]string{"phone": "phone"}, err: ErrImportUnknownField},
+		{name: "mapped column missing", file: "first_name,email\n", mapping: map[string]string{"email": "mail"}, err: apperror.ErrValidation},
+		{name: "no email column", file: "first_name,mail\n", err: apperror.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CustomerInterfaceRepo)
+			useCase := newImportUseCase(mockRepo)
+
+			_, err := useCase.ImportCustomers(strings.NewReader(tt.file), ImportOptions{Mapping: tt.mapping})
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestImportCustomers_LookupError(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	useCase := newImportUseCase(mockRepo)
+
+	lookupErr := errors.New("connection refused")
+	mockRepo.On("GetCustomersByEmails", mock.Anything).Return(nil, lookupErr)
+
+	_, err := useCase.ImportCustomers(strings.NewReader("first_name,email\nJohn,john@example.com\n"), ImportOptions{})
+
+	assert.ErrorIs(t, err, lookupErr)
+}
+
+func TestImportCustomers_CustomFields(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	useCase := newImportUseCase(mockRepo,
+		entity.CustomField{ID: 1, Name: "tier", Type: entity.FieldTypeEnum, Required: true, Rules: entity.CustomFieldRules{Options: []string{"gold", "silver"}}},
+		entity.CustomField{ID: 2, Name: "seats", Type: entity.FieldTypeNumber},
+	)
+
+	file := "first_name,email,Tier,Seats\n" +
+		"John,john@example.com,gold,5\n" +
+		"Jane,jane@example.com,,3\n" +
+		"Ann,ann@example.com,silver,many\n" +
+		"Bob,bob@example.com,,12\n"
+	bob := entity.Customer{ID: 9, First_name: "Bob", Email: "bob@example.com"}
+	var creates, updates []*entity.Customer
+	mockRepo.On("GetCustomersByEmails", mock.Anything).Return([]entity.Customer{bob}, nil)
+	mockRepo.On("ImportCustomers", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		creates = args.Get(0).([]*entity.Customer)
+		updates = args.Get(1).([]*entity.Customer)
+	}).Return(nil)
+
+	report, err := useCase.ImportCustomers(strings.NewReader(file), ImportOptions{OnDuplicate: DuplicateUpdate})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 2, report.Failed)
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[0].Row)
+	assert.Equal(t, "custom_fields.tier", report.Errors[0].Errors[0].Field)
+	assert.Equal(t, "required", report.Errors[0].Errors[0].Rule)
+	assert.Equal(t, 4, report.Errors[1].Row)
+	assert.Equal(t, "custom_fields.seats", report.Errors[1].Errors[0].Field)
+	assert.Equal(t, entity.FieldTypeNumber, report.Errors[1].Errors[0].Rule)
+
+	require.Len(t, creates, 1)
+	assert.ElementsMatch(t, []string{"gold", "5"}, []string{creates[0].Field_values[0].Value, creates[0].Field_values[1].Value})
+	// an update does not need the required fields
+	require.Len(t, updates, 1)
+	require.Len(t, updates[0].Field_values, 1)
+	assert.Equal(t, uint(2), updates[0].Field_values[0].Field_id)
+	assert.Equal(t, 12.0, *updates[0].Field_values[0].Number)
+}
@@ -0,0 +1,50 @@
+package customers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/job"
+	"github.com/alkamalp/crm-golang/utils/storage"
+	"gorm.io/gorm"
+)
+
+// NewPurgeJob job permanently deleting customers that have been in the trash
+// longer than retention along with their avatar files
+func NewPurgeJob(dbCrud *gorm.DB, store storage.Storage, retention time.Duration) job.Func {
+	uc := useCaseCustomer{
+		customerRepo: repository.NewCustomer(dbCrud),
+		avatarStore:  store,
+	}
+	return func(ctx context.Context) error {
+		purged, err := uc.PurgeTrash(time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			log.Printf("purged %d customers from the trash", purged)
+		}
+		return nil
+	}
+}
+
+// NewDuplicateScanJob job comparing every customer and replacing the pairs
+// the duplicates report lists
+func NewDuplicateScanJob(dbCrud *gorm.DB) job.Func {
+	uc := useCaseCustomer{
+		customerRepo:  repository.NewCustomer(dbCrud),
+		duplicateRepo: repository.NewDuplicate(dbCrud),
+	}
+	return func(ctx context.Context) error {
+		scan, err := uc.ScanDuplicates()
+		if err != nil {
+			return err
+		}
+		if scan.Truncated_blocks > 0 {
+			log.Printf("duplicate scan compared only the first %d customers of %d blocking keys", maxBlockSize, scan.Truncated_blocks)
+		}
+		return nil
+	}
+}
@@ -1,11 +1,22 @@
 package customers
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/middleware"
 	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/storage"
+	"github.com/alkamalp/crm-golang/utils/validation"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -16,25 +27,36 @@ type RequestHandlerCustomer struct {
 
 func NewCustomerRequestHandler(
 	dbCrud *gorm.DB,
+	store storage.Storage,
+	avatarCfg config.Avatar,
 ) RequestHandlerCustomer {
 	return RequestHandlerCustomer{
 		ctr: controllerCustomer{
 			customerUseCase: useCaseCustomer{
-				customerRepo: repository.NewCustomer(dbCrud),
+				customerRepo:  repository.NewCustomer(dbCrud),
+				actorRepo:     repository.NewActor(dbCrud),
+				tagRepo:       repository.NewTag(dbCrud),
+				segmentRepo:   repository.NewSegment(dbCrud),
+				fieldRepo:     repository.NewCustomField(dbCrud),
+				activityRepo:  repository.NewActivity(dbCrud),
+				accountRepo:   repository.NewAccount(dbCrud),
+				duplicateRepo: repository.NewDuplicate(dbCrud),
+				avatarStore:   store,
+				avatarMaxSize: avatarCfg.MaxSize,
 			},
 		}}
 }
 
 func (h RequestHandlerCustomer) CreateCustomer(c *gin.Context) {
 	request := CustomerParam{}
-	err := c.Bind(&request)
+	err := c.ShouldBind(&request)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		c.JSON(validation.NewBindErrorResponse(err))
 		return
 	}
 	res, err := h.ctr.CreateCustomer(request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -47,9 +69,23 @@ func (h RequestHandlerCustomer) GetCustomerById(c *gin.Context) {
 		return
 	}
 
-	res, err := h.ctr.GetCustomerById(uint(actorId))
+	request := CustomerAsOfParam{}
+	err = c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+
+	var res FindCustomer
+	if request.As_of == "" {
+		res, err = h.ctr.GetCustomerById(uint(actorId))
+	} else {
+		// already checked by binding
+		at, _ := time.Parse(time.RFC3339, request.As_of)
+		res, err = h.ctr.GetCustomerAsOf(uint(actorId), at)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -61,30 +97,601 @@ func (h RequestHandlerCustomer) DeleteCustomer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
 		return
 	}
-	res, err := h.ctr.DeleteCustomer(uint(id))
+	res, err := h.ctr.DeleteCustomer(uint(id), c.GetString("Username"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
 }
 
 func (h RequestHandlerCustomer) UpdateCustomer(c *gin.Context) {
-	request := CustomerParam{}
-	err := c.BindQuery(&request)
+	request := UpdateCustomerParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	customerId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+
+	res, err := h.ctr.UpdateCustomer(CustomerParam{
+		First_name: request.First_name,
+		Last_name:  request.Last_name,
+		Email:      request.Email,
+		Avatar:     request.Avatar,
+	}, uint(customerId))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) ListCustomers(c *gin.Context) {
+	request := CustomerListParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	request.Custom_fields = c.QueryMap("cf")
+
+	res, err := h.ctr.ListCustomers(request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) ListDuplicates(c *gin.Context) {
+	request := DuplicateListParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+
+	res, err := h.ctr.ListDuplicates(request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) MergeCustomers(c *gin.Context) {
+	request := MergeCustomerParam{}
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+
+	res, err := h.ctr.MergeCustomers(request, c.GetString("Username"), middleware.AuditTrail(c))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) ListTrash(c *gin.Context) {
+	request := TrashListParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+
+	res, err := h.ctr.ListTrash(request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) RestoreCustomer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.RestoreCustomer(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// ImportCustomers the CSV comes as the file part of a multipart form or as
+// the raw request body, it is read while the request streams in
+func (h RequestHandlerCustomer) ImportCustomers(c *gin.Context) {
+	request := ImportParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	file, err := importFile(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+
+	res, err := h.ctr.ImportCustomers(request, c.QueryMap("map"), file)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// importFile the part named file of a multipart request, otherwise the body
+func importFile(c *gin.Context) (io.Reader, error) {
+	part, err := formPart(c, "file")
+	if errors.Is(err, http.ErrNotMultipart) {
+		return c.Request.Body, nil
+	}
+	return part, err
+}
+
+// formPart part called name of a multipart request, read as it streams in
+func formPart(c *gin.Context, name string) (*multipart.Part, error) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == name {
+			return part, nil
+		}
+	}
+}
+
+// ExportCustomers stream every customer matching the list filters as a file
+func (h RequestHandlerCustomer) ExportCustomers(c *gin.Context) {
+	filter := CustomerListParam{}
+	err := c.ShouldBindQuery(&filter)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	filter.Custom_fields = c.QueryMap("cf")
+
+	request := ExportParam{}
+	err = c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+
+	contentType, extension := ExportContentType(request.Format)
+	w := &exportResponse{
+		c:           c,
+		contentType: contentType,
+		filename:    fmt.Sprintf("customers-%s.%s", time.Now().UTC().Format("20060102-150405"), extension),
+	}
+	err = h.ctr.ExportCustomers(filter, request, w)
+	if err != nil && !w.started {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	if err != nil {
+		// the 200 is already out, the client gets a cut off file
+		log.Printf("customer export failed: %v", err)
+		return
+	}
+	w.begin()
+}
+
+// exportResponse sends the download headers right before the first byte so
+// an export failing early can still answer with an error
+type exportResponse struct {
+	c           *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *exportResponse) begin() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.c.Header("Content-Type", w.contentType)
+	w.c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": w.filename}))
+	w.c.Header("Cache-Control", "no-store")
+	w.c.Status(http.StatusOK)
+}
+
+func (w *exportResponse) Write(p []byte) (int, error) {
+	w.begin()
+	return w.c.Writer.Write(p)
+}
+
+// UploadAvatar the image is the multipart part named avatar
+func (h RequestHandlerCustomer) UploadAvatar(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	part, err := formPart(c, "avatar")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+
+	res, err := h.ctr.UploadAvatar(uint(id), part, part.Header.Get("Content-Type"))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetAvatar the link kept in Avatar carries the version, such requests may be
+// cached for good while any other has to revalidate with the ETag
+func (h RequestHandlerCustomer) GetAvatar(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	file, err := h.ctr.GetAvatar(uint(id), c.DefaultQuery("size", AvatarFull))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	defer file.Body.Close()
+
+	if c.Query("v") == file.Version {
+		c.Header("Cache-Control", "private, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "private, no-cache")
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	if !file.Object.ModTime.IsZero() {
+		c.Header("Last-Modified", file.Object.ModTime.UTC().Format(http.TimeFormat))
+	}
+	if file.Object.ETag != "" {
+		c.Header("ETag", file.Object.ETag)
+		if c.GetHeader("If-None-Match") == file.Object.ETag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.DataFromReader(http.StatusOK, file.Object.Size, file.Object.ContentType, file.Body, nil)
+}
+
+func (h RequestHandlerCustomer) TagCustomer(c *gin.Context) {
+	h.changeTags(c, h.ctr.TagCustomer)
+}
+
+func (h RequestHandlerCustomer) UntagCustomer(c *gin.Context) {
+	h.changeTags(c, h.ctr.UntagCustomer)
+}
+
+// changeTags POST and DELETE /customer/:id/tags both take CustomerTagsParam
+func (h RequestHandlerCustomer) changeTags(c *gin.Context, change func(id uint, req CustomerTagsParam) (FindCustomer, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := CustomerTagsParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := change(uint(id), request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) CreateSegment(c *gin.Context) {
+	request := SegmentParam{}
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.CreateSegment(request, c.GetString("Username"))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) GetSegment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.GetSegment(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) ListSegments(c *gin.Context) {
+	request := SegmentListParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.ListSegments(request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) UpdateSegment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := SegmentParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.UpdateSegment(request, uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) DeleteSegment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.DeleteSegment(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) CreateCustomField(c *gin.Context) {
+	request := CustomFieldParam{}
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.CreateCustomField(request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) GetCustomField(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.GetCustomField(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) ListCustomFields(c *gin.Context) {
+	res, err := h.ctr.ListCustomFields()
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) UpdateCustomField(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := UpdateCustomFieldParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.UpdateCustomField(request, uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) DeleteCustomField(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.DeleteCustomField(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) SetCustomFields(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := CustomerFieldsParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.SetCustomFields(uint(id), request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) CreateActivity(c *gin.Context) {
+	customerId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := ActivityParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.CreateActivity(uint(customerId), request, c.GetString("Username"))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) UpdateActivity(c *gin.Context) {
+	customerId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("activityId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := ActivityParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.UpdateActivity(uint(customerId), uint(id), request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) DeleteActivity(c *gin.Context) {
+	customerId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("activityId"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
 		return
 	}
+	res, err := h.ctr.DeleteActivity(uint(customerId), uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) GetTimeline(c *gin.Context) {
 	customerId, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
 		return
 	}
+	request := TimelineParam{}
+	err = c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.GetTimeline(uint(customerId), request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
 
-	res, err := h.ctr.UpdateCustomer(request, uint(customerId))
+func (h RequestHandlerCustomer) GetCustomerHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := HistoryParam{}
+	err = c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.GetCustomerHistory(uint(id), request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerCustomer) RevertCustomer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	version, err := strconv.ParseUint(c.Param("version"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.RevertCustomer(uint(id), uint(version))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.DefaultErrorResponse())
+		c.JSON(dto.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
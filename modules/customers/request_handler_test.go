@@ -0,0 +1,331 @@
+package customers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type validationBody struct {
+	Errors []validation.FieldError `json:"errors"`
+}
+
+func newTestRouter(mockRepo *mocks.CustomerInterfaceRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	fieldRepo := new(mocks.CustomFieldInterfaceRepo)
+	fieldRepo.On("ListCustomFields").Return(nil, nil)
+	h := RequestHandlerCustomer{
+		ctr: controllerCustomer{
+			customerUseCase: useCaseCustomer{
+				customerRepo: mockRepo,
+				fieldRepo:    fieldRepo,
+			},
+		},
+	}
+	router := gin.New()
+	router.POST("/customer", h.CreateCustomer)
+	router.PUT("/customer/:id", h.UpdateCustomer)
+	router.GET("/customer", h.ListCustomers)
+	router.POST("/customer/import", h.ImportCustomers)
+	router.GET("/customer/export", h.ExportCustomers)
+	return router
+}
+
+func TestCustomerRequestValidation(t *testing.T) {
+	long := strings.Repeat("a", 256)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		fields []string
+	}{
+		{
+			name:   "valid create",
+			method: http.MethodPost,
+			target: "/customer",
+			body:   `{"first_name":"John","last_name":"Doe","email":"john.doe@example.com"}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "empty create",
+			method: http.MethodPost,
+			target: "/customer",
+			body:   `{}`,
+			status: http.StatusUnprocessableEntity,
+			fields: []string{"first_name", "email"},
+		},
+		{
+			name:   "malformed email",
+			method: http.MethodPost,
+			target: "/customer",
+			body:   `{"first_name":"John","email":"john.doe"}`,
+			status: http.StatusUnprocessableEntity,
+			fields: []string{"email"},
+		},
+		{
+			name:   "names longer than the columns",
+			method: http.MethodPost,
+			target: "/customer",
+			body:   `{"first_name":"` + long + `","last_name":"` + long + `","email":"john.doe@example.com"}`,
+			status: http.StatusUnprocessableEntity,
+			fields: []string{"first_name", "last_name"},
+		},
+		{
+			name:   "malformed json",
+			method: http.MethodPost,
+			target: "/customer",
+			body:   `{"first_name":`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "partial update",
+			method: http.MethodPut,
+			target: "/customer/1?last_name=Smith",
+			status: http.StatusOK,
+		},
+		{
+			name:   "update with malformed email",
+			method: http.MethodPut,
+			target: "/customer/1?email=smith",
+			status: http.StatusUnprocessableEntity,
+			fields: []string{"email"},
+		},
+		{
+			name:   "update with name longer than the column",
+			method: http.MethodPut,
+			target: "/customer/1?first_name=" + long,
+			status: http.StatusUnprocessableEntity,
+			fields: []string{"first_name"},
+		},
+		{
+			name:   "negative page",
+			method: http.MethodGet,
+			target: "/customer?page=-1",
+			status: http.StatusUnprocessableEntity,
+			fields: []string{"page"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CustomerInterfaceRepo)
+			mockRepo.On("CreateCustomer", mock.AnythingOfType("*entity.Customer")).Return(&entity.Customer{}, nil)
+			mockRepo.On("UpdateCustomer", mock.AnythingOfType("*entity.Customer"), uint(1)).Return(nil, nil)
+			mockRepo.On("GetCustomerByEmail", mock.Anything).Return(entity.Customer{}, apperror.NotFound("customer not found")).Maybe()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			newTestRouter(mockRepo).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.status != http.StatusUnprocessableEntity {
+				return
+			}
+			var body validationBody
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			var fields []string
+			for _, fieldErr := range body.Errors {
+				fields = append(fields, fieldErr.Field)
+				assert.NotEmpty(t, fieldErr.Message.ID)
+				assert.NotEmpty(t, fieldErr.Message.EN)
+			}
+			assert.ElementsMatch(t, tt.fields, fields)
+			mockRepo.AssertNotCalled(t, "CreateCustomer", mock.Anything)
+			mockRepo.AssertNotCalled(t, "ListCustomers", mock.Anything)
+		})
+	}
+}
+
+func TestUpdateCustomer_QueryNames(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	var updated entity.Customer
+	mockRepo.On("GetCustomerByEmail", "jane@example.com").Return(entity.Customer{}, apperror.NotFound("customer not found"))
+	mockRepo.On("UpdateCustomer", mock.AnythingOfType("*entity.Customer"), uint(1)).
+		Run(func(args mock.Arguments) {
+			updated = *args.Get(0).(*entity.Customer)
+		}).
+		Return(nil, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/customer/1?first_name=Jane&last_name=Smith&email=jane@example.com&avatar=a.png", nil)
+	newTestRouter(mockRepo).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Jane", updated.First_name)
+	assert.Equal(t, "Smith", updated.Last_name)
+	assert.Equal(t, "jane@example.com", updated.Email)
+	assert.Equal(t, "a.png", updated.Avatar)
+}
+
+func TestCustomerRequestValidation_Messages(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/customer", strings.NewReader(`{"first_name":"John","email":"john"}`))
+	req.Header.Set("Content-Type", "application/json")
+	newTestRouter(mockRepo).ServeHTTP(w, req)
+
+	var body validationBody
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []validation.FieldError{{
+		Field: "email",
+		Rule:  "email",
+		Message: validation.Message{
+			ID: "email harus berupa alamat email yang valid",
+			EN: "email must be a valid email address",
+		},
+	}}, body.Errors)
+}
+
+func TestImportCustomers_Upload(t *testing.T) {
+	file := "Name,Mail\nJohn,john@example.com\n"
+	multipartBody := &bytes.Buffer{}
+	form := multipart.NewWriter(multipartBody)
+	assert.NoError(t, form.WriteField("note", "ignored"))
+	part, err := form.CreateFormFile("file", "customers.csv")
+	assert.NoError(t, err)
+	part.Write([]byte(file))
+	assert.NoError(t, form.Close())
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		status      int
+	}{
+		{name: "multipart", query: "?map[first_name]=name&map[email]=mail", contentType: form.FormDataContentType(), body: multipartBody.String(), status: http.StatusOK},
+		{name: "raw body", query: "?map[first_name]=name&map[email]=mail", contentType: "text/csv", body: file, status: http.StatusOK},
+		{name: "multipart without file", query: "", contentType: "multipart/form-data; boundary=x", body: "--x--\r\n", status: http.StatusBadRequest},
+		{name: "unknown policy", query: "?on_duplicate=merge", contentType: "text/csv", body: file, status: http.StatusUnprocessableEntity},
+		{name: "unmapped header", query: "", contentType: "text/csv", body: file, status: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CustomerInterfaceRepo)
+			mockRepo.On("GetCustomersByEmails", []string{"john@example.com"}).Return([]entity.Customer{}, nil).Maybe()
+			mockRepo.On("ImportCustomers", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/customer/import"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			newTestRouter(mockRepo).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			if tt.status != http.StatusOK {
+				return
+			}
+			var body struct {
+				Data ImportReport `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, 1, body.Data.Created)
+		})
+	}
+}
+
+func TestExportCustomers_Response(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		status      int
+		contentType string
+		extension   string
+	}{
+		{name: "csv by default", target: "/customer/export", status: http.StatusOK, contentType: "text/csv; charset=utf-8", extension: ".csv"},
+		{name: "ndjson", target: "/customer/export?format=ndjson", status: http.StatusOK, contentType: "application/x-ndjson", extension: ".ndjson"},
+		{name: "unknown format", target: "/customer/export?format=pdf", status: http.StatusUnprocessableEntity, contentType: "application/json; charset=utf-8"},
+		{name: "unknown column", target: "/customer/export?columns=password", status: http.StatusUnprocessableEntity, contentType: "application/json; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CustomerInterfaceRepo)
+			mockRepo.On("ExportCustomers", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			newTestRouter(mockRepo).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+			assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			disposition := w.Header().Get("Content-Disposition")
+			if tt.extension == "" {
+				assert.Empty(t, disposition)
+				return
+			}
+			assert.True(t, strings.HasPrefix(disposition, "attachment; filename=customers-"), disposition)
+			assert.True(t, strings.HasSuffix(disposition, tt.extension), disposition)
+		})
+	}
+}
+
+func TestAvatar_UploadAndServe(t *testing.T) {
+	useCase, mockRepo, _ := newAvatarUseCase(t)
+	gin.SetMode(gin.TestMode)
+	h := RequestHandlerCustomer{ctr: controllerCustomer{customerUseCase: useCase}}
+	router := gin.New()
+	router.PUT("/customer/:id/avatar", h.UploadAvatar)
+	router.GET("/customer/:id/avatar", h.GetAvatar)
+
+	var key *string
+	mockRepo.On("GetCustomerById", uint(1)).Return(func(id uint) entity.Customer {
+		return entity.Customer{ID: 1, Avatar_key: key}
+	}, nil)
+	mockRepo.On("SetCustomerAvatar", uint(1), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		key = args.Get(2).(*string)
+	}).Return(nil)
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	part, err := form.CreatePart(map[string][]string{
+		"Content-Disposition": {`form-data; name="avatar"; filename="me.png"`},
+		"Content-Type":        {"image/png"},
+	})
+	assert.NoError(t, err)
+	part.Write(testPNG(t, 300, 300))
+	assert.NoError(t, form.Close())
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/customer/1/avatar", body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var res FindCustomer
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.True(t, strings.HasPrefix(res.Data.Avatar, "/customer/1/avatar?v="))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, res.Data.Avatar+"&size=64", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "private, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/customer/1/avatar?size=64", nil)
+	req.Header.Set("If-None-Match", etag)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/customer/1/avatar", strings.NewReader("not multipart")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
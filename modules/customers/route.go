@@ -1,37 +1,190 @@
 package customers
 
 import (
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/storage"
+	"github.com/alkamalp/crm-golang/utils/token"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type RouteCustomer struct {
 	CustomerRequestHandeler RequestHandlerCustomer
+	Authentication          middleware.Authentication
+	Authorization           middleware.Authorization
+	Audit                   middleware.Audit
 }
 
 func NewRouter(
 	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+	store storage.Storage,
 ) RouteCustomer {
-	return RouteCustomer{CustomerRequestHandeler: NewCustomerRequestHandler(
-		dbCrud,
-	)}
+	return RouteCustomer{
+		CustomerRequestHandeler: NewCustomerRequestHandler(
+			dbCrud,
+			store,
+			cfg.Avatar,
+		),
+		Authentication: middleware.NewAuthentication(dbCrud, issuer),
+		Authorization:  middleware.NewAuthorization(dbCrud),
+		Audit:          middleware.NewAudit(dbCrud),
+	}
 }
 
 func (r RouteCustomer) Handle(routeVersion *gin.Engine) {
 	basepath := "/customer"
-	customer := routeVersion.Group(basepath)
+	customer := routeVersion.Group(basepath, r.Authentication.Auth)
 
 	customer.POST("",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerCreate),
+		r.Audit.Record(entity.AuditActionCreate, "customer", ""),
 		r.CustomerRequestHandeler.CreateCustomer,
 	)
 
+	customer.GET("",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.ListCustomers,
+	)
+	customer.POST("/import",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerCreate),
+		r.Audit.Record(entity.AuditActionImport, "customer", ""),
+		r.CustomerRequestHandeler.ImportCustomers,
+	)
+	customer.GET("/export",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.ExportCustomers,
+	)
+	customer.POST("/merge",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerMerge),
+		r.CustomerRequestHandeler.MergeCustomers,
+	)
+	customer.GET("/trash",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerDelete),
+		r.CustomerRequestHandeler.ListTrash,
+	)
+	customer.POST("/:id/restore",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerDelete),
+		r.Audit.Record(entity.AuditActionRestore, "customer", "id"),
+		r.CustomerRequestHandeler.RestoreCustomer,
+	)
+	customer.GET("/segments",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.ListSegments,
+	)
+	customer.POST("/segments",
+		r.Authorization.RequirePermission(middleware.PermissionSegmentManage),
+		r.Audit.Record(entity.AuditActionCreate, "segment", ""),
+		r.CustomerRequestHandeler.CreateSegment,
+	)
+	customer.GET("/segments/:id",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.GetSegment,
+	)
+	customer.PUT("/segments/:id",
+		r.Authorization.RequirePermission(middleware.PermissionSegmentManage),
+		r.Audit.Record(entity.AuditActionUpdate, "segment", "id"),
+		r.CustomerRequestHandeler.UpdateSegment,
+	)
+	customer.DELETE("/segments/:id",
+		r.Authorization.RequirePermission(middleware.PermissionSegmentManage),
+		r.Audit.Record(entity.AuditActionDelete, "segment", "id"),
+		r.CustomerRequestHandeler.DeleteSegment,
+	)
+	customer.GET("/fields",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.ListCustomFields,
+	)
+	customer.POST("/fields",
+		r.Authorization.RequirePermission(middleware.PermissionFieldManage),
+		r.Audit.Record(entity.AuditActionCreate, "field", ""),
+		r.CustomerRequestHandeler.CreateCustomField,
+	)
+	customer.GET("/fields/:id",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.GetCustomField,
+	)
+	customer.PUT("/fields/:id",
+		r.Authorization.RequirePermission(middleware.PermissionFieldManage),
+		r.Audit.Record(entity.AuditActionUpdate, "field", "id"),
+		r.CustomerRequestHandeler.UpdateCustomField,
+	)
+	customer.DELETE("/fields/:id",
+		r.Authorization.RequirePermission(middleware.PermissionFieldManage),
+		r.Audit.Record(entity.AuditActionDelete, "field", "id"),
+		r.CustomerRequestHandeler.DeleteCustomField,
+	)
+	customer.GET("/duplicates",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.ListDuplicates,
+	)
 	customer.GET("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
 		r.CustomerRequestHandeler.GetCustomerById,
 	)
 	customer.PUT("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "customer", "id"),
 		r.CustomerRequestHandeler.UpdateCustomer,
 	)
+	customer.GET("/:id/avatar",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.GetAvatar,
+	)
+	customer.PUT("/:id/avatar",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "customer", "id"),
+		r.CustomerRequestHandeler.UploadAvatar,
+	)
+	customer.POST("/:id/tags",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "customer", "id"),
+		r.CustomerRequestHandeler.TagCustomer,
+	)
+	customer.DELETE("/:id/tags",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "customer", "id"),
+		r.CustomerRequestHandeler.UntagCustomer,
+	)
+	customer.GET("/:id/history",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.GetCustomerHistory,
+	)
+	customer.POST("/:id/revert/:version",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "customer", "id"),
+		r.CustomerRequestHandeler.RevertCustomer,
+	)
+	customer.GET("/:id/timeline",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.CustomerRequestHandeler.GetTimeline,
+	)
+	customer.POST("/:id/activities",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionCreate, "activity", ""),
+		r.CustomerRequestHandeler.CreateActivity,
+	)
+	customer.PUT("/:id/activities/:activityId",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "activity", "activityId"),
+		r.CustomerRequestHandeler.UpdateActivity,
+	)
+	customer.DELETE("/:id/activities/:activityId",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionDelete, "activity", "activityId"),
+		r.CustomerRequestHandeler.DeleteActivity,
+	)
+	customer.PUT("/:id/fields",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "customer", "id"),
+		r.CustomerRequestHandeler.SetCustomFields,
+	)
 	customer.DELETE("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerDelete),
+		r.Audit.Record(entity.AuditActionDelete, "customer", "id"),
 		r.CustomerRequestHandeler.DeleteCustomer,
 	)
 }
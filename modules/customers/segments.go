@@ -0,0 +1,143 @@
+package customers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+)
+
+var (
+	ErrSegmentNameRequired = apperror.Validation("segment name must not be blank", nil)
+	ErrSegmentNameTaken    = apperror.Conflict("a segment with this name already exists", nil)
+	ErrSegmentCreatedRange = apperror.Validation("created_within_days cannot be combined with created_from", nil)
+	ErrSegmentUnknownTag   = apperror.Validation("tag_ids must name existing tags", nil)
+)
+
+// SegmentPage one page of segments
+type SegmentPage struct {
+	Segments []entity.Segment
+	Total    int64
+	Page     int
+	Limit    int
+}
+
+// CreateSegment save a named filter of the customer list
+func (uc useCaseCustomer) CreateSegment(req SegmentParam, actorName string) (entity.Segment, error) {
+	actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return entity.Segment{}, err
+	}
+	segment, err := uc.segment(req)
+	if err != nil {
+		return segment, err
+	}
+	segment.Created_by = &actor.ID
+	segment.CreatedAt = time.Now()
+	segment.UpdatedAt = time.Now()
+
+	err = uc.segmentRepo.CreateSegment(&segment)
+	if errors.Is(err, apperror.ErrConflict) {
+		return segment, ErrSegmentNameTaken
+	}
+	return segment, err
+}
+
+func (uc useCaseCustomer) GetSegment(id uint) (entity.Segment, error) {
+	return uc.segmentRepo.GetSegmentById(id)
+}
+
+// ListSegments page of segments in name order
+func (uc useCaseCustomer) ListSegments(req SegmentListParam) (SegmentPage, error) {
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	segments, total, err := uc.segmentRepo.ListSegments(limit, (page-1)*limit)
+	if err != nil {
+		return SegmentPage{}, err
+	}
+	return SegmentPage{
+		Segments: segments,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// UpdateSegment replace the name and filter of a segment
+func (uc useCaseCustomer) UpdateSegment(req SegmentParam, id uint) (entity.Segment, error) {
+	segment, err := uc.segment(req)
+	if err != nil {
+		return segment, err
+	}
+	segment.UpdatedAt = time.Now()
+
+	err = uc.segmentRepo.UpdateSegment(&segment, id)
+	if errors.Is(err, apperror.ErrConflict) {
+		return segment, ErrSegmentNameTaken
+	}
+	if err != nil {
+		return segment, err
+	}
+	return uc.segmentRepo.GetSegmentById(id)
+}
+
+func (uc useCaseCustomer) DeleteSegment(id uint) error {
+	return uc.segmentRepo.DeleteSegment(id)
+}
+
+// segment checked Segment of req
+func (uc useCaseCustomer) segment(req SegmentParam) (entity.Segment, error) {
+	segment := entity.Segment{
+		Name:   strings.TrimSpace(req.Name),
+		Filter: entity.SegmentFilter(req.Filter),
+	}
+	if segment.Name == "" {
+		return segment, ErrSegmentNameRequired
+	}
+	segment.Filter.Tag_ids = uniqueIds(segment.Filter.Tag_ids)
+	_, err := uc.segmentCustomerFilter(segment.Filter, time.Now())
+	if err != nil {
+		return segment, err
+	}
+	return segment, uc.checkTagsExist(segment.Filter.Tag_ids, ErrSegmentUnknownTag)
+}
+
+// segmentCustomerFilter criteria of a segment used at now, tags deleted
+// since the segment was saved match no customer and custom fields deleted
+// since make it fail
+func (uc useCaseCustomer) segmentCustomerFilter(filter entity.SegmentFilter, now time.Time) (repository.CustomerFilter, error) {
+	createdFrom, err := parseDate(filter.Created_from, false)
+	if err != nil {
+		return repository.CustomerFilter{}, err
+	}
+	createdTo, err := parseDate(filter.Created_to, true)
+	if err != nil {
+		return repository.CustomerFilter{}, err
+	}
+	if filter.Created_within_days > 0 {
+		if createdFrom != nil {
+			return repository.CustomerFilter{}, ErrSegmentCreatedRange
+		}
+		from := now.AddDate(0, 0, -filter.Created_within_days)
+		createdFrom = &from
+	}
+	fieldConditions, err := uc.fieldConditions(filter.Custom_fields)
+	if err != nil {
+		return repository.CustomerFilter{}, err
+	}
+	return repository.CustomerFilter{
+		First_name:  filter.First_name,
+		Last_name:   filter.Last_name,
+		Email:       filter.Email,
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		Tag_ids:     filter.Tag_ids,
+		Fields:      fieldConditions,
+	}, nil
+
+}
@@ -0,0 +1,106 @@
+package customers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSegment(t *testing.T) {
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+	mockTagRepo := mocks.NewTagInterfaceRepo(t)
+	mockSegmentRepo := mocks.NewSegmentInterfaceRepo(t)
+	useCase := useCaseCustomer{actorRepo: mockActorRepo, tagRepo: mockTagRepo, segmentRepo: mockSegmentRepo}
+
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5}, nil)
+	mockTagRepo.On("GetTagsByIds", []uint{2}).Return([]entity.Tag{{ID: 2}}, nil)
+	mockSegmentRepo.On("CreateSegment", mock.MatchedBy(func(segment *entity.Segment) bool {
+		return segment.Name == "New VIPs" && *segment.Created_by == 5 &&
+			assert.ObjectsAreEqual([]uint{2}, segment.Filter.Tag_ids)
+	})).Return(nil)
+
+	segment, err := useCase.CreateSegment(SegmentParam{
+		Name:   " New VIPs ",
+		Filter: SegmentFilterParam{Created_within_days: 30, Tag_ids: []uint{2, 2}},
+	}, "admin1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 30, segment.Filter.Created_within_days)
+}
+
+func TestCreateSegment_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  SegmentParam
+		err  error
+	}{
+		{name: "blank name", req: SegmentParam{Name: " "}, err: ErrSegmentNameRequired},
+		{name: "bad date", req: SegmentParam{Name: "a", Filter: SegmentFilterParam{Created_to: "tomorrow"}}, err: ErrInvalidDate},
+		{
+			name: "two lower bounds",
+			req:  SegmentParam{Name: "a", Filter: SegmentFilterParam{Created_from: "2024-01-01", Created_within_days: 7}},
+			err:  ErrSegmentCreatedRange,
+		},
+		{name: "unknown tag", req: SegmentParam{Name: "a", Filter: SegmentFilterParam{Tag_ids: []uint{42}}}, err: ErrSegmentUnknownTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockActorRepo := mocks.NewActorInterfaceRepo(t)
+			mockTagRepo := new(mocks.TagInterfaceRepo)
+			mockSegmentRepo := mocks.NewSegmentInterfaceRepo(t)
+			useCase := useCaseCustomer{actorRepo: mockActorRepo, tagRepo: mockTagRepo, segmentRepo: mockSegmentRepo}
+			mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5}, nil)
+			mockTagRepo.On("GetTagsByIds", []uint{42}).Return([]entity.Tag{}, nil)
+
+			_, err := useCase.CreateSegment(tt.req, "admin1")
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.ErrorIs(t, err, apperror.ErrValidation)
+		})
+	}
+}
+
+func TestUpdateSegment_NameTaken(t *testing.T) {
+	mockSegmentRepo := mocks.NewSegmentInterfaceRepo(t)
+	useCase := useCaseCustomer{segmentRepo: mockSegmentRepo}
+
+	mockSegmentRepo.On("UpdateSegment", mock.Anything, uint(1)).Return(apperror.Conflict("segment already exists", nil))
+
+	_, err := useCase.UpdateSegment(SegmentParam{Name: "Leads"}, 1)
+
+	assert.ErrorIs(t, err, ErrSegmentNameTaken)
+}
+
+func TestListCustomers_Segment(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	mockSegmentRepo := mocks.NewSegmentInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, segmentRepo: mockSegmentRepo}
+
+	mockSegmentRepo.On("GetSegmentById", uint(3)).Return(entity.Segment{
+		ID:     3,
+		Filter: entity.SegmentFilter{Email: "example.com", Created_within_days: 30, Tag_ids: []uint{2}},
+	}, nil)
+	mockSegmentRepo.On("GetSegmentById", uint(4)).Return(entity.Segment{}, apperror.NotFound("segment not found"))
+	mockRepo.On("ListCustomers", mock.MatchedBy(func(filter repository.CustomerFilter) bool {
+		segment := filter.Segment
+		return filter.First_name == "jo" && segment != nil &&
+			segment.Email == "example.com" &&
+			assert.ObjectsAreEqual([]uint{2}, segment.Tag_ids) &&
+			time.Since(*segment.CreatedFrom) > 29*24*time.Hour &&
+			time.Since(*segment.CreatedFrom) < 31*24*time.Hour
+	})).Return([]entity.Customer{{ID: 1}}, int64(1), nil)
+
+	page, err := useCase.ListCustomers(CustomerListParam{First_name: "jo", Segment: 3})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	_, err = useCase.ListCustomers(CustomerListParam{Segment: 4})
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
@@ -0,0 +1,91 @@
+package customers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+)
+
+var (
+	ErrInvalidTags = apperror.Validation("tags must be comma separated tag ids", nil)
+	ErrUnknownTag  = apperror.NotFound("tag not found")
+)
+
+// TagCustomer put tags on a customer, tags it already has are kept once
+func (uc useCaseCustomer) TagCustomer(id uint, tagIds []uint) (entity.Customer, error) {
+	_, err := uc.customerRepo.GetCustomerById(id)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	tagIds = uniqueIds(tagIds)
+	err = uc.checkTagsExist(tagIds, ErrUnknownTag)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	err = uc.tagRepo.AddCustomerTags(id, tagIds)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	return uc.GetCustomerById(id)
+}
+
+// UntagCustomer take tags off a customer, tags it does not have are ignored
+func (uc useCaseCustomer) UntagCustomer(id uint, tagIds []uint) (entity.Customer, error) {
+	_, err := uc.customerRepo.GetCustomerById(id)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	err = uc.tagRepo.RemoveCustomerTags(id, uniqueIds(tagIds))
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	return uc.GetCustomerById(id)
+
+}
+
+// checkTagsExist notFound unless every one of the unique tagIds is a tag
+func (uc useCaseCustomer) checkTagsExist(tagIds []uint, notFound error) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+	tags, err := uc.tagRepo.GetTagsByIds(tagIds)
+	if err != nil {
+		return err
+	}
+	if len(tags) != len(tagIds) {
+		return notFound
+	}
+	return nil
+}
+
+// parseTagIds read the "3,7" tags filter of the customer list
+func parseTagIds(value string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || id == 0 {
+			return nil, ErrInvalidTags
+		}
+		ids = append(ids, uint(id))
+	}
+	return uniqueIds(ids), nil
+}
+
+// uniqueIds ids without repeats, in the order they first appear
+func uniqueIds(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := ids[:0:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,73 @@
+package customers
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagCustomer(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	mockTagRepo := mocks.NewTagInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, tagRepo: mockTagRepo}
+
+	tagged := entity.Customer{ID: 1, Tags: []entity.Tag{{ID: 3, Name: "lead"}, {ID: 2, Name: "vip"}}}
+	mockRepo.On("GetCustomerById", uint(1)).Return(tagged, nil)
+	mockTagRepo.On("GetTagsByIds", []uint{2, 3}).Return([]entity.Tag{{ID: 2}, {ID: 3}}, nil)
+	mockTagRepo.On("AddCustomerTags", uint(1), []uint{2, 3}).Return(nil)
+
+	customer, err := useCase.TagCustomer(1, []uint{2, 3, 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, tagged, customer)
+}
+
+func TestTagCustomer_UnknownTag(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	mockTagRepo := mocks.NewTagInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, tagRepo: mockTagRepo}
+
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1}, nil)
+	mockTagRepo.On("GetTagsByIds", []uint{2, 42}).Return([]entity.Tag{{ID: 2}}, nil)
+
+	_, err := useCase.TagCustomer(1, []uint{2, 42})
+
+	assert.ErrorIs(t, err, ErrUnknownTag)
+	mockTagRepo.AssertNotCalled(t, "AddCustomerTags", mock.Anything, mock.Anything)
+}
+
+func TestUntagCustomer(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	mockTagRepo := mocks.NewTagInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo, tagRepo: mockTagRepo}
+
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1}, nil)
+	mockTagRepo.On("RemoveCustomerTags", uint(1), []uint{4}).Return(nil)
+
+	customer, err := useCase.UntagCustomer(1, []uint{4, 4})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), customer.ID)
+}
+
+func TestListCustomers_TagsFilter(t *testing.T) {
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+	useCase := useCaseCustomer{customerRepo: mockRepo}
+
+	mockRepo.On("ListCustomers", mock.MatchedBy(func(filter repository.CustomerFilter) bool {
+		return assert.ObjectsAreEqual([]uint{7, 3}, filter.Tag_ids) && filter.Segment == nil
+	})).Return([]entity.Customer{}, int64(0), nil)
+
+	_, err := useCase.ListCustomers(CustomerListParam{Tags: "7, 3,7"})
+	require.NoError(t, err)
+
+	for _, tags := range []string{"vip", "1,-2", "0"} {
+		_, err = useCase.ListCustomers(CustomerListParam{Tags: tags})
+		assert.ErrorIs(t, err, ErrInvalidTags, tags)
+	}
+}
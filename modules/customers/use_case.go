@@ -1,20 +1,108 @@
 package customers
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"time"
 
+	"github.com/alkamalp/crm-golang/dto"
 	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
 	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/storage"
 )
 
 type UseCaseCustomer interface {
 	CreateCustomer(customer CustomerParam) (entity.Customer, error)
 	GetCustomerById(id uint) (entity.Customer, error)
 	UpdateCustomer(customer CustomerParam, id uint) (any, error)
-	DeleteCustomer(id uint) (any, error)
+	DeleteCustomer(id uint, actorName string) (any, error)
+	ListCustomers(req CustomerListParam) (CustomerPage, error)
+	ListDuplicates(req DuplicateListParam) (DuplicatePage, error)
+	ScanDuplicates() (entity.DuplicateScan, error)
+	MergeCustomers(req MergeCustomerParam, actorName string, trail entity.AuditLog) (entity.Customer, error)
+	ListTrash(req TrashListParam) (CustomerPage, error)
+	RestoreCustomer(id uint) (entity.Customer, error)
+	PurgeTrash(deletedBefore time.Time) (int64, error)
+	ImportCustomers(r io.Reader, opts ImportOptions) (ImportReport, error)
+	ExportCustomers(filter CustomerListParam, req ExportParam, w io.Writer) error
+	UploadAvatar(id uint, file io.Reader, contentType string) (entity.Customer, error)
+	GetAvatar(id uint, size string) (AvatarFile, error)
+	TagCustomer(id uint, tagIds []uint) (entity.Customer, error)
+	UntagCustomer(id uint, tagIds []uint) (entity.Customer, error)
+	CreateSegment(req SegmentParam, actorName string) (entity.Segment, error)
+	GetSegment(id uint) (entity.Segment, error)
+	ListSegments(req SegmentListParam) (SegmentPage, error)
+	UpdateSegment(req SegmentParam, id uint) (entity.Segment, error)
+	DeleteSegment(id uint) error
+	CreateCustomField(req CustomFieldParam) (entity.CustomField, error)
+	GetCustomField(id uint) (entity.CustomField, error)
+	ListCustomFields() ([]entity.CustomField, error)
+	UpdateCustomField(req UpdateCustomFieldParam, id uint) (entity.CustomField, error)
+	DeleteCustomField(id uint) error
+	SetCustomFields(id uint, values map[string]any) (entity.Customer, error)
+	CreateActivity(customerId uint, req ActivityParam, actorName string) (entity.Activity, error)
+	UpdateActivity(customerId uint, id uint, req ActivityParam) (entity.Activity, error)
+	DeleteActivity(customerId uint, id uint) error
+	GetTimeline(customerId uint, req TimelineParam) (TimelinePage, error)
+	GetCustomerAsOf(id uint, at time.Time) (entity.Customer, error)
+	GetCustomerHistory(id uint, req HistoryParam) (HistoryPage, error)
+	RevertCustomer(id uint, version uint) (entity.Customer, error)
+}
+
+// customerFields CustomerParam fields a merge or an import can set
+var customerFields = map[string]bool{
+	"first_name": true,
+	"last_name":  true,
+	"email":      true,
+	"avatar":     true,
+}
+
+var (
+	ErrInvalidSort   = apperror.Validation("invalid sort field", nil)
+	ErrInvalidCursor = apperror.Validation("invalid cursor", nil)
+	ErrInvalidDate   = apperror.Validation("invalid date, use YYYY-MM-DD or RFC 3339", nil)
+
+	ErrMergeSurvivorIsVictim = apperror.Validation("survivor cannot also be a victim", nil)
+	ErrMergeDuplicateVictim  = apperror.Validation("victim_ids must not repeat", nil)
+	ErrMergeUnknownField     = apperror.Validation("fields may only choose first_name, last_name, email or avatar", nil)
+	ErrMergeFieldSource      = apperror.Validation("fields must choose the survivor or one of the victims", nil)
+	ErrUnknownAccount        = apperror.Validation("account_id does not match an account", nil)
+)
+
+// CustomerPage one page of ListCustomers, Page is 0 for cursor requests
+type CustomerPage struct {
+	Customers  []entity.Customer
+	Total      int64
+	Page       int
+	Limit      int
+	NextCursor string
+}
+
+// DuplicatePage one page of the duplicates report and the scan it comes from
+type DuplicatePage struct {
+	Pairs []DuplicatePair
+	Total int64
+	Page  int
+	Limit int
+	Scan  entity.DuplicateScan
 }
 
 type useCaseCustomer struct {
-	customerRepo repository.CustomerInterfaceRepo
+	customerRepo  repository.CustomerInterfaceRepo
+	actorRepo     repository.ActorInterfaceRepo
+	tagRepo       repository.TagInterfaceRepo
+	segmentRepo   repository.SegmentInterfaceRepo
+	fieldRepo     repository.CustomFieldInterfaceRepo
+	activityRepo  repository.ActivityInterfaceRepo
+	accountRepo   repository.AccountInterfaceRepo
+	duplicateRepo repository.DuplicateInterfaceRepo
+	avatarStore   storage.Storage
+	avatarMaxSize int
 }
 
 func (uc useCaseCustomer) CreateCustomer(customer CustomerParam) (entity.Customer, error) {
@@ -23,23 +111,61 @@ func (uc useCaseCustomer) CreateCustomer(customer CustomerParam) (entity.Custome
 	newCustomer = &entity.Customer{
 		First_name: customer.First_name,
 		Last_name:  customer.Last_name,
-		Email:      customer.Email,
+		Email:      strings.TrimSpace(customer.Email),
 		Avatar:     customer.Avatar,
+		Account_id: customer.Account_id,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
 
-	_, err := uc.customerRepo.CreateCustomer(newCustomer)
+	if newCustomer.Account_id != nil {
+		_, err := uc.accountRepo.GetAccountById(*newCustomer.Account_id)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return *newCustomer, ErrUnknownAccount
+		}
+		if err != nil {
+			return *newCustomer, err
+		}
+	}
+
+	fields, err := uc.fieldRepo.ListCustomFields()
+	if err != nil {
+		return *newCustomer, err
+	}
+	newCustomer.Field_values, _, err = customFieldValues(fields, customer.Custom_fields, true)
+	if err != nil {
+		return *newCustomer, err
+	}
+	newCustomer.Custom_fields = customFieldMap(fields, newCustomer.Field_values)
+
+	err = uc.checkEmailAvailable(newCustomer.Email, 0)
 	if err != nil {
 		return *newCustomer, err
 	}
+	_, err = uc.customerRepo.CreateCustomer(newCustomer)
+	if err != nil {
+		return *newCustomer, uc.explainConflict(err, newCustomer.Email, 0)
+	}
 	return *newCustomer, nil
 }
 
+// GetCustomerById ids of merged customers resolve to the survivor
 func (uc useCaseCustomer) GetCustomerById(id uint) (entity.Customer, error) {
 	var customer entity.Customer
 	customer, err := uc.customerRepo.GetCustomerById(id)
-	return customer, err
+	if errors.Is(err, apperror.ErrNotFound) {
+		redirect, redirectErr := uc.customerRepo.GetCustomerRedirect(id)
+		if redirectErr != nil {
+			return customer, err
+		}
+		customer, err = uc.customerRepo.GetCustomerById(redirect.Survivor_id)
+	}
+	if err != nil {
+		return customer, err
+	}
+	customers := []entity.Customer{customer}
+	err = uc.withCustomFields(customers)
+	return customers[0], err
 }
 
 func (uc useCaseCustomer) UpdateCustomer(customer CustomerParam, id uint) (any, error) {
@@ -47,19 +173,452 @@ func (uc useCaseCustomer) UpdateCustomer(customer CustomerParam, id uint) (any,
 	editCustomer = &entity.Customer{
 		First_name: customer.First_name,
 		Last_name:  customer.Last_name,
-		Email:      customer.Email,
+		Email:      strings.TrimSpace(customer.Email),
 		Avatar:     customer.Avatar,
 		UpdatedAt:  time.Now(),
 	}
 
+	if editCustomer.Email != "" {
+		err := uc.checkEmailAvailable(editCustomer.Email, id)
+		if err != nil {
+			return *editCustomer, err
+		}
+	}
 	_, err := uc.customerRepo.UpdateCustomer(editCustomer, id)
 	if err != nil {
-		return *editCustomer, err
+		return *editCustomer, uc.explainConflict(err, editCustomer.Email, id)
 	}
 	return *editCustomer, nil
 }
 
-func (uc useCaseCustomer) DeleteCustomer(id uint) (any, error) {
-	_, err := uc.customerRepo.DeleteCustomer(id)
+// checkEmailAvailable conflict pointing at the existing customer when a
+// customer other than id already has email
+func (uc useCaseCustomer) checkEmailAvailable(email string, id uint) error {
+	existing, err := uc.customerRepo.GetCustomerByEmail(email)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID == id {
+		return nil
+	}
+	return apperror.Conflict("a customer with this email already exists", ExistingCustomer{
+		ID:    existing.ID,
+		Email: existing.Email,
+		Href:  fmt.Sprintf("/customer/%d", existing.ID),
+	})
+}
+
+// explainConflict add the existing customer to a unique index violation,
+// which happens when a concurrent request took the email after the check
+func (uc useCaseCustomer) explainConflict(err error, email string, id uint) error {
+	if !errors.Is(err, apperror.ErrConflict) || email == "" {
+		return err
+	}
+	if conflict := uc.checkEmailAvailable(email, id); conflict != nil {
+		return conflict
+	}
+	return err
+}
+
+// DeleteCustomer move to the trash, recording who deleted it
+func (uc useCaseCustomer) DeleteCustomer(id uint, actorName string) (any, error) {
+	actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return nil, err
+	}
+	_, err = uc.customerRepo.DeleteCustomer(id, actor.ID)
 	return nil, err
 }
+
+// ListTrash page of deleted customers, most recently deleted first
+func (uc useCaseCustomer) ListTrash(req TrashListParam) (CustomerPage, error) {
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	customers, total, err := uc.customerRepo.ListDeletedCustomers(limit, (page-1)*limit)
+	if err != nil {
+		return CustomerPage{}, err
+	}
+	return CustomerPage{
+		Customers: customers,
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}, nil
+}
+
+// RestoreCustomer take a customer out of the trash, a conflict points at the
+// customer that took its email in the meantime
+func (uc useCaseCustomer) RestoreCustomer(id uint) (entity.Customer, error) {
+	customer, err := uc.customerRepo.RestoreCustomer(id)
+	if err != nil {
+		return entity.Customer{}, uc.explainConflict(err, customer.Email, id)
+	}
+	customers := []entity.Customer{customer}
+	err = uc.withCustomFields(customers)
+	return customers[0], err
+}
+
+// PurgeTrash permanently delete customers trashed before deletedBefore
+func (uc useCaseCustomer) PurgeTrash(deletedBefore time.Time) (int64, error) {
+	purged, err := uc.customerRepo.PurgeCustomers(deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	for _, customer := range purged {
+		if customer.Avatar_key != nil {
+			uc.deleteAvatarFiles(*customer.Avatar_key)
+		}
+	}
+	return int64(len(purged)), nil
+}
+
+func (uc useCaseCustomer) ListCustomers(req CustomerListParam) (CustomerPage, error) {
+	filter, err := uc.customerFilter(req)
+	if err != nil {
+		return CustomerPage{}, err
+	}
+	sort := filter.Sort
+
+	limit, page := dto.PageOf(req.Limit, req.Page)
+	// one extra row tells whether there is a next page
+	filter.Limit = limit + 1
+
+	if req.Cursor != "" {
+		page = 0
+		filter.After, err = decodeCursor(req.Cursor, sort)
+		if err != nil {
+			return CustomerPage{}, err
+		}
+	} else {
+		filter.Offset = (page - 1) * limit
+	}
+
+	customers, total, err := uc.customerRepo.ListCustomers(filter)
+	if err != nil {
+		return CustomerPage{}, err
+	}
+	err = uc.withCustomFields(customers)
+	if err != nil {
+		return CustomerPage{}, err
+	}
+
+	res := CustomerPage{
+		Customers: customers,
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}
+	if len(customers) > limit {
+		res.Customers = customers[:limit]
+		res.NextCursor = encodeCursor(res.Customers[limit-1], sort)
+	}
+	return res, nil
+}
+
+// customerFilter criteria of req shared by the list and the export, without
+// paging, a segment adds the criteria saved with it
+func (uc useCaseCustomer) customerFilter(req CustomerListParam) (repository.CustomerFilter, error) {
+	sort, err := parseSort(req.Sort)
+	if err != nil {
+		return repository.CustomerFilter{}, err
+	}
+	createdFrom, err := parseDate(req.CreatedFrom, false)
+	if err != nil {
+		return repository.CustomerFilter{}, err
+	}
+	createdTo, err := parseDate(req.CreatedTo, true)
+	if err != nil {
+		return repository.CustomerFilter{}, err
+	}
+	tagIds, err := parseTagIds(req.Tags)
+	if err != nil {
+		return repository.CustomerFilter{}, err
+	}
+	fieldConditions, err := uc.fieldConditions(req.Custom_fields)
+	if err != nil {
+		return repository.CustomerFilter{}, err
+	}
+	filter := repository.CustomerFilter{
+		First_name:  req.First_name,
+		Last_name:   req.Last_name,
+		Email:       req.Email,
+		Account_id:  req.Account,
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		Tag_ids:     tagIds,
+		Fields:      fieldConditions,
+		Sort:        sort,
+	}
+	if req.Segment != 0 {
+		segment, err := uc.segmentRepo.GetSegmentById(req.Segment)
+		if err != nil {
+			return repository.CustomerFilter{}, err
+		}
+		segmentFilter, err := uc.segmentCustomerFilter(segment.Filter, time.Now())
+		if err != nil {
+			return repository.CustomerFilter{}, err
+		}
+		filter.Segment = &segmentFilter
+	}
+	return filter, nil
+}
+
+// ListDuplicates page of likely duplicate customers found by the latest
+// scan, the first request scans when there has been none
+func (uc useCaseCustomer) ListDuplicates(req DuplicateListParam) (DuplicatePage, error) {
+	scan, err := uc.duplicateRepo.GetLatestDuplicateScan()
+	if errors.Is(err, apperror.ErrNotFound) {
+		scan, err = uc.ScanDuplicates()
+	}
+	if err != nil {
+		return DuplicatePage{}, err
+	}
+
+	minScore := req.MinScore
+	if minScore <= 0 {
+		minScore = defaultDuplicateScore
+	}
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	duplicates, total, err := uc.duplicateRepo.ListCustomerDuplicates(scan.ID, minScore, limit, (page-1)*limit)
+	if err != nil {
+		return DuplicatePage{}, err
+	}
+	res := DuplicatePage{
+		Total: total,
+		Page:  page,
+		Limit: limit,
+		Scan:  scan,
+	}
+	for _, duplicate := range duplicates {
+		var reasons []string
+		if duplicate.Reasons != "" {
+			reasons = strings.Split(duplicate.Reasons, ",")
+		}
+		res.Pairs = append(res.Pairs, DuplicatePair{
+			Score:       duplicate.Score,
+			Name_score:  duplicate.Name_score,
+			Email_score: duplicate.Email_score,
+			Reasons:     reasons,
+			Customers:   []entity.Customer{duplicate.Customer, duplicate.Other},
+		})
+	}
+	return res, nil
+}
+
+// ScanDuplicates compare every customer and replace the stored pairs with
+// the ones scoring at least minDuplicateScore, every customer is read so
+// this runs as a job rather than on each request
+func (uc useCaseCustomer) ScanDuplicates() (entity.DuplicateScan, error) {
+	index := newDuplicateIndex()
+	err := uc.customerRepo.EachCustomerBatch(500, func(batch []entity.Customer) error {
+		index.add(batch)
+		return nil
+	})
+	if err != nil {
+		return entity.DuplicateScan{}, err
+	}
+
+	pairs, truncated := index.pairs(minDuplicateScore)
+	scan := entity.DuplicateScan{
+		Customers:        len(index.candidates),
+		Pairs:            len(pairs),
+		Truncated_blocks: truncated,
+	}
+	err = uc.duplicateRepo.SaveDuplicateScan(&scan, pairs)
+	return scan, err
+}
+
+// MergeCustomers fold the victims into the survivor, which keeps its id and
+// takes every field from the customer req.Fields chooses for it. The audit
+// entry of the merge carries the request id and client ip of trail
+func (uc useCaseCustomer) MergeCustomers(req MergeCustomerParam, actorName string, trail entity.AuditLog) (entity.Customer, error) {
+	sources := map[uint]bool{req.Survivor_id: true}
+	for _, id := range req.Victim_ids {
+		if id == req.Survivor_id {
+			return entity.Customer{}, ErrMergeSurvivorIsVictim
+		}
+		if sources[id] {
+			return entity.Customer{}, ErrMergeDuplicateVictim
+		}
+		sources[id] = true
+	}
+	for field, id := range req.Fields {
+		if !customerFields[field] {
+			return entity.Customer{}, ErrMergeUnknownField
+		}
+		if !sources[id] {
+			return entity.Customer{}, ErrMergeFieldSource
+		}
+	}
+
+	actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+
+	survivor, err := uc.customerRepo.GetCustomerById(req.Survivor_id)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	customers := map[uint]entity.Customer{survivor.ID: survivor}
+	victims := make([]entity.Customer, len(req.Victim_ids))
+	for i, id := range req.Victim_ids {
+		victims[i], err = uc.customerRepo.GetCustomerById(id)
+		if err != nil {
+			return entity.Customer{}, err
+		}
+		customers[id] = victims[i]
+	}
+
+	merged := survivor
+	for field, id := range req.Fields {
+		source := customers[id]
+		switch field {
+		case "first_name":
+			merged.First_name = source.First_name
+		case "last_name":
+			merged.Last_name = source.Last_name
+		case "email":
+			merged.Email = source.Email
+		case "avatar":
+			merged.Avatar = source.Avatar
+			merged.Avatar_key = source.Avatar_key
+		}
+	}
+	merged.UpdatedAt = time.Now()
+
+	before, err := json.Marshal(map[string]any{"survivor": survivor, "victims": victims})
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	after, err := json.Marshal(merged)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	beforeJson, afterJson := string(before), string(after)
+	audit := &entity.AuditLog{
+		Actor_id:    &actor.ID,
+		Action:      entity.AuditActionMerge,
+		Entity_type: "customer",
+		Entity_id:   survivor.ID,
+		Before:      &beforeJson,
+		After:       &afterJson,
+		Request_id:  trail.Request_id,
+		Client_ip:   trail.Client_ip,
+	}
+
+	err = uc.customerRepo.MergeCustomers(&merged, req.Victim_ids, audit)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	for _, customer := range customers {
+		if customer.Avatar_key != nil && (merged.Avatar_key == nil || *customer.Avatar_key != *merged.Avatar_key) {
+			uc.deleteAvatarFiles(*customer.Avatar_key)
+		}
+	}
+	return merged, nil
+}
+
+// parseSort read "-created_at,last_name" style sort, id is always appended
+// as tie breaker so cursors are stable
+func parseSort(sort string) ([]repository.SortField, error) {
+	var fields []repository.SortField
+	hasId := false
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := repository.SortField{Column: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !repository.CustomerSortColumns[field.Column] {
+			return nil, ErrInvalidSort
+		}
+		if field.Column == "id" {
+			hasId = true
+		}
+		fields = append(fields, field)
+	}
+	if !hasId {
+		fields = append(fields, repository.SortField{Column: "id"})
+	}
+	return fields, nil
+}
+
+// parseDate accept a date or RFC 3339 timestamp, a date used as upper bound
+// includes the whole day
+func parseDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+func encodeCursor(customer entity.Customer, sort []repository.SortField) string {
+	values := make([]any, len(sort))
+	for i, field := range sort {
+		switch field.Column {
+		case "id":
+			values[i] = customer.ID
+		case "first_name":
+			values[i] = customer.First_name
+		case "last_name":
+			values[i] = customer.Last_name
+		case "email":
+			values[i] = customer.Email
+		case "created_at":
+			values[i] = customer.CreatedAt
+		case "updated_at":
+			values[i] = customer.UpdatedAt
+		}
+	}
+	raw, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(cursor string, sort []repository.SortField) ([]any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var encoded []json.RawMessage
+	if json.Unmarshal(raw, &encoded) != nil || len(encoded) != len(sort) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]any, len(sort))
+	for i, field := range sort {
+		var err error
+		switch field.Column {
+		case "id":
+			var id uint
+			err = json.Unmarshal(encoded[i], &id)
+			values[i] = id
+		case "created_at", "updated_at":
+			var t time.Time
+			err = json.Unmarshal(encoded[i], &t)
+			values[i] = t.UTC()
+		default:
+			var str string
+			err = json.Unmarshal(encoded[i], &str)
+			values[i] = str
+		}
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return values, nil
+}
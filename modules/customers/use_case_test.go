@@ -3,33 +3,28 @@ package customers
 import (
 	"errors"
 	"fmt"
+	"github.com/alkamalp/crm-golang/dto"
 	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"strings"
 	"testing"
 	"time"
 )
 
-type MockCustomerRepo struct {
-	mock.Mock
-}
-
-func (m *MockCustomerRepo) CreateCustomer(customer *entity.Customer) (*entity.Customer, error) {
-	args := m.Called(customer)
-	result := args.Get(0)
-	err := args.Error(1)
-	if result == nil {
-		return nil, err
-	}
-	return result.(*entity.Customer), err
-}
-
 func TestCreateCustomer(t *testing.T) {
 
-	mockRepo := new(MockCustomerRepo)
+	mockRepo := new(mocks.CustomerInterfaceRepo)
+
+	fieldRepo := new(mocks.CustomFieldInterfaceRepo)
+	fieldRepo.On("ListCustomFields").Return(nil, nil)
 
 	useCase := useCaseCustomer{
 		customerRepo: mockRepo,
+		fieldRepo:    fieldRepo,
 	}
 
 	customer := CustomerParam{
@@ -39,6 +34,7 @@ func TestCreateCustomer(t *testing.T) {
 		Avatar:     "avatar.jpg",
 	}
 
+	mockRepo.On("GetCustomerByEmail", customer.Email).Return(entity.Customer{}, apperror.NotFound("customer not found"))
 	mockRepo.On("CreateCustomer", mock.AnythingOfType("*entity.Customer")).Return(&entity.Customer{}, nil)
 
 	createdCustomer, err := useCase.CreateCustomer(customer)
@@ -49,19 +45,9 @@ func TestCreateCustomer(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func (m *MockCustomerRepo) GetCustomerById(id uint) (entity.Customer, error) {
-	args := m.Called(id)
-	result := args.Get(0)
-	err := args.Error(1)
-	if result == nil {
-		return entity.Customer{}, err
-	}
-	return result.(entity.Customer), err
-}
-
 func TestGetCustomerById(t *testing.T) {
 
-	mockRepo := new(MockCustomerRepo)
+	mockRepo := new(mocks.CustomerInterfaceRepo)
 
 	useCase := useCaseCustomer{
 		customerRepo: mockRepo,
@@ -87,7 +73,7 @@ func TestGetCustomerById(t *testing.T) {
 
 func TestGetCustomerById_Error(t *testing.T) {
 
-	mockRepo := new(MockCustomerRepo)
+	mockRepo := new(mocks.CustomerInterfaceRepo)
 
 	useCase := useCaseCustomer{
 		customerRepo: mockRepo,
@@ -107,13 +93,19 @@ func TestGetCustomerById_Error(t *testing.T) {
 	assert.Equal(t, entity.Customer{}, result)
 }
 
-func (m *MockCustomerRepo) UpdateCustomer(customer *entity.Customer, id uint) (interface{}, error) {
-	args := m.Called(customer, id)
-	return args.Get(0), args.Error(1)
+// sameCustomer match the fields UpdateCustomer copies from CustomerParam,
+// UpdatedAt is stamped by the use case itself
+func sameCustomer(expected *entity.Customer) func(*entity.Customer) bool {
+	return func(actual *entity.Customer) bool {
+		return actual.First_name == expected.First_name &&
+			actual.Last_name == expected.Last_name &&
+			actual.Email == expected.Email &&
+			actual.Avatar == expected.Avatar
+	}
 }
 
 func TestUpdateCustomer(t *testing.T) {
-	mockRepo := new(MockCustomerRepo)
+	mockRepo := new(mocks.CustomerInterfaceRepo)
 	useCase := useCaseCustomer{
 		customerRepo: mockRepo,
 	}
@@ -134,15 +126,19 @@ func TestUpdateCustomer(t *testing.T) {
 		UpdatedAt:  time.Now(),
 	}
 
-	mockRepo.On("UpdateCustomer", expectedCustomer, customerID).Return(expectedCustomer, nil)
+	mockRepo.On("GetCustomerByEmail", customer.Email).Return(entity.Customer{ID: customerID, Email: customer.Email}, nil)
+	mockRepo.On("UpdateCustomer", mock.MatchedBy(sameCustomer(expectedCustomer)), customerID).Return(expectedCustomer, nil)
 	result, err := useCase.UpdateCustomer(customer, customerID)
-	mockRepo.AssertCalled(t, "UpdateCustomer", expectedCustomer, customerID)
-	assert.Equal(t, *expectedCustomer, result)
+	mockRepo.AssertCalled(t, "UpdateCustomer", mock.MatchedBy(sameCustomer(expectedCustomer)), customerID)
+	updatedCustomer := result.(entity.Customer)
+	assert.WithinDuration(t, expectedCustomer.UpdatedAt, updatedCustomer.UpdatedAt, time.Second)
+	updatedCustomer.UpdatedAt = expectedCustomer.UpdatedAt
+	assert.Equal(t, *expectedCustomer, updatedCustomer)
 	assert.NoError(t, err)
 }
 
 func TestUpdateCustomer_Error(t *testing.T) {
-	mockRepo := new(MockCustomerRepo)
+	mockRepo := new(mocks.CustomerInterfaceRepo)
 
 	useCase := useCaseCustomer{
 		customerRepo: mockRepo,
@@ -162,36 +158,33 @@ func TestUpdateCustomer_Error(t *testing.T) {
 		UpdatedAt:  time.Now(),
 	}
 	expectedError := fmt.Errorf("failed to update customer")
-	mockRepo.On("UpdateCustomer", expectedCustomer, customerID).Return(nil, expectedError)
+	mockRepo.On("GetCustomerByEmail", customer.Email).Return(entity.Customer{}, apperror.NotFound("customer not found"))
+	mockRepo.On("UpdateCustomer", mock.MatchedBy(sameCustomer(expectedCustomer)), customerID).Return(nil, expectedError)
 	result, err := useCase.UpdateCustomer(customer, customerID)
-	mockRepo.AssertCalled(t, "UpdateCustomer", expectedCustomer, customerID)
+	mockRepo.AssertCalled(t, "UpdateCustomer", mock.MatchedBy(sameCustomer(expectedCustomer)), customerID)
 	assert.EqualError(t, err, expectedError.Error())
 	assert.NotNil(t, result)
 }
 
-func (m *MockCustomerRepo) DeleteCustomer(id uint) (interface{}, error) {
-	args := m.Called(id)
-	result := args.Get(0)
-	err := args.Error(1)
-	return result, err
-}
-
 func TestDeleteCustomer(t *testing.T) {
 
-	mockRepo := new(MockCustomerRepo)
+	mockRepo := new(mocks.CustomerInterfaceRepo)
 
+	mockActorRepo := new(mocks.ActorInterfaceRepo)
 	useCase := useCaseCustomer{
 		customerRepo: mockRepo,
+		actorRepo:    mockActorRepo,
 	}
 
 	var id uint
 	id = 1
 
-	mockRepo.On("DeleteCustomer", id).Return(nil, nil)
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockRepo.On("DeleteCustomer", id, uint(5)).Return(nil, nil)
 
-	result, err := useCase.DeleteCustomer(id)
+	result, err := useCase.DeleteCustomer(id, "admin1")
 
-	mockRepo.AssertCalled(t, "DeleteCustomer", id)
+	mockRepo.AssertCalled(t, "DeleteCustomer", id, uint(5))
 
 	assert.NoError(t, err)
 	assert.Nil(t, result)
@@ -199,10 +192,12 @@ func TestDeleteCustomer(t *testing.T) {
 
 func TestDeleteCustomer_Error(t *testing.T) {
 
-	mockRepo := new(MockCustomerRepo)
+	mockRepo := new(mocks.CustomerInterfaceRepo)
 
+	mockActorRepo := new(mocks.ActorInterfaceRepo)
 	useCase := useCaseCustomer{
 		customerRepo: mockRepo,
+		actorRepo:    mockActorRepo,
 	}
 
 	var id uint
@@ -210,13 +205,434 @@ func TestDeleteCustomer_Error(t *testing.T) {
 
 	expectedError := errors.New("failed to delete customer")
 
-	mockRepo.On("DeleteCustomer", id).Return(nil, expectedError)
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockRepo.On("DeleteCustomer", id, uint(5)).Return(nil, expectedError)
 
-	result, err := useCase.DeleteCustomer(id)
+	result, err := useCase.DeleteCustomer(id, "admin1")
 
-	mockRepo.AssertCalled(t, "DeleteCustomer", id)
+	mockRepo.AssertCalled(t, "DeleteCustomer", id, uint(5))
 
 	assert.Error(t, err)
 	assert.EqualError(t, err, expectedError.Error())
 	assert.Nil(t, result)
 }
+
+func TestListCustomers(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+	}
+
+	customers := []entity.Customer{
+		{ID: 1, First_name: "John", Last_name: "Doe"},
+		{ID: 2, First_name: "Jane", Last_name: "Doe"},
+		{ID: 3, First_name: "Jim", Last_name: "Doe"},
+	}
+
+	expectedFilter := repository.CustomerFilter{
+		Last_name: "doe",
+		Sort: []repository.SortField{
+			{Column: "first_name", Desc: true},
+			{Column: "id"},
+		},
+		Limit:  3,
+		Offset: 2,
+	}
+
+	mockRepo.On("ListCustomers", expectedFilter).Return(customers, int64(5), nil)
+
+	result, err := useCase.ListCustomers(CustomerListParam{
+		Page:      2,
+		Limit:     2,
+		Last_name: "doe",
+		Sort:      "-first_name",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, customers[:2], result.Customers)
+	assert.Equal(t, int64(5), result.Total)
+	assert.Equal(t, 2, result.Page)
+	assert.NotEmpty(t, result.NextCursor)
+}
+
+func TestListCustomers_Cursor(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+	}
+
+	sort, err := parseSort("last_name")
+	assert.NoError(t, err)
+	cursor := encodeCursor(entity.Customer{ID: 7, Last_name: "Doe"}, sort)
+
+	mockRepo.On("ListCustomers", mock.MatchedBy(func(filter repository.CustomerFilter) bool {
+		return filter.Offset == 0 &&
+			len(filter.After) == 2 &&
+			filter.After[0] == "Doe" &&
+			filter.After[1] == uint(7)
+	})).Return([]entity.Customer{{ID: 8, Last_name: "Doe"}}, int64(8), nil)
+
+	result, err := useCase.ListCustomers(CustomerListParam{Cursor: cursor, Sort: "last_name"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Page)
+	assert.Empty(t, result.NextCursor)
+	assert.Len(t, result.Customers, 1)
+}
+
+func TestListCustomers_InvalidParam(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+	}
+
+	_, err := useCase.ListCustomers(CustomerListParam{Sort: "password"})
+	assert.ErrorIs(t, err, ErrInvalidSort)
+
+	_, err = useCase.ListCustomers(CustomerListParam{CreatedFrom: "yesterday"})
+	assert.ErrorIs(t, err, ErrInvalidDate)
+
+	_, err = useCase.ListCustomers(CustomerListParam{Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	mockRepo.AssertNotCalled(t, "ListCustomers", mock.Anything)
+}
+
+func TestCreateCustomer_DuplicateEmail(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	fieldRepo := new(mocks.CustomFieldInterfaceRepo)
+	fieldRepo.On("ListCustomFields").Return(nil, nil)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+		fieldRepo:    fieldRepo,
+	}
+
+	existing := entity.Customer{ID: 4, First_name: "John", Email: "John.Doe@example.com"}
+	mockRepo.On("GetCustomerByEmail", "john.doe@EXAMPLE.com").Return(existing, nil)
+
+	_, err := useCase.CreateCustomer(CustomerParam{First_name: "Johnny", Email: " john.doe@EXAMPLE.com "})
+
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	var appErr *apperror.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ExistingCustomer{ID: 4, Email: "John.Doe@example.com", Href: "/customer/4"}, appErr.Details)
+	mockRepo.AssertNotCalled(t, "CreateCustomer", mock.Anything)
+}
+
+func TestCreateCustomer_WithAccount(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockAccountRepo := mocks.NewAccountInterfaceRepo(t)
+
+	fieldRepo := new(mocks.CustomFieldInterfaceRepo)
+	fieldRepo.On("ListCustomFields").Return(nil, nil)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+		fieldRepo:    fieldRepo,
+		accountRepo:  mockAccountRepo,
+	}
+
+	accountId := uint(2)
+	mockAccountRepo.On("GetAccountById", accountId).Return(entity.Account{ID: accountId, Name: "Acme"}, nil)
+	mockRepo.On("GetCustomerByEmail", "jane@example.com").Return(entity.Customer{}, apperror.NotFound("customer not found"))
+	mockRepo.On("CreateCustomer", mock.MatchedBy(func(customer *entity.Customer) bool {
+		return customer.Account_id != nil && *customer.Account_id == accountId
+	})).Return(&entity.Customer{}, nil)
+
+	customer, err := useCase.CreateCustomer(CustomerParam{First_name: "Jane", Email: "jane@example.com", Account_id: &accountId})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &accountId, customer.Account_id)
+}
+
+func TestCreateCustomer_UnknownAccount(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockAccountRepo := mocks.NewAccountInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+		accountRepo:  mockAccountRepo,
+	}
+
+	accountId := uint(9)
+	mockAccountRepo.On("GetAccountById", accountId).Return(entity.Account{}, apperror.NotFound("account not found"))
+
+	_, err := useCase.CreateCustomer(CustomerParam{First_name: "Jane", Email: "jane@example.com", Account_id: &accountId})
+
+	assert.ErrorIs(t, err, ErrUnknownAccount)
+	mockRepo.AssertNotCalled(t, "CreateCustomer", mock.Anything)
+}
+
+func TestCreateCustomer_ConcurrentDuplicate(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	fieldRepo := new(mocks.CustomFieldInterfaceRepo)
+	fieldRepo.On("ListCustomFields").Return(nil, nil)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+		fieldRepo:    fieldRepo,
+	}
+
+	// free at the check, taken by another request before the insert
+	mockRepo.On("GetCustomerByEmail", "jane@example.com").Return(entity.Customer{}, apperror.NotFound("customer not found")).Once()
+	mockRepo.On("CreateCustomer", mock.AnythingOfType("*entity.Customer")).Return(nil, apperror.Conflict("customer already exists", nil))
+	mockRepo.On("GetCustomerByEmail", "jane@example.com").Return(entity.Customer{ID: 9, Email: "jane@example.com"}, nil).Once()
+
+	_, err := useCase.CreateCustomer(CustomerParam{First_name: "Jane", Email: "jane@example.com"})
+
+	var appErr *apperror.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ExistingCustomer{ID: 9, Email: "jane@example.com", Href: "/customer/9"}, appErr.Details)
+}
+
+func TestUpdateCustomer_EmailOfAnotherCustomer(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+	}
+
+	mockRepo.On("GetCustomerByEmail", "jane@example.com").Return(entity.Customer{ID: 2, Email: "jane@example.com"}, nil)
+
+	_, err := useCase.UpdateCustomer(CustomerParam{Email: "jane@example.com"}, 1)
+
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	mockRepo.AssertNotCalled(t, "UpdateCustomer", mock.Anything, mock.Anything)
+}
+
+func TestListDuplicates(t *testing.T) {
+
+	duplicateRepo := mocks.NewDuplicateInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		duplicateRepo: duplicateRepo,
+	}
+
+	scan := entity.DuplicateScan{ID: 3, Customers: 4, Pairs: 2, Truncated_blocks: 1}
+	duplicateRepo.On("GetLatestDuplicateScan").Return(scan, nil)
+	duplicateRepo.On("ListCustomerDuplicates", uint(3), 0.8, 1, 1).Return([]entity.CustomerDuplicate{{
+		Customer_id: 1,
+		Other_id:    4,
+		Score:       0.9,
+		Reasons:     "same email address name,similar name",
+		Customer:    entity.Customer{ID: 1},
+		Other:       entity.Customer{ID: 4},
+	}}, int64(2), nil)
+
+	result, err := useCase.ListDuplicates(DuplicateListParam{Page: 2, Limit: 1, MinScore: 0.8})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, scan, result.Scan)
+	assert.Len(t, result.Pairs, 1)
+	assert.Equal(t, uint(4), result.Pairs[0].Customers[1].ID)
+	assert.Equal(t, []string{"same email address name", "similar name"}, result.Pairs[0].Reasons)
+}
+
+func TestListDuplicates_FirstScan(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	duplicateRepo := mocks.NewDuplicateInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo:  mockRepo,
+		duplicateRepo: duplicateRepo,
+	}
+
+	customers := []entity.Customer{
+		{ID: 1, First_name: "John", Last_name: "Doe", Email: "john.doe@example.com"},
+		{ID: 2, First_name: "Jon", Last_name: "Doe", Email: "johndoe@example.com"},
+		{ID: 3, First_name: "Jane", Last_name: "Smith", Email: "jane@example.com"},
+	}
+	mockRepo.On("EachCustomerBatch", 500, mock.Anything).Return(func(size int, fn func([]entity.Customer) error) error {
+		return fn(customers)
+	})
+	duplicateRepo.On("GetLatestDuplicateScan").Return(entity.DuplicateScan{}, apperror.NotFound("duplicate scan not found"))
+	var saved []entity.CustomerDuplicate
+	duplicateRepo.On("SaveDuplicateScan", mock.AnythingOfType("*entity.DuplicateScan"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(0).(*entity.DuplicateScan).ID = 1
+			saved = args.Get(1).([]entity.CustomerDuplicate)
+		}).
+		Return(nil)
+	duplicateRepo.On("ListCustomerDuplicates", uint(1), defaultDuplicateScore, dto.DefaultListLimit, 0).Return(nil, int64(0), nil)
+
+	result, err := useCase.ListDuplicates(DuplicateListParam{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Scan.Customers)
+	assert.Equal(t, 1, result.Scan.Pairs)
+	assert.Len(t, saved, 1)
+	assert.Equal(t, uint(1), saved[0].Customer_id)
+	assert.Equal(t, uint(2), saved[0].Other_id)
+	assert.Contains(t, saved[0].Reasons, "same email address name")
+}
+
+func TestScanDuplicates_AcrossBatches(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	duplicateRepo := mocks.NewDuplicateInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo:  mockRepo,
+		duplicateRepo: duplicateRepo,
+	}
+
+	// the repository reuses one slice for every batch
+	mockRepo.On("EachCustomerBatch", 500, mock.Anything).Return(func(size int, fn func([]entity.Customer) error) error {
+		batch := []entity.Customer{{ID: 1, First_name: "Budi", Last_name: "Santoso", Email: "budi@example.co.id"}}
+		err := fn(batch)
+		if err != nil {
+			return err
+		}
+		batch[0] = entity.Customer{ID: 2, First_name: "Budi", Last_name: "Santoso", Email: "BUDI@example.co.id"}
+		return fn(batch)
+	})
+	var saved []entity.CustomerDuplicate
+	duplicateRepo.On("SaveDuplicateScan", mock.AnythingOfType("*entity.DuplicateScan"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			saved = args.Get(1).([]entity.CustomerDuplicate)
+		}).
+		Return(nil)
+
+	scan, err := useCase.ScanDuplicates()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, scan.Customers)
+	assert.Equal(t, 1, scan.Pairs)
+	assert.Len(t, saved, 1)
+	assert.Equal(t, uint(1), saved[0].Customer_id)
+	assert.Equal(t, uint(2), saved[0].Other_id)
+	assert.Equal(t, 1.0, saved[0].Score)
+}
+
+func TestGetCustomerById_MergedRedirects(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+	}
+
+	survivor := entity.Customer{ID: 1, First_name: "John"}
+	mockRepo.On("GetCustomerById", uint(2)).Return(entity.Customer{}, apperror.NotFound("customer not found"))
+	mockRepo.On("GetCustomerRedirect", uint(2)).Return(entity.CustomerRedirect{Old_id: 2, Survivor_id: 1}, nil)
+	mockRepo.On("GetCustomerById", uint(1)).Return(survivor, nil)
+
+	result, err := useCase.GetCustomerById(2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, survivor, result)
+}
+
+func TestMergeCustomers(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+		actorRepo:    mockActorRepo,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1, First_name: "Jon", Last_name: "Doe", Email: "jon@example.com"}, nil)
+	mockRepo.On("GetCustomerById", uint(2)).Return(entity.Customer{ID: 2, First_name: "John", Email: "john.doe@example.com", Avatar: "john.jpg"}, nil)
+	mockRepo.On("MergeCustomers", mock.AnythingOfType("*entity.Customer"), []uint{2}, mock.MatchedBy(func(audit *entity.AuditLog) bool {
+		return *audit.Actor_id == 5 &&
+			audit.Action == entity.AuditActionMerge &&
+			audit.Entity_id == 1 &&
+			audit.Request_id == "req-1" &&
+			strings.Contains(*audit.Before, "john.doe@example.com") &&
+			strings.Contains(*audit.After, "john.jpg")
+	})).Return(nil)
+
+	merged, err := useCase.MergeCustomers(MergeCustomerParam{
+		Survivor_id: 1,
+		Victim_ids:  []uint{2},
+		Fields:      map[string]uint{"first_name": 2, "email": 2, "avatar": 2},
+	}, "admin1", entity.AuditLog{Request_id: "req-1", Client_ip: "10.0.0.1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), merged.ID)
+	assert.Equal(t, "John", merged.First_name)
+	assert.Equal(t, "Doe", merged.Last_name)
+	assert.Equal(t, "john.doe@example.com", merged.Email)
+	assert.Equal(t, "john.jpg", merged.Avatar)
+}
+
+func TestMergeCustomers_InvalidParam(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+	}
+
+	tests := []struct {
+		req MergeCustomerParam
+		err error
+	}{
+		{MergeCustomerParam{Survivor_id: 1, Victim_ids: []uint{1}}, ErrMergeSurvivorIsVictim},
+		{MergeCustomerParam{Survivor_id: 1, Victim_ids: []uint{2, 2}}, ErrMergeDuplicateVictim},
+		{MergeCustomerParam{Survivor_id: 1, Victim_ids: []uint{2}, Fields: map[string]uint{"id": 2}}, ErrMergeUnknownField},
+		{MergeCustomerParam{Survivor_id: 1, Victim_ids: []uint{2}, Fields: map[string]uint{"email": 3}}, ErrMergeFieldSource},
+	}
+	for _, tt := range tests {
+		_, err := useCase.MergeCustomers(tt.req, "admin1", entity.AuditLog{})
+		assert.ErrorIs(t, err, tt.err)
+	}
+	mockRepo.AssertNotCalled(t, "MergeCustomers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListTrash(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+	}
+
+	customers := []entity.Customer{{ID: 3, First_name: "Jim"}}
+	mockRepo.On("ListDeletedCustomers", 20, 0).Return(customers, int64(1), nil)
+
+	result, err := useCase.ListTrash(TrashListParam{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, customers, result.Customers)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, 20, result.Limit)
+}
+
+func TestRestoreCustomer_EmailTaken(t *testing.T) {
+
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+
+	useCase := useCaseCustomer{
+		customerRepo: mockRepo,
+	}
+
+	trashed := entity.Customer{ID: 3, Email: "jim@example.com"}
+	mockRepo.On("RestoreCustomer", uint(3)).Return(trashed, apperror.Conflict("customer already exists", nil))
+	mockRepo.On("GetCustomerByEmail", "jim@example.com").Return(entity.Customer{ID: 8, Email: "jim@example.com"}, nil)
+
+	_, err := useCase.RestoreCustomer(3)
+
+	var appErr *apperror.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, ExistingCustomer{ID: 8, Email: "jim@example.com", Href: "/customer/8"}, appErr.Details)
+}
@@ -0,0 +1,164 @@
+package customers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+)
+
+var ErrCustomerNotYetCreated = apperror.NotFound("customer did not exist yet at as_of")
+
+// HistoryPage one page of the versions of a customer
+type HistoryPage struct {
+	Entries []HistoryEntry
+	Total   int64
+	Page    int
+	Limit   int
+}
+
+// GetCustomerAsOf customer as it was at at, rebuilt from the version in
+// effect then. Tags and custom fields are not versioned and left out
+func (uc useCaseCustomer) GetCustomerAsOf(id uint, at time.Time) (entity.Customer, error) {
+	customer, err := uc.GetCustomerById(id)
+	if err != nil {
+		return customer, err
+	}
+	if at.Before(customer.CreatedAt) {
+		return entity.Customer{}, ErrCustomerNotYetCreated
+	}
+	if !at.Before(customer.UpdatedAt) {
+		return customer, nil
+	}
+
+	version, err := uc.customerRepo.GetCustomerVersionAt(customer.ID, at)
+	if errors.Is(err, apperror.ErrNotFound) {
+		// before the first version was recorded, which holds the state
+		// the customer had since its last unrecorded change
+		version, err = uc.customerRepo.GetCustomerVersion(customer.ID, 1)
+		if errors.Is(err, apperror.ErrNotFound) {
+			// every write to a versioned field records a version, without
+			// any the customer still has the fields it was created with
+			return customer, nil
+		}
+	}
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	customer.First_name = version.First_name
+	customer.Last_name = version.Last_name
+	customer.Email = version.Email
+	customer.Avatar = version.Avatar
+	customer.Account_id = version.Account_id
+	customer.UpdatedAt = version.CreatedAt
+	customer.Tags = nil
+	customer.Custom_fields = nil
+	return customer, nil
+}
+
+// GetCustomerHistory page of the versions of a customer, the latest first,
+// each with the fields it changed
+func (uc useCaseCustomer) GetCustomerHistory(id uint, req HistoryParam) (HistoryPage, error) {
+	customer, err := uc.GetCustomerById(id)
+	if err != nil {
+		return HistoryPage{}, err
+	}
+
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	// one more than the page holds, the version the last entry changed
+	versions, total, err := uc.customerRepo.ListCustomerVersions(customer.ID, limit+1, (page-1)*limit)
+	if err != nil {
+		return HistoryPage{}, err
+	}
+	entries := []HistoryEntry{}
+	for i := 0; i < len(versions) && i < limit; i++ {
+		var previous *entity.CustomerVersion
+		if i+1 < len(versions) {
+			previous = &versions[i+1]
+		}
+		entries = append(entries, historyEntry(versions[i], previous))
+	}
+	return HistoryPage{
+		Entries: entries,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// RevertCustomer set the versioned fields of a customer back to those of
+// version, recorded as a new version
+func (uc useCaseCustomer) RevertCustomer(id uint, version uint) (entity.Customer, error) {
+	target, err := uc.customerRepo.GetCustomerVersion(id, version)
+	if err != nil {
+		return entity.Customer{}, err
+	}
+	if target.Email != "" {
+		err = uc.checkEmailAvailable(target.Email, id)
+		if err != nil {
+			return entity.Customer{}, err
+		}
+	}
+	if target.Account_id != nil {
+		_, err = uc.accountRepo.GetAccountById(*target.Account_id)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return entity.Customer{}, ErrUnknownAccount
+		}
+		if err != nil {
+			return entity.Customer{}, err
+		}
+	}
+
+	err = uc.customerRepo.RevertCustomer(id, version)
+	if err != nil {
+		return entity.Customer{}, uc.explainConflict(err, target.Email, id)
+	}
+	return uc.GetCustomerById(id)
+}
+
+func historyEntry(version entity.CustomerVersion, previous *entity.CustomerVersion) HistoryEntry {
+	entry := HistoryEntry{
+		Version:       version.Version,
+		Changed_at:    version.CreatedAt,
+		Reverted_from: version.Reverted_from,
+		Values:        customerValues(version),
+		Changes:       []FieldChange{},
+	}
+	if previous == nil {
+		return entry
+	}
+	before := customerValues(*previous)
+	for _, change := range []FieldChange{
+		{Field: "first_name", From: before.First_name, To: entry.Values.First_name},
+		{Field: "last_name", From: before.Last_name, To: entry.Values.Last_name},
+		{Field: "email", From: before.Email, To: entry.Values.Email},
+		{Field: "avatar", From: before.Avatar, To: entry.Values.Avatar},
+		{Field: "account_id", From: accountValue(before.Account_id), To: accountValue(entry.Values.Account_id)},
+	} {
+		if change.From != change.To {
+			entry.Changes = append(entry.Changes, change)
+		}
+	}
+	return entry
+}
+
+func customerValues(version entity.CustomerVersion) CustomerValues {
+	return CustomerValues{
+		First_name: version.First_name,
+		Last_name:  version.Last_name,
+		Email:      version.Email,
+		Avatar:     version.Avatar,
+		Account_id: version.Account_id,
+	}
+}
+
+// accountValue comparable account id, nil when there is none
+func accountValue(id *uint) any {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
@@ -0,0 +1,107 @@
+package customers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var versionsDay = time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC)
+
+func TestGetCustomerAsOf(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo}
+
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{
+		ID: 1, First_name: "John", Email: "john@example.com", CreatedAt: versionsDay, UpdatedAt: versionsDay.AddDate(0, 0, 20),
+		Tags: []entity.Tag{{ID: 3, Name: "vip"}},
+	}, nil)
+	at := versionsDay.AddDate(0, 0, 10)
+	mockRepo.On("GetCustomerVersionAt", uint(1), at).Return(entity.CustomerVersion{
+		Customer_id: 1, Version: 2, First_name: "Jon", Email: "jon@example.com", CreatedAt: versionsDay.AddDate(0, 0, 5),
+	}, nil)
+
+	customer, err := useCase.GetCustomerAsOf(1, at)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Jon", customer.First_name)
+	assert.Equal(t, "jon@example.com", customer.Email)
+	assert.Equal(t, versionsDay.AddDate(0, 0, 5), customer.UpdatedAt)
+	assert.Nil(t, customer.Tags)
+}
+
+func TestGetCustomerAsOf_BeforeFirstVersionAndCreation(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo}
+
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{
+		ID: 1, First_name: "John", CreatedAt: versionsDay, UpdatedAt: versionsDay.AddDate(0, 0, 20),
+	}, nil)
+	at := versionsDay.Add(time.Hour)
+	mockRepo.On("GetCustomerVersionAt", uint(1), at).Return(entity.CustomerVersion{}, apperror.NotFound("customer version not found"))
+	mockRepo.On("GetCustomerVersion", uint(1), uint(1)).Return(entity.CustomerVersion{Version: 1, First_name: "Jon"}, nil)
+
+	customer, err := useCase.GetCustomerAsOf(1, at)
+	require.NoError(t, err)
+	assert.Equal(t, "Jon", customer.First_name)
+
+	_, err = useCase.GetCustomerAsOf(1, versionsDay.Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrCustomerNotYetCreated)
+
+	customer, err = useCase.GetCustomerAsOf(1, versionsDay.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "John", customer.First_name)
+}
+
+func TestGetCustomerHistory(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo}
+
+	account := uint(4)
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1}, nil)
+	mockRepo.On("ListCustomerVersions", uint(1), 3, 0).Return([]entity.CustomerVersion{
+		{Version: 3, First_name: "John", Last_name: "Doe", Account_id: &account},
+		{Version: 2, First_name: "Jon", Last_name: "Doe", Account_id: &account},
+		{Version: 1, First_name: "Jon", Last_name: "Doe"},
+	}, int64(3), nil)
+
+	page, err := useCase.GetCustomerHistory(1, HistoryParam{Limit: 2})
+
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	assert.Equal(t, []FieldChange{{Field: "first_name", From: "Jon", To: "John"}}, page.Entries[0].Changes)
+	assert.Equal(t, []FieldChange{{Field: "account_id", From: nil, To: uint(4)}}, page.Entries[1].Changes)
+}
+
+func TestRevertCustomer(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo}
+
+	mockRepo.On("GetCustomerVersion", uint(1), uint(2)).Return(entity.CustomerVersion{Version: 2, Email: "jon@example.com"}, nil)
+	mockRepo.On("GetCustomerByEmail", "jon@example.com").Return(entity.Customer{ID: 1}, nil)
+	mockRepo.On("RevertCustomer", uint(1), uint(2)).Return(nil)
+	mockRepo.On("GetCustomerById", uint(1)).Return(entity.Customer{ID: 1, Email: "jon@example.com"}, nil)
+
+	customer, err := useCase.RevertCustomer(1, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, "jon@example.com", customer.Email)
+}
+
+func TestRevertCustomer_EmailTaken(t *testing.T) {
+	mockRepo := mocks.NewCustomerInterfaceRepo(t)
+	useCase := useCaseCustomer{customerRepo: mockRepo}
+
+	mockRepo.On("GetCustomerVersion", uint(1), uint(2)).Return(entity.CustomerVersion{Version: 2, Email: "jon@example.com"}, nil)
+	mockRepo.On("GetCustomerByEmail", "jon@example.com").Return(entity.Customer{ID: 7, Email: "jon@example.com"}, nil)
+
+	_, err := useCase.RevertCustomer(1, 2)
+
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	mockRepo.AssertNotCalled(t, "RevertCustomer", uint(1), uint(2))
+}
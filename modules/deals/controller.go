@@ -0,0 +1,152 @@
+package deals
+
+import (
+	"net/url"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+type ControllerDeal interface {
+	CreateDeal(req DealParam, actorName string) (FindDeal, error)
+	GetDealById(id uint) (FindDeal, error)
+	ListDeals(req DealListParam, requestUrl url.URL) (ListDeal, error)
+	UpdateDeal(req UpdateDealParam, id uint) (FindDeal, error)
+	DeleteDeal(id uint) (dto.ResponseMeta, error)
+	MoveDeal(id uint, req StageParam, actorName string) (FindDeal, error)
+	ListStageChanges(id uint) (ListStageChange, error)
+	ListStages() ListPipelineStage
+	PipelineSummary(req PipelineSummaryParam) (FindPipelineSummary, error)
+}
+
+type controllerDeal struct {
+	dealUseCase UseCaseDeal
+}
+
+func (uc controllerDeal) CreateDeal(req DealParam, actorName string) (FindDeal, error) {
+	deal, err := uc.dealUseCase.CreateDeal(req, actorName)
+	if err != nil {
+		return FindDeal{}, err
+	}
+	return findDeal("Success create deal", deal), nil
+}
+
+func (uc controllerDeal) GetDealById(id uint) (FindDeal, error) {
+	deal, err := uc.dealUseCase.GetDealById(id)
+	if err != nil {
+		return FindDeal{}, err
+	}
+	return findDeal("Success get deal", deal), nil
+}
+
+func (uc controllerDeal) ListDeals(req DealListParam, requestUrl url.URL) (ListDeal, error) {
+	page, err := uc.dealUseCase.ListDeals(req)
+	if err != nil {
+		return ListDeal{}, err
+	}
+	res := ListDeal{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get deals",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Deals,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Deal{}
+	}
+	return res, nil
+}
+
+func (uc controllerDeal) UpdateDeal(req UpdateDealParam, id uint) (FindDeal, error) {
+	deal, err := uc.dealUseCase.UpdateDeal(req, id)
+	if err != nil {
+		return FindDeal{}, err
+	}
+	return findDeal("Success update deal", deal), nil
+}
+
+func (uc controllerDeal) DeleteDeal(id uint) (dto.ResponseMeta, error) {
+	err := uc.dealUseCase.DeleteDeal(id)
+	if err != nil {
+		return dto.ResponseMeta{}, err
+	}
+	return dto.ResponseMeta{
+		Success:      true,
+		MessageTitle: "Success delete deal",
+		Message:      "Success",
+		ResponseTime: "",
+	}, nil
+}
+
+func (uc controllerDeal) MoveDeal(id uint, req StageParam, actorName string) (FindDeal, error) {
+	deal, err := uc.dealUseCase.MoveDeal(id, req, actorName)
+	if err != nil {
+		return FindDeal{}, err
+	}
+	return findDeal("Success move deal", deal), nil
+}
+
+func (uc controllerDeal) ListStageChanges(id uint) (ListStageChange, error) {
+	changes, err := uc.dealUseCase.ListStageChanges(id)
+	if err != nil {
+		return ListStageChange{}, err
+	}
+	res := ListStageChange{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get deal history",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: changes,
+	}
+	if res.Data == nil {
+		res.Data = []entity.DealStageChange{}
+	}
+	return res, nil
+}
+
+func (uc controllerDeal) ListStages() ListPipelineStage {
+	return ListPipelineStage{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get pipeline stages",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: uc.dealUseCase.ListStages(),
+	}
+}
+
+func (uc controllerDeal) PipelineSummary(req PipelineSummaryParam) (FindPipelineSummary, error) {
+	summary, err := uc.dealUseCase.PipelineSummary(req)
+	if err != nil {
+		return FindPipelineSummary{}, err
+	}
+	return FindPipelineSummary{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get pipeline summary",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: summary,
+	}, nil
+}
+
+func findDeal(title string, deal entity.Deal) FindDeal {
+	return FindDeal{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: title,
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: deal,
+	}
+}
@@ -0,0 +1,116 @@
+package deals
+
+import (
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+// DealParam Stage defaults to the first stage of the pipeline and Owner_id to
+// the acting actor, Currency is an ISO 4217 code such as USD and
+// Expected_close_date is YYYY-MM-DD
+type DealParam struct {
+	Title               string  `json:"title" binding:"required,max=255"`
+	Customer_id         uint    `json:"customer_id" binding:"required"`
+	Owner_id            *uint   `json:"owner_id" binding:"omitempty,min=1"`
+	Stage               string  `json:"stage" binding:"max=64"`
+	Value               float64 `json:"value" binding:"min=0,max=9999999999999"`
+	Currency            string  `json:"currency" binding:"required,iso4217"`
+	Expected_close_date string  `json:"expected_close_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// UpdateDealParam the stage only moves with a StageParam, a missing Owner_id
+// keeps the owner
+type UpdateDealParam struct {
+	Title               string  `json:"title" binding:"required,max=255"`
+	Customer_id         uint    `json:"customer_id" binding:"required"`
+	Owner_id            *uint   `json:"owner_id" binding:"omitempty,min=1"`
+	Value               float64 `json:"value" binding:"min=0,max=9999999999999"`
+	Currency            string  `json:"currency" binding:"required,iso4217"`
+	Expected_close_date string  `json:"expected_close_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type DealListParam struct {
+	Page     int    `form:"page" binding:"min=0"`
+	Limit    int    `form:"limit" binding:"min=0"`
+	Stage    string `form:"stage" binding:"max=64"`
+	Owner    uint   `form:"owner"`
+	Customer uint   `form:"customer"`
+}
+
+// StageParam stage to move a deal to, one of the next stages of its stage
+type StageParam struct {
+	Stage string `json:"stage" binding:"required,max=64"`
+}
+
+// PipelineSummaryParam Close_from and Close_to limit the summary to deals
+// expected to close within those days, both included
+type PipelineSummaryParam struct {
+	Owner      uint   `form:"owner"`
+	Close_from string `form:"close_from" binding:"omitempty,datetime=2006-01-02"`
+	Close_to   string `form:"close_to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// StageMove details of a refused stage move
+type StageMove struct {
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Allowed []string `json:"allowed"`
+}
+
+// PipelineStage configured stage, Closed when no deal moves on from it
+type PipelineStage struct {
+	Name        string   `json:"name"`
+	Probability int      `json:"probability"`
+	Next        []string `json:"next"`
+	Closed      bool     `json:"closed"`
+}
+
+// CurrencyTotal deals in one currency, Weighted_value is the value times the
+// probability of the stage each deal is in
+type CurrencyTotal struct {
+	Currency       string  `json:"currency"`
+	Count          int64   `json:"count"`
+	Value          float64 `json:"value"`
+	Weighted_value float64 `json:"weighted_value"`
+}
+
+// StageSummary deals in one stage, values of different currencies are never
+// added up
+type StageSummary struct {
+	Stage       string          `json:"stage"`
+	Probability int             `json:"probability"`
+	Count       int64           `json:"count"`
+	Totals      []CurrencyTotal `json:"totals"`
+}
+
+// PipelineSummary Stages in pipeline order followed by stages no longer
+// configured that still have deals, Totals adds up every stage
+type PipelineSummary struct {
+	Stages []StageSummary  `json:"stages"`
+	Totals []CurrencyTotal `json:"totals"`
+}
+
+type FindDeal struct {
+	dto.ResponseMeta
+	Data entity.Deal `json:"data"`
+}
+
+type ListDeal struct {
+	dto.ListResponseMeta
+	Data []entity.Deal `json:"data"`
+}
+
+type ListStageChange struct {
+	dto.ResponseMeta
+	Data []entity.DealStageChange `json:"data"`
+}
+
+type ListPipelineStage struct {
+	dto.ResponseMeta
+	Data []PipelineStage `json:"data"`
+}
+
+type FindPipelineSummary struct {
+	dto.ResponseMeta
+	Data PipelineSummary `json:"data"`
+}
@@ -0,0 +1,163 @@
+package deals
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RequestHandlerDeal struct {
+	ctr ControllerDeal
+}
+
+func NewDealRequestHandler(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+) RequestHandlerDeal {
+	return RequestHandlerDeal{
+		ctr: controllerDeal{
+			dealUseCase: useCaseDeal{
+				dealRepo:     repository.NewDeal(dbCrud),
+				customerRepo: repository.NewCustomer(dbCrud),
+				actorRepo:    repository.NewActor(dbCrud),
+				pipeline:     cfg.Pipeline,
+			},
+		}}
+}
+
+func (h RequestHandlerDeal) CreateDeal(c *gin.Context) {
+	request := DealParam{}
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.CreateDeal(request, c.GetString("Username"))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerDeal) GetDealById(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.GetDealById(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerDeal) ListDeals(c *gin.Context) {
+	request := DealListParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.ListDeals(request, *c.Request.URL)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerDeal) UpdateDeal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := UpdateDealParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.UpdateDeal(request, uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerDeal) DeleteDeal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.DeleteDeal(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerDeal) MoveDeal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	request := StageParam{}
+	err = c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.MoveDeal(uint(id), request, c.GetString("Username"))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerDeal) ListStageChanges(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.DefaultBadRequestResponse())
+		return
+	}
+	res, err := h.ctr.ListStageChanges(uint(id))
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerDeal) ListStages(c *gin.Context) {
+	c.JSON(http.StatusOK, h.ctr.ListStages())
+}
+
+func (h RequestHandlerDeal) PipelineSummary(c *gin.Context) {
+	request := PipelineSummaryParam{}
+	err := c.ShouldBindQuery(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.PipelineSummary(request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,82 @@
+package deals
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RouteDeal struct {
+	DealRequestHandeler RequestHandlerDeal
+	Authentication      middleware.Authentication
+	Authorization       middleware.Authorization
+	Audit               middleware.Audit
+}
+
+func NewRouter(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+) RouteDeal {
+	return RouteDeal{
+		DealRequestHandeler: NewDealRequestHandler(
+			dbCrud,
+			cfg,
+		),
+		Authentication: middleware.NewAuthentication(dbCrud, issuer),
+		Authorization:  middleware.NewAuthorization(dbCrud),
+		Audit:          middleware.NewAudit(dbCrud),
+	}
+}
+
+func (r RouteDeal) Handle(routeVersion *gin.Engine) {
+	basepath := "/deal"
+	deal := routeVersion.Group(basepath, r.Authentication.Auth)
+
+	deal.GET("",
+		r.Authorization.RequirePermission(middleware.PermissionDealRead),
+		r.DealRequestHandeler.ListDeals,
+	)
+	deal.POST("",
+		r.Authorization.RequirePermission(middleware.PermissionDealCreate),
+		r.Audit.Record(entity.AuditActionCreate, "deal", ""),
+		r.DealRequestHandeler.CreateDeal,
+	)
+	deal.GET("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionDealRead),
+		r.DealRequestHandeler.GetDealById,
+	)
+	deal.PUT("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionDealUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "deal", "id"),
+		r.DealRequestHandeler.UpdateDeal,
+	)
+	deal.DELETE("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionDealDelete),
+		r.Audit.Record(entity.AuditActionDelete, "deal", "id"),
+		r.DealRequestHandeler.DeleteDeal,
+	)
+	deal.POST("/:id/stage",
+		r.Authorization.RequirePermission(middleware.PermissionDealUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "deal", "id"),
+		r.DealRequestHandeler.MoveDeal,
+	)
+	deal.GET("/:id/history",
+		r.Authorization.RequirePermission(middleware.PermissionDealRead),
+		r.DealRequestHandeler.ListStageChanges,
+	)
+
+	pipeline := routeVersion.Group("/pipeline", r.Authentication.Auth)
+
+	pipeline.GET("/stages",
+		r.Authorization.RequirePermission(middleware.PermissionDealRead),
+		r.DealRequestHandeler.ListStages,
+	)
+	pipeline.GET("/summary",
+		r.Authorization.RequirePermission(middleware.PermissionDealRead),
+		r.DealRequestHandeler.PipelineSummary,
+	)
+}
@@ -0,0 +1,336 @@
+package deals
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/config"
+)
+
+type UseCaseDeal interface {
+	CreateDeal(req DealParam, actorName string) (entity.Deal, error)
+	GetDealById(id uint) (entity.Deal, error)
+	ListDeals(req DealListParam) (DealPage, error)
+	UpdateDeal(req UpdateDealParam, id uint) (entity.Deal, error)
+	DeleteDeal(id uint) error
+	MoveDeal(id uint, req StageParam, actorName string) (entity.Deal, error)
+	ListStageChanges(id uint) ([]entity.DealStageChange, error)
+	ListStages() []PipelineStage
+	PipelineSummary(req PipelineSummaryParam) (PipelineSummary, error)
+}
+
+var (
+	ErrDealTitleRequired = apperror.Validation("deal title must not be blank", nil)
+	ErrUnknownStage      = apperror.Validation("stage is not a stage of the pipeline", nil)
+	ErrUnknownCustomer   = apperror.Validation("customer_id does not match a customer", nil)
+	ErrUnknownOwner      = apperror.Validation("owner_id does not match an actor", nil)
+	ErrInvalidCloseRange = apperror.Validation("close_from must not be after close_to", nil)
+)
+
+// DealPage one page of deals
+type DealPage struct {
+	Deals []entity.Deal
+	Total int64
+	Page  int
+	Limit int
+}
+
+type useCaseDeal struct {
+	dealRepo     repository.DealInterfaceRepo
+	customerRepo repository.CustomerInterfaceRepo
+	actorRepo    repository.ActorInterfaceRepo
+	pipeline     config.Pipeline
+}
+
+// CreateDeal the deal starts in req.Stage, or the first stage of the
+// pipeline, owned by req.Owner_id or the acting actor
+func (uc useCaseDeal) CreateDeal(req DealParam, actorName string) (entity.Deal, error) {
+	actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return entity.Deal{}, err
+	}
+	now := time.Now()
+	deal := entity.Deal{
+		Title:            strings.TrimSpace(req.Title),
+		Stage:            req.Stage,
+		Value:            req.Value,
+		Currency:         req.Currency,
+		Stage_changed_at: now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if deal.Title == "" {
+		return deal, ErrDealTitleRequired
+	}
+	if deal.Stage == "" {
+		deal.Stage = uc.pipeline.Stages[0].Name
+	}
+	if _, ok := uc.pipeline.Stage(deal.Stage); !ok {
+		return deal, ErrUnknownStage
+	}
+	deal.Expected_close_date = parseDay(req.Expected_close_date)
+	deal.Customer_id, err = uc.dealCustomer(req.Customer_id)
+	if err != nil {
+		return deal, err
+	}
+	deal.Owner_id = &actor.ID
+	if req.Owner_id != nil {
+		deal.Owner_id, err = uc.dealOwner(*req.Owner_id)
+		if err != nil {
+			return deal, err
+		}
+	}
+
+	err = uc.dealRepo.CreateDeal(&deal, &entity.DealStageChange{
+		To_stage:   deal.Stage,
+		Actor_id:   &actor.ID,
+		Changed_at: now,
+	})
+	return deal, err
+}
+
+func (uc useCaseDeal) GetDealById(id uint) (entity.Deal, error) {
+	return uc.dealRepo.GetDealById(id)
+}
+
+// ListDeals page of deals, most recently created first
+func (uc useCaseDeal) ListDeals(req DealListParam) (DealPage, error) {
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	deals, total, err := uc.dealRepo.ListDeals(repository.DealFilter{
+		Stage:       req.Stage,
+		Owner_id:    req.Owner,
+		Customer_id: req.Customer,
+		Limit:       limit,
+		Offset:      (page - 1) * limit,
+	})
+	if err != nil {
+		return DealPage{}, err
+	}
+	return DealPage{
+		Deals: deals,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+// UpdateDeal everything but the stage, see MoveDeal
+func (uc useCaseDeal) UpdateDeal(req UpdateDealParam, id uint) (entity.Deal, error) {
+	deal, err := uc.dealRepo.GetDealById(id)
+	if err != nil {
+		return deal, err
+	}
+	deal.Title = strings.TrimSpace(req.Title)
+	deal.Value = req.Value
+	deal.Currency = req.Currency
+	deal.Expected_close_date = parseDay(req.Expected_close_date)
+	deal.UpdatedAt = time.Now()
+	if deal.Title == "" {
+		return deal, ErrDealTitleRequired
+	}
+	deal.Customer_id, err = uc.dealCustomer(req.Customer_id)
+	if err != nil {
+		return deal, err
+	}
+	if req.Owner_id != nil {
+		deal.Owner_id, err = uc.dealOwner(*req.Owner_id)
+		if err != nil {
+			return deal, err
+		}
+	}
+
+	err = uc.dealRepo.UpdateDeal(&deal, id)
+	if err != nil {
+		return deal, err
+	}
+	return uc.dealRepo.GetDealById(id)
+}
+
+// DeleteDeal its stage changes go with it
+func (uc useCaseDeal) DeleteDeal(id uint) error {
+	return uc.dealRepo.DeleteDeal(id)
+}
+
+// MoveDeal move the deal to one of the next stages of its stage, recording
+// who moved it and when
+func (uc useCaseDeal) MoveDeal(id uint, req StageParam, actorName string) (entity.Deal, error) {
+	actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return entity.Deal{}, err
+	}
+	deal, err := uc.dealRepo.GetDealById(id)
+	if err != nil {
+		return deal, err
+	}
+	if _, ok := uc.pipeline.Stage(req.Stage); !ok {
+		return deal, ErrUnknownStage
+	}
+	if !uc.pipeline.CanMove(deal.Stage, req.Stage) {
+		current, _ := uc.pipeline.Stage(deal.Stage)
+		allowed := current.Next
+		if allowed == nil {
+			allowed = []string{}
+		}
+		return deal, apperror.Validation("deal cannot move from "+deal.Stage+" to "+req.Stage, StageMove{
+			From:    deal.Stage,
+			To:      req.Stage,
+			Allowed: allowed,
+		})
+	}
+
+	from := deal.Stage
+	err = uc.dealRepo.MoveDealStage(id, from, &entity.DealStageChange{
+		From_stage: &from,
+		To_stage:   req.Stage,
+		Actor_id:   &actor.ID,
+		Changed_at: time.Now(),
+	})
+	if err != nil {
+		return deal, err
+	}
+	return uc.dealRepo.GetDealById(id)
+}
+
+// ListStageChanges every stage the deal went through, oldest first
+func (uc useCaseDeal) ListStageChanges(id uint) ([]entity.DealStageChange, error) {
+	_, err := uc.dealRepo.GetDealById(id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.dealRepo.ListStageChanges(id)
+}
+
+// ListStages stages of the pipeline in order
+func (uc useCaseDeal) ListStages() []PipelineStage {
+	stages := make([]PipelineStage, len(uc.pipeline.Stages))
+	for i, stage := range uc.pipeline.Stages {
+		next := stage.Next
+		if next == nil {
+			next = []string{}
+		}
+		stages[i] = PipelineStage{
+			Name:        stage.Name,
+			Probability: stage.Probability,
+			Next:        next,
+			Closed:      len(stage.Next) == 0,
+		}
+	}
+	return stages
+}
+
+// PipelineSummary count, value and value weighted by the probability of the
+// stage, per stage and currency. Every configured stage is listed, deals in
+// a stage that is no longer configured weigh nothing. Totals are in currency
+// order
+func (uc useCaseDeal) PipelineSummary(req PipelineSummaryParam) (PipelineSummary, error) {
+	filter := repository.PipelineFilter{
+		Owner_id:  req.Owner,
+		CloseFrom: parseDay(req.Close_from),
+	}
+	if closeTo := parseDay(req.Close_to); closeTo != nil {
+		if filter.CloseFrom != nil && filter.CloseFrom.After(*closeTo) {
+			return PipelineSummary{}, ErrInvalidCloseRange
+		}
+		closeBefore := closeTo.AddDate(0, 0, 1)
+		filter.CloseBefore = &closeBefore
+	}
+	totals, err := uc.dealRepo.PipelineSummary(filter)
+	if err != nil {
+		return PipelineSummary{}, err
+	}
+
+	summary := PipelineSummary{Totals: []CurrencyTotal{}}
+	position := make(map[string]int, len(uc.pipeline.Stages))
+	for _, stage := range uc.pipeline.Stages {
+		position[stage.Name] = len(summary.Stages)
+		summary.Stages = append(summary.Stages, StageSummary{
+			Stage:       stage.Name,
+			Probability: stage.Probability,
+			Totals:      []CurrencyTotal{},
+		})
+	}
+	overall := map[string]int{}
+	for _, total := range totals {
+		i, ok := position[total.Stage]
+		if !ok {
+			i = len(summary.Stages)
+			position[total.Stage] = i
+			summary.Stages = append(summary.Stages, StageSummary{Stage: total.Stage, Totals: []CurrencyTotal{}})
+		}
+		stage := &summary.Stages[i]
+		weighted := roundCents(total.Value * float64(stage.Probability) / 100)
+		stage.Count += total.Count
+		stage.Totals = append(stage.Totals, CurrencyTotal{
+			Currency:       total.Currency,
+			Count:          total.Count,
+			Value:          roundCents(total.Value),
+			Weighted_value: weighted,
+		})
+
+		j, ok := overall[total.Currency]
+		if !ok {
+			j = len(summary.Totals)
+			overall[total.Currency] = j
+			summary.Totals = append(summary.Totals, CurrencyTotal{Currency: total.Currency})
+		}
+		summary.Totals[j].Count += total.Count
+		summary.Totals[j].Value = roundCents(summary.Totals[j].Value + total.Value)
+		summary.Totals[j].Weighted_value = roundCents(summary.Totals[j].Weighted_value + weighted)
+	}
+	sort.Slice(summary.Totals, func(i, j int) bool {
+		return summary.Totals[i].Currency < summary.Totals[j].Currency
+	})
+	return summary, nil
+}
+
+// dealCustomer id of the customer a deal is for, a merged customer resolves
+// to the survivor
+func (uc useCaseDeal) dealCustomer(id uint) (uint, error) {
+	customer, err := uc.customerRepo.GetCustomerById(id)
+	if errors.Is(err, apperror.ErrNotFound) {
+		redirect, redirectErr := uc.customerRepo.GetCustomerRedirect(id)
+		if redirectErr != nil {
+			return 0, ErrUnknownCustomer
+		}
+		return redirect.Survivor_id, nil
+	}
+	return customer.ID, err
+}
+
+// dealOwner id of an actor that may own a deal
+func (uc useCaseDeal) dealOwner(id uint) (*uint, error) {
+	owner, err := uc.actorRepo.GetActorById(id)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, ErrUnknownOwner
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &owner.ID, nil
+}
+
+// parseDay midnight UTC of a YYYY-MM-DD day already checked by binding, nil
+// for an empty value
+func parseDay(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil
+	}
+	return &day
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,213 @@
+package deals
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateDeal(t *testing.T) {
+
+	mockRepo := mocks.NewDealInterfaceRepo(t)
+	mockCustomerRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseDeal{
+		dealRepo:     mockRepo,
+		customerRepo: mockCustomerRepo,
+		actorRepo:    mockActorRepo,
+		pipeline:     config.Default().Pipeline,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockCustomerRepo.On("GetCustomerById", uint(3)).Return(entity.Customer{ID: 3}, nil)
+	mockRepo.On("CreateDeal", mock.MatchedBy(func(deal *entity.Deal) bool {
+		return deal.Stage == "lead" && deal.Currency == "USD" && *deal.Owner_id == 5 &&
+			deal.Expected_close_date.Format("2006-01-02") == "2026-12-31"
+	}), mock.MatchedBy(func(change *entity.DealStageChange) bool {
+		return change.From_stage == nil && change.To_stage == "lead" && *change.Actor_id == 5
+	})).Return(nil)
+
+	deal, err := useCase.CreateDeal(DealParam{
+		Title:               "Licenses",
+		Customer_id:         3,
+		Value:               1200,
+		Currency:            "USD",
+		Expected_close_date: "2026-12-31",
+	}, "admin1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), deal.Customer_id)
+}
+
+func TestCreateDeal_MergedCustomer(t *testing.T) {
+
+	mockRepo := mocks.NewDealInterfaceRepo(t)
+	mockCustomerRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseDeal{
+		dealRepo:     mockRepo,
+		customerRepo: mockCustomerRepo,
+		actorRepo:    mockActorRepo,
+		pipeline:     config.Default().Pipeline,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockCustomerRepo.On("GetCustomerById", uint(3)).Return(entity.Customer{}, apperror.NotFound("customer not found"))
+	mockCustomerRepo.On("GetCustomerRedirect", uint(3)).Return(entity.CustomerRedirect{Old_id: 3, Survivor_id: 8}, nil)
+	mockRepo.On("CreateDeal", mock.Anything, mock.Anything).Return(nil)
+
+	deal, err := useCase.CreateDeal(DealParam{Title: "Licenses", Customer_id: 3, Currency: "USD", Stage: "proposal"}, "admin1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(8), deal.Customer_id)
+	assert.Equal(t, "proposal", deal.Stage)
+}
+
+func TestCreateDeal_UnknownStage(t *testing.T) {
+
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseDeal{
+		actorRepo: mockActorRepo,
+		pipeline:  config.Default().Pipeline,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+
+	_, err := useCase.CreateDeal(DealParam{Title: "Licenses", Customer_id: 3, Currency: "USD", Stage: "closing"}, "admin1")
+
+	assert.ErrorIs(t, err, ErrUnknownStage)
+}
+
+func TestCreateDeal_UnknownOwner(t *testing.T) {
+
+	mockCustomerRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseDeal{
+		customerRepo: mockCustomerRepo,
+		actorRepo:    mockActorRepo,
+		pipeline:     config.Default().Pipeline,
+	}
+
+	owner := uint(9)
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockCustomerRepo.On("GetCustomerById", uint(3)).Return(entity.Customer{ID: 3}, nil)
+	mockActorRepo.On("GetActorById", owner).Return(entity.Actor{}, apperror.NotFound("actor not found"))
+
+	_, err := useCase.CreateDeal(DealParam{Title: "Licenses", Customer_id: 3, Currency: "USD", Owner_id: &owner}, "admin1")
+
+	assert.ErrorIs(t, err, ErrUnknownOwner)
+}
+
+func TestMoveDeal(t *testing.T) {
+
+	mockRepo := mocks.NewDealInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseDeal{
+		dealRepo:  mockRepo,
+		actorRepo: mockActorRepo,
+		pipeline:  config.Default().Pipeline,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockRepo.On("GetDealById", uint(1)).Return(entity.Deal{ID: 1, Stage: "proposal"}, nil).Once()
+	mockRepo.On("MoveDealStage", uint(1), "proposal", mock.MatchedBy(func(change *entity.DealStageChange) bool {
+		return *change.From_stage == "proposal" && change.To_stage == "won" && *change.Actor_id == 5
+	})).Return(nil)
+	mockRepo.On("GetDealById", uint(1)).Return(entity.Deal{ID: 1, Stage: "won"}, nil).Once()
+
+	deal, err := useCase.MoveDeal(1, StageParam{Stage: "won"}, "admin1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "won", deal.Stage)
+}
+
+func TestMoveDeal_NotAllowed(t *testing.T) {
+
+	mockRepo := mocks.NewDealInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseDeal{
+		dealRepo:  mockRepo,
+		actorRepo: mockActorRepo,
+		pipeline:  config.Default().Pipeline,
+	}
+
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockRepo.On("GetDealById", uint(1)).Return(entity.Deal{ID: 1, Stage: "won"}, nil)
+
+	_, err := useCase.MoveDeal(1, StageParam{Stage: "lost"}, "admin1")
+
+	assert.ErrorIs(t, err, apperror.ErrValidation)
+	var appErr *apperror.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, StageMove{From: "won", To: "lost", Allowed: []string{}}, appErr.Details)
+	mockRepo.AssertNotCalled(t, "MoveDealStage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPipelineSummary(t *testing.T) {
+
+	mockRepo := mocks.NewDealInterfaceRepo(t)
+
+	useCase := useCaseDeal{
+		dealRepo: mockRepo,
+		pipeline: config.Pipeline{Stages: []config.Stage{
+			{Name: "open", Probability: 40, Next: []string{"won"}},
+			{Name: "won", Probability: 100},
+		}},
+	}
+
+	mockRepo.On("PipelineSummary", mock.MatchedBy(func(filter repository.PipelineFilter) bool {
+		return filter.Owner_id == 2 && filter.CloseFrom.Format("2006-01-02") == "2026-10-01" &&
+			filter.CloseBefore.Format("2006-01-02") == "2027-01-01"
+	})).Return([]entity.PipelineTotal{
+		{Stage: "archived", Currency: "USD", Count: 1, Value: 10},
+		{Stage: "open", Currency: "EUR", Count: 1, Value: 100},
+		{Stage: "open", Currency: "USD", Count: 2, Value: 250.5},
+		{Stage: "won", Currency: "USD", Count: 1, Value: 1000},
+	}, nil)
+
+	summary, err := useCase.PipelineSummary(PipelineSummaryParam{Owner: 2, Close_from: "2026-10-01", Close_to: "2026-12-31"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []StageSummary{
+		{Stage: "open", Probability: 40, Count: 3, Totals: []CurrencyTotal{
+			{Currency: "EUR", Count: 1, Value: 100, Weighted_value: 40},
+			{Currency: "USD", Count: 2, Value: 250.5, Weighted_value: 100.2},
+		}},
+		{Stage: "won", Probability: 100, Count: 1, Totals: []CurrencyTotal{
+			{Currency: "USD", Count: 1, Value: 1000, Weighted_value: 1000},
+		}},
+		{Stage: "archived", Count: 1, Totals: []CurrencyTotal{
+			{Currency: "USD", Count: 1, Value: 10, Weighted_value: 0},
+		}},
+	}, summary.Stages)
+	assert.Equal(t, []CurrencyTotal{
+		{Currency: "EUR", Count: 1, Value: 100, Weighted_value: 40},
+		{Currency: "USD", Count: 4, Value: 1260.5, Weighted_value: 1100.2},
+	}, summary.Totals)
+}
+
+func TestPipelineSummary_InvalidRange(t *testing.T) {
+
+	mockRepo := mocks.NewDealInterfaceRepo(t)
+
+	useCase := useCaseDeal{
+		dealRepo: mockRepo,
+		pipeline: config.Default().Pipeline,
+	}
+
+	_, err := useCase.PipelineSummary(PipelineSummaryParam{Close_from: "2026-12-31", Close_to: "2026-10-01"})
+
+	assert.ErrorIs(t, err, ErrInvalidCloseRange)
+}
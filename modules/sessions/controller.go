@@ -0,0 +1,66 @@
+package sessions
+
+import (
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/utils/token"
+)
+
+type ControllerSession interface {
+	Refresh(req RefreshParam) (SuccessToken, error)
+	Logout(req RefreshParam) (dto.ResponseMeta, error)
+	Jwks() token.JWKS
+}
+
+type controllerSession struct {
+	sessionUseCase UseCaseSession
+	issuer         token.Issuer
+}
+
+func (uc controllerSession) Refresh(req RefreshParam) (SuccessToken, error) {
+	tokens, err := uc.sessionUseCase.Refresh(req.Refresh_token)
+	if err != nil {
+		return SuccessToken{}, err
+	}
+	res := SuccessToken{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success refresh token",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: NewTokenPair(tokens),
+	}
+	return res, nil
+}
+
+func (uc controllerSession) Logout(req RefreshParam) (dto.ResponseMeta, error) {
+	var res dto.ResponseMeta
+	err := uc.sessionUseCase.Logout(req.Refresh_token)
+	if err != nil {
+		return dto.ResponseMeta{}, err
+	}
+	res.Success = true
+	res.Message = "Success logout"
+	res.MessageTitle = "Logout"
+
+	return res, nil
+}
+
+// Jwks public signing keys, empty while tokens are signed with the HMAC secret
+func (uc controllerSession) Jwks() token.JWKS {
+	return uc.issuer.JWKS()
+}
+
+// NewTokenPair response body for tokens
+func NewTokenPair(tokens Tokens) TokenPair {
+	now := time.Now()
+	return TokenPair{
+		Access_token:       tokens.AccessToken,
+		Token_type:         "Bearer",
+		Expires_in:         int64(tokens.AccessExpiresAt.Sub(now).Seconds()),
+		Refresh_token:      tokens.RefreshToken,
+		Refresh_expires_in: int64(tokens.RefreshExpiresAt.Sub(now).Seconds()),
+	}
+}
@@ -0,0 +1,21 @@
+package sessions
+
+import "github.com/alkamalp/crm-golang/dto"
+
+type RefreshParam struct {
+	Refresh_token string `json:"refresh_token" binding:"required"`
+}
+
+// TokenPair what login and refresh hand to the client, expires_in are seconds
+type TokenPair struct {
+	Access_token       string `json:"access_token"`
+	Token_type         string `json:"token_type"`
+	Expires_in         int64  `json:"expires_in"`
+	Refresh_token      string `json:"refresh_token"`
+	Refresh_expires_in int64  `json:"refresh_expires_in"`
+}
+
+type SuccessToken struct {
+	dto.ResponseMeta
+	Data TokenPair `json:"data"`
+}
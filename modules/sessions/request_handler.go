@@ -0,0 +1,66 @@
+package sessions
+
+import (
+	"net/http"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/alkamalp/crm-golang/utils/validation"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RequestHandlerSession struct {
+	ctr ControllerSession
+}
+
+func NewSessionRequestHandler(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+) RequestHandlerSession {
+	return RequestHandlerSession{
+		ctr: controllerSession{
+			sessionUseCase: NewUseCaseSession(dbCrud, issuer, cfg.JWT),
+			issuer:         issuer,
+		}}
+}
+
+func (h RequestHandlerSession) Refresh(c *gin.Context) {
+	request := RefreshParam{}
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.Refresh(request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.Header("Authorization", res.Data.Access_token)
+	c.JSON(http.StatusOK, res)
+}
+
+func (h RequestHandlerSession) Logout(c *gin.Context) {
+	request := RefreshParam{}
+	err := c.ShouldBind(&request)
+	if err != nil {
+		c.JSON(validation.NewBindErrorResponse(err))
+		return
+	}
+	res, err := h.ctr.Logout(request)
+	if err != nil {
+		c.JSON(dto.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Jwks key set for services verifying CRM tokens offline, cached briefly so
+// a rotated key shows up soon after restart
+func (h RequestHandlerSession) Jwks(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.ctr.Jwks())
+}
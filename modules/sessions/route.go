@@ -0,0 +1,45 @@
+package sessions
+
+import (
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RouteSession struct {
+	SessionRequestHandeler RequestHandlerSession
+}
+
+func NewRouter(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+) RouteSession {
+	return RouteSession{
+		SessionRequestHandeler: NewSessionRequestHandler(
+			dbCrud,
+			cfg,
+			issuer,
+		),
+	}
+}
+
+// Handle session endpoints live next to /actor/login, the refresh token in
+// the body is the credential so no access token is required. The JWKS is
+// public
+func (r RouteSession) Handle(routeVersion *gin.Engine) {
+	basepath := "/actor"
+	session := routeVersion.Group(basepath)
+
+	session.POST("/refresh",
+		r.SessionRequestHandeler.Refresh,
+	)
+	session.POST("/logout",
+		r.SessionRequestHandeler.Logout,
+	)
+
+	routeVersion.GET("/.well-known/jwks.json",
+		r.SessionRequestHandeler.Jwks,
+	)
+}
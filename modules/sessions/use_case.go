@@ -0,0 +1,175 @@
+package sessions
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = apperror.Unauthorized("invalid or expired refresh token")
+	ErrRefreshTokenReused  = apperror.Unauthorized("refresh token was already used, the session has been revoked")
+)
+
+// Tokens access and refresh token of a session with their expiry
+type Tokens struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+type UseCaseSession interface {
+	StartSession(actor entity.Actor) (Tokens, error)
+	Refresh(refreshToken string) (Tokens, error)
+	Logout(refreshToken string) error
+}
+
+type useCaseSession struct {
+	sessionRepo repository.SessionInterfaceRepo
+	actorRepo   repository.ActorInterfaceRepo
+	issuer      token.Issuer
+	refreshTTL  time.Duration
+}
+
+// NewUseCaseSession used by the actor login to open sessions
+func NewUseCaseSession(dbCrud *gorm.DB, issuer token.Issuer, cfg config.JWT) UseCaseSession {
+	return useCaseSession{
+		sessionRepo: repository.NewSession(dbCrud),
+		actorRepo:   repository.NewActor(dbCrud),
+		issuer:      issuer,
+		refreshTTL:  cfg.RefreshTTL,
+	}
+}
+
+// StartSession open a new session for an actor that just logged in
+func (uc useCaseSession) StartSession(actor entity.Actor) (Tokens, error) {
+	sessionId, err := token.NewSessionId()
+	if err != nil {
+		return Tokens{}, err
+	}
+	refreshToken, next, err := uc.newRefreshToken()
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	session := &entity.Session{
+		ID:        sessionId,
+		Actor_id:  actor.ID,
+		CreatedAt: time.Now(),
+	}
+	err = uc.sessionRepo.CreateSession(session, next)
+	if err != nil {
+		return Tokens{}, err
+	}
+	return uc.tokens(actor, sessionId, refreshToken, next.ExpiresAt)
+}
+
+// Refresh exchange a refresh token for a new pair. Presenting a token that
+// was already exchanged means it leaked, so the whole session is revoked
+func (uc useCaseSession) Refresh(refreshToken string) (Tokens, error) {
+	found, err := uc.findRefreshToken(refreshToken)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if found.UsedAt != nil {
+		return Tokens{}, uc.revokeReused(found.Session_id)
+	}
+	if !found.ExpiresAt.After(time.Now()) {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+
+	// role or status may have changed since login
+	actor, err := uc.actorRepo.GetActorById(found.Session.Actor_id)
+	if errors.Is(err, apperror.ErrNotFound) || (err == nil && actor.Active != 1) {
+		return Tokens{}, uc.revoke(found.Session_id, ErrInvalidRefreshToken)
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	rotated, next, err := uc.newRefreshToken()
+	if err != nil {
+		return Tokens{}, err
+	}
+	err = uc.sessionRepo.RotateRefreshToken(&found, next)
+	if errors.Is(err, repository.ErrRefreshTokenUsed) {
+		return Tokens{}, uc.revokeReused(found.Session_id)
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+	return uc.tokens(actor, found.Session_id, rotated, next.ExpiresAt)
+}
+
+// Logout revoke the session the refresh token belongs to, access tokens of
+// that session stop working as well
+func (uc useCaseSession) Logout(refreshToken string) error {
+	found, err := uc.sessionRepo.GetRefreshToken(token.HashRefreshToken(refreshToken))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return err
+	}
+	return uc.sessionRepo.RevokeSession(found.Session_id)
+}
+
+func (uc useCaseSession) findRefreshToken(refreshToken string) (entity.RefreshToken, error) {
+	found, err := uc.sessionRepo.GetRefreshToken(token.HashRefreshToken(refreshToken))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return found, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return found, err
+	}
+	if found.Session.RevokedAt != nil {
+		return found, ErrInvalidRefreshToken
+	}
+	return found, nil
+}
+
+func (uc useCaseSession) newRefreshToken() (string, *entity.RefreshToken, error) {
+	refreshToken, err := token.NewRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+	return refreshToken, &entity.RefreshToken{
+		Token_hash: token.HashRefreshToken(refreshToken),
+		ExpiresAt:  now.Add(uc.refreshTTL),
+		CreatedAt:  now,
+	}, nil
+}
+
+func (uc useCaseSession) tokens(actor entity.Actor, sessionId, refreshToken string, refreshExpiresAt time.Time) (Tokens, error) {
+	accessToken, accessExpiresAt, err := uc.issuer.AccessToken(actor, sessionId)
+	if err != nil {
+		return Tokens{}, err
+	}
+	return Tokens{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func (uc useCaseSession) revokeReused(sessionId string) error {
+	return uc.revoke(sessionId, ErrRefreshTokenReused)
+}
+
+// revoke the session and answer with reason, unless revoking itself failed
+func (uc useCaseSession) revoke(sessionId string, reason error) error {
+	err := uc.sessionRepo.RevokeSession(sessionId)
+	if err != nil {
+		return err
+	}
+	return reason
+}
@@ -0,0 +1,170 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testJWT = config.JWT{
+	Secret:     "test-secret-0123456789",
+	AccessTTL:  time.Minute,
+	RefreshTTL: time.Hour,
+}
+
+func newTestIssuer(t *testing.T) token.Issuer {
+	issuer, err := token.NewIssuer(testJWT)
+	assert.NoError(t, err)
+	return issuer
+}
+
+func newTestUseCase(t *testing.T) (useCaseSession, *mocks.SessionInterfaceRepo, *mocks.ActorInterfaceRepo) {
+	sessionRepo := mocks.NewSessionInterfaceRepo(t)
+	actorRepo := mocks.NewActorInterfaceRepo(t)
+	useCase := useCaseSession{
+		sessionRepo: sessionRepo,
+		actorRepo:   actorRepo,
+		issuer:      newTestIssuer(t),
+		refreshTTL:  testJWT.RefreshTTL,
+	}
+	return useCase, sessionRepo, actorRepo
+}
+
+func storedToken(refreshToken string, usedAt *time.Time) entity.RefreshToken {
+	return entity.RefreshToken{
+		ID:         7,
+		Session_id: "session-1",
+		Token_hash: token.HashRefreshToken(refreshToken),
+		ExpiresAt:  time.Now().Add(time.Hour),
+		UsedAt:     usedAt,
+		Session:    entity.Session{ID: "session-1", Actor_id: 3},
+	}
+}
+
+func TestStartSession(t *testing.T) {
+
+	useCase, sessionRepo, _ := newTestUseCase(t)
+
+	sessionRepo.On("CreateSession",
+		mock.MatchedBy(func(s *entity.Session) bool { return s.Actor_id == 3 && s.ID != "" }),
+		mock.AnythingOfType("*entity.RefreshToken"),
+	).Return(nil)
+
+	tokens, err := useCase.StartSession(entity.Actor{ID: 3, Username: "admin1", Role_id: 2})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	claims, err := newTestIssuer(t).Parse(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin1", claims.Name)
+	assert.Equal(t, uint(2), claims.Role)
+	assert.NotEmpty(t, claims.SessionId)
+
+	// only the hash of the refresh token reaches the database
+	stored := sessionRepo.Calls[0].Arguments.Get(1).(*entity.RefreshToken)
+	assert.Equal(t, token.HashRefreshToken(tokens.RefreshToken), stored.Token_hash)
+}
+
+func TestRefresh(t *testing.T) {
+
+	useCase, sessionRepo, actorRepo := newTestUseCase(t)
+
+	found := storedToken("old-token", nil)
+	sessionRepo.On("GetRefreshToken", found.Token_hash).Return(found, nil)
+	actorRepo.On("GetActorById", uint(3)).Return(entity.Actor{ID: 3, Username: "admin1", Role_id: 2, Active: 1}, nil)
+	sessionRepo.On("RotateRefreshToken", &found, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
+
+	tokens, err := useCase.Refresh("old-token")
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, "old-token", tokens.RefreshToken)
+	claims, err := newTestIssuer(t).Parse(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionId)
+}
+
+func TestRefresh_ReusedTokenRevokesSession(t *testing.T) {
+
+	useCase, sessionRepo, _ := newTestUseCase(t)
+
+	usedAt := time.Now().Add(-time.Minute)
+	found := storedToken("old-token", &usedAt)
+	sessionRepo.On("GetRefreshToken", found.Token_hash).Return(found, nil)
+	sessionRepo.On("RevokeSession", "session-1").Return(nil)
+
+	_, err := useCase.Refresh("old-token")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	sessionRepo.AssertCalled(t, "RevokeSession", "session-1")
+}
+
+func TestRefresh_ConcurrentRotationRevokesSession(t *testing.T) {
+
+	useCase, sessionRepo, actorRepo := newTestUseCase(t)
+
+	found := storedToken("old-token", nil)
+	sessionRepo.On("GetRefreshToken", found.Token_hash).Return(found, nil)
+	actorRepo.On("GetActorById", uint(3)).Return(entity.Actor{ID: 3, Active: 1}, nil)
+	sessionRepo.On("RotateRefreshToken", &found, mock.Anything).Return(repository.ErrRefreshTokenUsed)
+	sessionRepo.On("RevokeSession", "session-1").Return(nil)
+
+	_, err := useCase.Refresh("old-token")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+}
+
+func TestRefresh_Rejected(t *testing.T) {
+
+	revokedAt := time.Now()
+	revoked := storedToken("revoked", nil)
+	revoked.Session.RevokedAt = &revokedAt
+	expired := storedToken("expired", nil)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	useCase, sessionRepo, _ := newTestUseCase(t)
+	sessionRepo.On("GetRefreshToken", token.HashRefreshToken("unknown")).Return(entity.RefreshToken{}, apperror.NotFound("refresh token not found"))
+	sessionRepo.On("GetRefreshToken", revoked.Token_hash).Return(revoked, nil)
+	sessionRepo.On("GetRefreshToken", expired.Token_hash).Return(expired, nil)
+
+	for _, refreshToken := range []string{"unknown", "revoked", "expired"} {
+		_, err := useCase.Refresh(refreshToken)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken, refreshToken)
+		assert.ErrorIs(t, err, apperror.ErrUnauthorized, refreshToken)
+	}
+}
+
+func TestRefresh_InactiveActor(t *testing.T) {
+
+	useCase, sessionRepo, actorRepo := newTestUseCase(t)
+
+	found := storedToken("old-token", nil)
+	sessionRepo.On("GetRefreshToken", found.Token_hash).Return(found, nil)
+	actorRepo.On("GetActorById", uint(3)).Return(entity.Actor{ID: 3, Active: 0}, nil)
+	sessionRepo.On("RevokeSession", "session-1").Return(nil)
+
+	_, err := useCase.Refresh("old-token")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	sessionRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestLogout(t *testing.T) {
+
+	useCase, sessionRepo, _ := newTestUseCase(t)
+
+	found := storedToken("old-token", nil)
+	sessionRepo.On("GetRefreshToken", found.Token_hash).Return(found, nil)
+	sessionRepo.On("RevokeSession", "session-1").Return(nil)
+	sessionRepo.On("GetRefreshToken", token.HashRefreshToken("unknown")).Return(entity.RefreshToken{}, apperror.NotFound("refresh token not found"))
+
+	assert.NoError(t, useCase.Logout("old-token"))
+	assert.ErrorIs(t, useCase.Logout("unknown"), ErrInvalidRefreshToken)
+}
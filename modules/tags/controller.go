@@ -0,0 +1,107 @@
+package tags
+
+import (
+	"net/url"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+type ControllerTag interface {
+	CreateTag(req TagParam) (FindTag, error)
+	GetTagById(id uint) (FindTag, error)
+	ListTags(req TagListParam, requestUrl url.URL) (ListTag, error)
+	UpdateTag(req TagParam, id uint) (FindTag, error)
+	DeleteTag(id uint) (dto.ResponseMeta, error)
+}
+
+type controllerTag struct {
+	tagUseCase UseCaseTag
+}
+
+func (uc controllerTag) CreateTag(req TagParam) (FindTag, error) {
+	tag, err := uc.tagUseCase.CreateTag(req)
+	if err != nil {
+		return FindTag{}, err
+	}
+	res := FindTag{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success create tag",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: tag,
+	}
+	return res, nil
+}
+
+func (uc controllerTag) GetTagById(id uint) (FindTag, error) {
+	tag, err := uc.tagUseCase.GetTagById(id)
+	if err != nil {
+		return FindTag{}, err
+	}
+	res := FindTag{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success get tag",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: tag,
+	}
+	return res, nil
+}
+
+func (uc controllerTag) ListTags(req TagListParam, requestUrl url.URL) (ListTag, error) {
+	page, err := uc.tagUseCase.ListTags(req)
+	if err != nil {
+		return ListTag{}, err
+	}
+	res := ListTag{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: "Success get tags",
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Tags,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Tag{}
+	}
+	return res, nil
+}
+
+func (uc controllerTag) UpdateTag(req TagParam, id uint) (FindTag, error) {
+	tag, err := uc.tagUseCase.UpdateTag(req, id)
+	if err != nil {
+		return FindTag{}, err
+	}
+	res := FindTag{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: "Success update tag",
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: tag,
+	}
+	return res, nil
+}
+
+func (uc controllerTag) DeleteTag(id uint) (dto.ResponseMeta, error) {
+	err := uc.tagUseCase.DeleteTag(id)
+	if err != nil {
+		return dto.ResponseMeta{}, err
+	}
+	return dto.ResponseMeta{
+		Success:      true,
+		MessageTitle: "Success delete tag",
+		Message:      "Success",
+		ResponseTime: "",
+	}, nil
+}
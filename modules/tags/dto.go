@@ -0,0 +1,28 @@
+package tags
+
+import (
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+// TagParam Color is an optional #rrggbb color shown with the tag
+type TagParam struct {
+	Name  string `json:"name" binding:"required,max=64"`
+	Color string `json:"color" binding:"omitempty,hexcolor,len=7"`
+}
+
+type TagListParam struct {
+	Page  int    `form:"page" binding:"min=0"`
+	Limit int    `form:"limit" binding:"min=0"`
+	Name  string `form:"name" binding:"max=64"`
+}
+
+type FindTag struct {
+	dto.ResponseMeta
+	Data entity.Tag `json:"data"`
+}
+
+type ListTag struct {
+	dto.ListResponseMeta
+	Data []entity.Tag `json:"data"`
+}
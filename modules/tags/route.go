@@ -0,0 +1,61 @@
+package tags
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RouteTag struct {
+	TagRequestHandeler RequestHandlerTag
+	Authentication     middleware.Authentication
+	Authorization      middleware.Authorization
+	Audit              middleware.Audit
+}
+
+func NewRouter(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+) RouteTag {
+	return RouteTag{
+		TagRequestHandeler: NewTagRequestHandler(
+			dbCrud,
+		),
+		Authentication: middleware.NewAuthentication(dbCrud, issuer),
+		Authorization:  middleware.NewAuthorization(dbCrud),
+		Audit:          middleware.NewAudit(dbCrud),
+	}
+}
+
+func (r RouteTag) Handle(routeVersion *gin.Engine) {
+	basepath := "/tag"
+	tag := routeVersion.Group(basepath, r.Authentication.Auth)
+
+	tag.GET("",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.TagRequestHandeler.ListTags,
+	)
+	tag.POST("",
+		r.Authorization.RequirePermission(middleware.PermissionTagManage),
+		r.Audit.Record(entity.AuditActionCreate, "tag", ""),
+		r.TagRequestHandeler.CreateTag,
+	)
+	tag.GET("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionCustomerRead),
+		r.TagRequestHandeler.GetTagById,
+	)
+	tag.PUT("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionTagManage),
+		r.Audit.Record(entity.AuditActionUpdate, "tag", "id"),
+		r.TagRequestHandeler.UpdateTag,
+	)
+	tag.DELETE("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionTagManage),
+		r.Audit.Record(entity.AuditActionDelete, "tag", "id"),
+		r.TagRequestHandeler.DeleteTag,
+	)
+}
@@ -0,0 +1,120 @@
+package tags
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+)
+
+type UseCaseTag interface {
+	CreateTag(req TagParam) (entity.Tag, error)
+	GetTagById(id uint) (entity.Tag, error)
+	ListTags(req TagListParam) (TagPage, error)
+	UpdateTag(req TagParam, id uint) (entity.Tag, error)
+	DeleteTag(id uint) error
+}
+
+var (
+	ErrTagNameRequired = apperror.Validation("tag name must not be blank", nil)
+	ErrTagNameTaken    = apperror.Conflict("a tag with this name already exists", nil)
+)
+
+// TagPage one page of tags
+type TagPage struct {
+	Tags  []entity.Tag
+	Total int64
+	Page  int
+	Limit int
+}
+
+type useCaseTag struct {
+	tagRepo repository.TagInterfaceRepo
+}
+
+func (uc useCaseTag) CreateTag(req TagParam) (entity.Tag, error) {
+	tag := entity.Tag{
+		Name:      strings.TrimSpace(req.Name),
+		Color:     strings.ToLower(req.Color),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err := uc.checkNameAvailable(tag.Name, 0)
+	if err != nil {
+		return tag, err
+	}
+	err = uc.tagRepo.CreateTag(&tag)
+	if errors.Is(err, apperror.ErrConflict) {
+		return tag, ErrTagNameTaken
+	}
+	return tag, err
+}
+
+func (uc useCaseTag) GetTagById(id uint) (entity.Tag, error) {
+	return uc.tagRepo.GetTagById(id)
+}
+
+// ListTags page of tags in name order
+func (uc useCaseTag) ListTags(req TagListParam) (TagPage, error) {
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	tags, total, err := uc.tagRepo.ListTags(strings.TrimSpace(req.Name), limit, (page-1)*limit)
+	if err != nil {
+		return TagPage{}, err
+	}
+	return TagPage{
+		Tags:  tags,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+// UpdateTag rename or recolor, the customers keep the tag
+func (uc useCaseTag) UpdateTag(req TagParam, id uint) (entity.Tag, error) {
+	tag := entity.Tag{
+		ID:        id,
+		Name:      strings.TrimSpace(req.Name),
+		Color:     strings.ToLower(req.Color),
+		UpdatedAt: time.Now(),
+	}
+	err := uc.checkNameAvailable(tag.Name, id)
+	if err != nil {
+		return tag, err
+	}
+	err = uc.tagRepo.UpdateTag(&tag, id)
+	if errors.Is(err, apperror.ErrConflict) {
+		return tag, ErrTagNameTaken
+	}
+	if err != nil {
+		return tag, err
+	}
+	return uc.tagRepo.GetTagById(id)
+}
+
+// DeleteTag the tag is taken off every customer
+func (uc useCaseTag) DeleteTag(id uint) error {
+	return uc.tagRepo.DeleteTag(id)
+}
+
+// checkNameAvailable names are unique ignoring case
+func (uc useCaseTag) checkNameAvailable(name string, id uint) error {
+	if name == "" {
+		return ErrTagNameRequired
+	}
+	existing, err := uc.tagRepo.GetTagByName(name)
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID == id {
+		return nil
+	}
+	return ErrTagNameTaken
+}
@@ -0,0 +1,115 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateTag(t *testing.T) {
+
+	mockRepo := mocks.NewTagInterfaceRepo(t)
+
+	useCase := useCaseTag{
+		tagRepo: mockRepo,
+	}
+
+	mockRepo.On("GetTagByName", "VIP").Return(entity.Tag{}, apperror.NotFound("tag not found"))
+	mockRepo.On("CreateTag", mock.MatchedBy(func(tag *entity.Tag) bool {
+		return tag.Name == "VIP" && tag.Color == "#ff8800"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.Tag).ID = 3
+	}).Return(nil)
+
+	tag, err := useCase.CreateTag(TagParam{Name: " VIP ", Color: "#FF8800"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), tag.ID)
+}
+
+func TestCreateTag_NameTaken(t *testing.T) {
+
+	mockRepo := mocks.NewTagInterfaceRepo(t)
+
+	useCase := useCaseTag{
+		tagRepo: mockRepo,
+	}
+
+	mockRepo.On("GetTagByName", "vip").Return(entity.Tag{ID: 1, Name: "VIP"}, nil)
+
+	_, err := useCase.CreateTag(TagParam{Name: "vip"})
+
+	assert.ErrorIs(t, err, ErrTagNameTaken)
+	mockRepo.AssertNotCalled(t, "CreateTag", mock.Anything)
+}
+
+func TestCreateTag_BlankName(t *testing.T) {
+
+	mockRepo := mocks.NewTagInterfaceRepo(t)
+
+	useCase := useCaseTag{
+		tagRepo: mockRepo,
+	}
+
+	_, err := useCase.CreateTag(TagParam{Name: "   "})
+
+	assert.ErrorIs(t, err, ErrTagNameRequired)
+}
+
+func TestUpdateTag_KeepsOwnName(t *testing.T) {
+
+	mockRepo := mocks.NewTagInterfaceRepo(t)
+
+	useCase := useCaseTag{
+		tagRepo: mockRepo,
+	}
+
+	mockRepo.On("GetTagByName", "vip").Return(entity.Tag{ID: 1, Name: "VIP"}, nil)
+	mockRepo.On("UpdateTag", mock.Anything, uint(1)).Return(nil)
+	mockRepo.On("GetTagById", uint(1)).Return(entity.Tag{ID: 1, Name: "vip"}, nil)
+
+	tag, err := useCase.UpdateTag(TagParam{Name: "vip"}, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vip", tag.Name)
+}
+
+func TestUpdateTag_NameOfAnotherTag(t *testing.T) {
+
+	mockRepo := mocks.NewTagInterfaceRepo(t)
+
+	useCase := useCaseTag{
+		tagRepo: mockRepo,
+	}
+
+	mockRepo.On("GetTagByName", "lead").Return(entity.Tag{ID: 2, Name: "lead"}, nil)
+
+	_, err := useCase.UpdateTag(TagParam{Name: "lead"}, 1)
+
+	assert.ErrorIs(t, err, ErrTagNameTaken)
+	mockRepo.AssertNotCalled(t, "UpdateTag", mock.Anything, mock.Anything)
+}
+
+func TestListTags(t *testing.T) {
+
+	mockRepo := mocks.NewTagInterfaceRepo(t)
+
+	useCase := useCaseTag{
+		tagRepo: mockRepo,
+	}
+
+	tags := []entity.Tag{{ID: 1, Name: "lead"}}
+	mockRepo.On("ListTags", "le", dto.MaxListLimit, 100).Return(tags, int64(101), nil)
+
+	page, err := useCase.ListTags(TagListParam{Page: 2, Limit: 500, Name: "le"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, tags, page.Tags)
+	assert.Equal(t, 2, page.Page)
+	assert.Equal(t, dto.MaxListLimit, page.Limit)
+}
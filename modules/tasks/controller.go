@@ -0,0 +1,105 @@
+package tasks
+
+import (
+	"net/url"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+type ControllerTask interface {
+	CreateTask(req TaskParam, actorName string) (FindTask, error)
+	GetTaskById(id uint) (FindTask, error)
+	ListTasks(req TaskListParam, requestUrl url.URL) (ListTask, error)
+	ListMyTasks(req TaskListParam, actorName string, requestUrl url.URL) (ListTask, error)
+	UpdateTask(req TaskParam, id uint) (FindTask, error)
+	DeleteTask(id uint) (dto.ResponseMeta, error)
+}
+
+type controllerTask struct {
+	taskUseCase UseCaseTask
+}
+
+func (uc controllerTask) CreateTask(req TaskParam, actorName string) (FindTask, error) {
+	task, err := uc.taskUseCase.CreateTask(req, actorName)
+	if err != nil {
+		return FindTask{}, err
+	}
+	return findTask("Success create task", task), nil
+}
+
+func (uc controllerTask) GetTaskById(id uint) (FindTask, error) {
+	task, err := uc.taskUseCase.GetTaskById(id)
+	if err != nil {
+		return FindTask{}, err
+	}
+	return findTask("Success get task", task), nil
+}
+
+func (uc controllerTask) ListTasks(req TaskListParam, requestUrl url.URL) (ListTask, error) {
+	page, err := uc.taskUseCase.ListTasks(req)
+	if err != nil {
+		return ListTask{}, err
+	}
+	return listTask("Success get tasks", page, requestUrl), nil
+}
+
+func (uc controllerTask) ListMyTasks(req TaskListParam, actorName string, requestUrl url.URL) (ListTask, error) {
+	page, err := uc.taskUseCase.ListMyTasks(req, actorName)
+	if err != nil {
+		return ListTask{}, err
+	}
+	return listTask("Success get my tasks", page, requestUrl), nil
+}
+
+func (uc controllerTask) UpdateTask(req TaskParam, id uint) (FindTask, error) {
+	task, err := uc.taskUseCase.UpdateTask(req, id)
+	if err != nil {
+		return FindTask{}, err
+	}
+	return findTask("Success update task", task), nil
+}
+
+func (uc controllerTask) DeleteTask(id uint) (dto.ResponseMeta, error) {
+	err := uc.taskUseCase.DeleteTask(id)
+	if err != nil {
+		return dto.ResponseMeta{}, err
+	}
+	return dto.ResponseMeta{
+		Success:      true,
+		MessageTitle: "Success delete task",
+		Message:      "Success",
+		ResponseTime: "",
+	}, nil
+}
+
+func findTask(title string, task entity.Task) FindTask {
+	return FindTask{
+		ResponseMeta: dto.ResponseMeta{
+			Success:      true,
+			MessageTitle: title,
+			Message:      "Success",
+			ResponseTime: "",
+		},
+		Data: task,
+	}
+}
+
+func listTask(title string, page TaskPage, requestUrl url.URL) ListTask {
+	res := ListTask{
+		ListResponseMeta: dto.ListResponseMeta{
+			ResponseMeta: dto.ResponseMeta{
+				Success:      true,
+				MessageTitle: title,
+				Message:      "Success",
+				ResponseTime: "",
+			},
+			Pagination: dto.NewPagination(requestUrl, page.Page, page.Limit, page.Total, ""),
+		},
+		Data: page.Tasks,
+	}
+	if res.Data == nil {
+		res.Data = []entity.Task{}
+	}
+	return res
+}
@@ -0,0 +1,54 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+)
+
+// TaskParam Due_at and Remind_at are RFC 3339 times, Assignee_id defaults to
+// the acting actor on create and keeps the assignee on update. A Deal_id
+// links the customer of the deal as well
+type TaskParam struct {
+	Title       string     `json:"title" binding:"required,max=255"`
+	Description string     `json:"description" binding:"max=10000"`
+	Due_at      time.Time  `json:"due_at" binding:"required"`
+	Remind_at   *time.Time `json:"remind_at"`
+	Priority    string     `json:"priority" binding:"omitempty,oneof=low normal high"`
+	Status      string     `json:"status" binding:"omitempty,oneof=open in_progress done cancelled"`
+	Assignee_id *uint      `json:"assignee_id" binding:"omitempty,min=1"`
+	Customer_id *uint      `json:"customer_id" binding:"omitempty,min=1"`
+	Deal_id     *uint      `json:"deal_id" binding:"omitempty,min=1"`
+}
+
+// Views of the task list
+const (
+	ViewAll     = "all"
+	ViewOverdue = "overdue"
+	ViewToday   = "today"
+)
+
+// TaskListParam View overdue lists open tasks past due, today the tasks due
+// today in the Tz time zone, server time by default
+type TaskListParam struct {
+	Page     int    `form:"page" binding:"min=0"`
+	Limit    int    `form:"limit" binding:"min=0"`
+	View     string `form:"view" binding:"omitempty,oneof=all overdue today"`
+	Tz       string `form:"tz" binding:"omitempty,timezone"`
+	Status   string `form:"status" binding:"omitempty,oneof=open in_progress done cancelled"`
+	Priority string `form:"priority" binding:"omitempty,oneof=low normal high"`
+	Assignee uint   `form:"assignee"`
+	Customer uint   `form:"customer"`
+	Deal     uint   `form:"deal"`
+}
+
+type FindTask struct {
+	dto.ResponseMeta
+	Data entity.Task `json:"data"`
+}
+
+type ListTask struct {
+	dto.ListResponseMeta
+	Data []entity.Task `json:"data"`
+}
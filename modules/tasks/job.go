@@ -0,0 +1,32 @@
+package tasks
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/job"
+	"github.com/alkamalp/crm-golang/utils/notify"
+	"gorm.io/gorm"
+)
+
+// NewReminderJob job marking open tasks past due overdue and sending due
+// reminders to the assignees through notifier
+func NewReminderJob(dbCrud *gorm.DB, notifier notify.Notifier) job.Func {
+	uc := useCaseTask{
+		taskRepo:  repository.NewTask(dbCrud),
+		actorRepo: repository.NewActor(dbCrud),
+		notifier:  notifier,
+	}
+	return func(ctx context.Context) error {
+		reminded, overdue, err := uc.SendReminders(ctx, time.Now())
+		if overdue > 0 {
+			log.Printf("marked %d tasks overdue", overdue)
+		}
+		if reminded > 0 {
+			log.Printf("sent %d task reminders", reminded)
+		}
+		return err
+	}
+}
@@ -0,0 +1,65 @@
+package tasks
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/token"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RouteTask struct {
+	TaskRequestHandeler RequestHandlerTask
+	Authentication      middleware.Authentication
+	Authorization       middleware.Authorization
+	Audit               middleware.Audit
+}
+
+func NewRouter(
+	dbCrud *gorm.DB,
+	cfg config.Config,
+	issuer token.Issuer,
+) RouteTask {
+	return RouteTask{
+		TaskRequestHandeler: NewTaskRequestHandler(
+			dbCrud,
+		),
+		Authentication: middleware.NewAuthentication(dbCrud, issuer),
+		Authorization:  middleware.NewAuthorization(dbCrud),
+		Audit:          middleware.NewAudit(dbCrud),
+	}
+}
+
+func (r RouteTask) Handle(routeVersion *gin.Engine) {
+	basepath := "/task"
+	task := routeVersion.Group(basepath, r.Authentication.Auth)
+
+	task.GET("",
+		r.Authorization.RequirePermission(middleware.PermissionTaskRead),
+		r.TaskRequestHandeler.ListTasks,
+	)
+	task.POST("",
+		r.Authorization.RequirePermission(middleware.PermissionTaskCreate),
+		r.Audit.Record(entity.AuditActionCreate, "task", ""),
+		r.TaskRequestHandeler.CreateTask,
+	)
+	task.GET("/my",
+		r.Authorization.RequirePermission(middleware.PermissionTaskRead),
+		r.TaskRequestHandeler.ListMyTasks,
+	)
+	task.GET("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionTaskRead),
+		r.TaskRequestHandeler.GetTaskById,
+	)
+	task.PUT("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionTaskUpdate),
+		r.Audit.Record(entity.AuditActionUpdate, "task", "id"),
+		r.TaskRequestHandeler.UpdateTask,
+	)
+	task.DELETE("/:id",
+		r.Authorization.RequirePermission(middleware.PermissionTaskDelete),
+		r.Audit.Record(entity.AuditActionDelete, "task", "id"),
+		r.TaskRequestHandeler.DeleteTask,
+	)
+}
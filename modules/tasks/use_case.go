@@ -0,0 +1,350 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/middleware"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/notify"
+)
+
+type UseCaseTask interface {
+	CreateTask(req TaskParam, actorName string) (entity.Task, error)
+	GetTaskById(id uint) (entity.Task, error)
+	ListTasks(req TaskListParam) (TaskPage, error)
+	ListMyTasks(req TaskListParam, actorName string) (TaskPage, error)
+	UpdateTask(req TaskParam, id uint) (entity.Task, error)
+	DeleteTask(id uint) error
+}
+
+// reminderBatch most tasks of each kind a reminder run looks at, the next
+// run picks up the rest
+const reminderBatch = 100
+
+var (
+	ErrTaskTitleRequired   = apperror.Validation("task title must not be blank", nil)
+	ErrRemindAfterDue      = apperror.Validation("remind_at must not be after due_at", nil)
+	ErrUnknownAssignee     = apperror.Validation("assignee_id does not match an actor", nil)
+	ErrUnknownCustomer     = apperror.Validation("customer_id does not match a customer", nil)
+	ErrUnknownDeal         = apperror.Validation("deal_id does not match a deal", nil)
+	ErrDealOfOtherCustomer = apperror.Validation("deal_id is a deal of another customer than customer_id", nil)
+	ErrOverdueStatus       = apperror.Validation("only open and in_progress tasks can be overdue", nil)
+)
+
+// TaskPage one page of tasks
+type TaskPage struct {
+	Tasks []entity.Task
+	Total int64
+	Page  int
+	Limit int
+}
+
+type useCaseTask struct {
+	taskRepo     repository.TaskInterfaceRepo
+	customerRepo repository.CustomerInterfaceRepo
+	dealRepo     repository.DealInterfaceRepo
+	actorRepo    repository.ActorInterfaceRepo
+	notifier     notify.Notifier
+}
+
+// CreateTask the task is assigned to req.Assignee_id or the acting actor
+func (uc useCaseTask) CreateTask(req TaskParam, actorName string) (entity.Task, error) {
+	actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return entity.Task{}, err
+	}
+	now := time.Now()
+	task := entity.Task{
+		Status:      entity.TaskStatusOpen,
+		Assignee_id: &actor.ID,
+		Created_by:  &actor.ID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err = uc.applyParam(&task, req, now)
+	if err != nil {
+		return task, err
+	}
+
+	err = uc.taskRepo.CreateTask(&task)
+	return task, err
+}
+
+func (uc useCaseTask) GetTaskById(id uint) (entity.Task, error) {
+	return uc.taskRepo.GetTaskById(id)
+}
+
+// ListTasks page of tasks in the view of req, the earliest due first
+func (uc useCaseTask) ListTasks(req TaskListParam) (TaskPage, error) {
+	limit, page := dto.PageOf(req.Limit, req.Page)
+
+	filter := repository.TaskFilter{
+		Assignee_id: req.Assignee,
+		Customer_id: req.Customer,
+		Deal_id:     req.Deal,
+		Priority:    req.Priority,
+		Limit:       limit,
+		Offset:      (page - 1) * limit,
+	}
+	if req.Status != "" {
+		filter.Statuses = []string{req.Status}
+	}
+	now := time.Now()
+	switch req.View {
+	case ViewOverdue:
+		if req.Status != "" && !entity.TaskOpen(req.Status) {
+			return TaskPage{}, ErrOverdueStatus
+		}
+		if filter.Statuses == nil {
+			filter.Statuses = entity.TaskOpenStatuses
+		}
+		filter.DueBefore = &now
+	case ViewToday:
+		location := time.Local
+		if req.Tz != "" {
+			// already checked by binding
+			location, _ = time.LoadLocation(req.Tz)
+		}
+		local := now.In(location)
+		start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+		end := start.AddDate(0, 0, 1)
+		if filter.Statuses == nil {
+			filter.Statuses = entity.TaskOpenStatuses
+		}
+		filter.DueFrom = &start
+		filter.DueBefore = &end
+	}
+
+	tasks, total, err := uc.taskRepo.ListTasks(filter)
+	if err != nil {
+		return TaskPage{}, err
+	}
+	return TaskPage{
+		Tasks: tasks,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+// ListMyTasks ListTasks of the tasks assigned to the acting actor
+func (uc useCaseTask) ListMyTasks(req TaskListParam, actorName string) (TaskPage, error) {
+	actor, err := middleware.ActingActor(uc.actorRepo, actorName)
+	if err != nil {
+		return TaskPage{}, err
+	}
+	req.Assignee = actor.ID
+	return uc.ListTasks(req)
+}
+
+// UpdateTask every field, a nil Assignee_id keeps the assignee. Moving the due
+// date or the reminder lets the reminder job notify about it again
+func (uc useCaseTask) UpdateTask(req TaskParam, id uint) (entity.Task, error) {
+	task, err := uc.taskRepo.GetTaskById(id)
+	if err != nil {
+		return task, err
+	}
+	previous := task
+	now := time.Now()
+	task.UpdatedAt = now
+	if req.Status == "" {
+		req.Status = task.Status
+	}
+	err = uc.applyParam(&task, req, now)
+	if err != nil {
+		return task, err
+	}
+	if !task.Due_at.Equal(previous.Due_at) {
+		task.Overdue_at = nil
+	}
+	if !sameTime(task.Remind_at, previous.Remind_at) {
+		task.Reminded_at = nil
+	}
+
+	err = uc.taskRepo.UpdateTask(&task, id)
+	if err != nil {
+		return task, err
+	}
+	return uc.taskRepo.GetTaskById(id)
+}
+
+func (uc useCaseTask) DeleteTask(id uint) error {
+	return uc.taskRepo.DeleteTask(id)
+}
+
+// SendReminders mark open tasks past due overdue and send the reminders whose
+// time has come, both through the notifier to the assignee. A task is marked
+// before its notification goes out so concurrent runs do not notify twice,
+// and unmarked again when the notification fails so the next run retries
+func (uc useCaseTask) SendReminders(ctx context.Context, now time.Time) (reminded int, overdue int, err error) {
+	var errs []error
+	recipients := map[uint]string{}
+
+	tasks, err := uc.taskRepo.ListNewlyOverdue(now, reminderBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, task := range tasks {
+		sent, err := uc.notifyOnce(ctx, task, notify.KindOverdue, now, recipients)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if sent {
+			overdue++
+		}
+	}
+
+	tasks, err = uc.taskRepo.ListDueReminders(now, reminderBatch)
+	if err != nil {
+		return reminded, overdue, errors.Join(append(errs, err)...)
+	}
+	for _, task := range tasks {
+		sent, err := uc.notifyOnce(ctx, task, notify.KindReminder, now, recipients)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if sent {
+			reminded++
+		}
+	}
+	return reminded, overdue, errors.Join(errs...)
+}
+
+// notifyOnce mark the task and notify its assignee, false when a concurrent
+// run marked it first or the notification failed
+func (uc useCaseTask) notifyOnce(ctx context.Context, task entity.Task, kind string, now time.Time, recipients map[uint]string) (bool, error) {
+	mark, unmark := uc.taskRepo.MarkOverdue, uc.taskRepo.UnmarkOverdue
+	if kind == notify.KindReminder {
+		mark, unmark = uc.taskRepo.MarkReminded, uc.taskRepo.UnmarkReminded
+	}
+	marked, err := mark(task.ID, now)
+	if err != nil || !marked {
+		return false, err
+	}
+
+	err = uc.notifier.Notify(ctx, notify.Notification{
+		Kind:        kind,
+		Recipient:   uc.recipient(task.Assignee_id, recipients),
+		Subject:     task.Title,
+		Entity_type: "task",
+		Entity_id:   task.ID,
+		Due_at:      task.Due_at,
+	})
+	if err != nil {
+		return false, errors.Join(err, unmark(task.ID))
+	}
+	return true, nil
+}
+
+// recipient username of the assignee, empty when there is none
+func (uc useCaseTask) recipient(assigneeId *uint, known map[uint]string) string {
+	if assigneeId == nil {
+		return ""
+	}
+	username, ok := known[*assigneeId]
+	if !ok {
+		actor, err := uc.actorRepo.GetActorById(*assigneeId)
+		if err == nil {
+			username = actor.Username
+		}
+		known[*assigneeId] = username
+	}
+	return username
+}
+
+// applyParam copy req onto task, checking the actor, customer and deal it
+// refers to. Completed_at follows the status
+func (uc useCaseTask) applyParam(task *entity.Task, req TaskParam, now time.Time) error {
+	task.Title = strings.TrimSpace(req.Title)
+	task.Description = req.Description
+	task.Due_at = req.Due_at.UTC()
+	task.Remind_at = nil
+	if req.Remind_at != nil {
+		remindAt := req.Remind_at.UTC()
+		task.Remind_at = &remindAt
+	}
+	task.Priority = req.Priority
+	if task.Priority == "" {
+		task.Priority = entity.TaskPriorityNormal
+	}
+	if req.Status != "" {
+		task.Status = req.Status
+	}
+	if task.Title == "" {
+		return ErrTaskTitleRequired
+	}
+	if task.Remind_at != nil && task.Remind_at.After(task.Due_at) {
+		return ErrRemindAfterDue
+	}
+
+	switch {
+	case task.Status == entity.TaskStatusDone && task.Completed_at == nil:
+		completedAt := now.UTC()
+		task.Completed_at = &completedAt
+	case task.Status != entity.TaskStatusDone:
+		task.Completed_at = nil
+	}
+
+	if req.Assignee_id != nil {
+		assignee, err := uc.actorRepo.GetActorById(*req.Assignee_id)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return ErrUnknownAssignee
+		}
+		if err != nil {
+			return err
+		}
+		task.Assignee_id = &assignee.ID
+	}
+
+	task.Customer_id = nil
+	if req.Customer_id != nil {
+		customerId, err := uc.taskCustomer(*req.Customer_id)
+		if err != nil {
+			return err
+		}
+		task.Customer_id = &customerId
+	}
+	task.Deal_id = nil
+	if req.Deal_id != nil {
+		deal, err := uc.dealRepo.GetDealById(*req.Deal_id)
+		if errors.Is(err, apperror.ErrNotFound) {
+			return ErrUnknownDeal
+		}
+		if err != nil {
+			return err
+		}
+		if task.Customer_id != nil && *task.Customer_id != deal.Customer_id {
+			return ErrDealOfOtherCustomer
+		}
+		task.Deal_id = &deal.ID
+		task.Customer_id = &deal.Customer_id
+	}
+	return nil
+}
+
+// taskCustomer id of the customer a task is about, a merged customer resolves
+// to the survivor
+func (uc useCaseTask) taskCustomer(id uint) (uint, error) {
+	customer, err := uc.customerRepo.GetCustomerById(id)
+	if errors.Is(err, apperror.ErrNotFound) {
+		redirect, redirectErr := uc.customerRepo.GetCustomerRedirect(id)
+		if redirectErr != nil {
+			return 0, ErrUnknownCustomer
+		}
+		return redirect.Survivor_id, nil
+	}
+	return customer.ID, err
+}
+
+func sameTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,256 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/repository"
+	"github.com/alkamalp/crm-golang/repository/mocks"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/alkamalp/crm-golang/utils/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingNotifier keeps what it is asked to send, failing with err
+type recordingNotifier struct {
+	sent []notify.Notification
+	err  error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification notify.Notification) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestCreateTask(t *testing.T) {
+
+	mockRepo := mocks.NewTaskInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseTask{
+		taskRepo:  mockRepo,
+		actorRepo: mockActorRepo,
+	}
+
+	due := time.Date(2026, 10, 20, 9, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockRepo.On("CreateTask", mock.MatchedBy(func(task *entity.Task) bool {
+		return *task.Assignee_id == 5 && *task.Created_by == 5 && task.Status == entity.TaskStatusOpen &&
+			task.Priority == entity.TaskPriorityNormal && task.Due_at.Location() == time.UTC && task.Due_at.Equal(due)
+	})).Return(nil)
+
+	task, err := useCase.CreateTask(TaskParam{Title: " Call back ", Due_at: due}, "admin1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Call back", task.Title)
+	assert.Nil(t, task.Customer_id)
+}
+
+func TestCreateTask_DealLinksCustomer(t *testing.T) {
+
+	mockRepo := mocks.NewTaskInterfaceRepo(t)
+	mockDealRepo := mocks.NewDealInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseTask{
+		taskRepo:  mockRepo,
+		dealRepo:  mockDealRepo,
+		actorRepo: mockActorRepo,
+	}
+
+	dealId := uint(4)
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockDealRepo.On("GetDealById", dealId).Return(entity.Deal{ID: 4, Customer_id: 3}, nil)
+	mockRepo.On("CreateTask", mock.Anything).Return(nil)
+
+	task, err := useCase.CreateTask(TaskParam{Title: "Send proposal", Due_at: time.Now(), Deal_id: &dealId}, "admin1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), *task.Customer_id)
+	assert.Equal(t, uint(4), *task.Deal_id)
+}
+
+func TestCreateTask_DealOfOtherCustomer(t *testing.T) {
+
+	mockCustomerRepo := mocks.NewCustomerInterfaceRepo(t)
+	mockDealRepo := mocks.NewDealInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseTask{
+		customerRepo: mockCustomerRepo,
+		dealRepo:     mockDealRepo,
+		actorRepo:    mockActorRepo,
+	}
+
+	customerId, dealId := uint(8), uint(4)
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockCustomerRepo.On("GetCustomerById", customerId).Return(entity.Customer{ID: 8}, nil)
+	mockDealRepo.On("GetDealById", dealId).Return(entity.Deal{ID: 4, Customer_id: 3}, nil)
+
+	_, err := useCase.CreateTask(TaskParam{Title: "Send proposal", Due_at: time.Now(), Customer_id: &customerId, Deal_id: &dealId}, "admin1")
+
+	assert.ErrorIs(t, err, ErrDealOfOtherCustomer)
+}
+
+func TestCreateTask_RemindAfterDue(t *testing.T) {
+
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseTask{
+		actorRepo: mockActorRepo,
+	}
+
+	due := time.Now()
+	remindAt := due.Add(time.Minute)
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+
+	_, err := useCase.CreateTask(TaskParam{Title: "Call back", Due_at: due, Remind_at: &remindAt}, "admin1")
+
+	assert.ErrorIs(t, err, ErrRemindAfterDue)
+}
+
+func TestListMyTasks_Today(t *testing.T) {
+
+	mockRepo := mocks.NewTaskInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseTask{
+		taskRepo:  mockRepo,
+		actorRepo: mockActorRepo,
+	}
+
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockRepo.On("ListTasks", mock.MatchedBy(func(filter repository.TaskFilter) bool {
+		start := filter.DueFrom.In(jakarta)
+		return filter.Assignee_id == 5 && assert.ObjectsAreEqual(entity.TaskOpenStatuses, filter.Statuses) &&
+			start.Hour() == 0 && start.Minute() == 0 && start.Day() == time.Now().In(jakarta).Day() &&
+			filter.DueBefore.Sub(*filter.DueFrom) == 24*time.Hour
+	})).Return([]entity.Task{{ID: 1}}, int64(1), nil)
+
+	page, err := useCase.ListMyTasks(TaskListParam{View: ViewToday, Tz: "Asia/Jakarta", Assignee: 9}, "admin1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+}
+
+func TestListTasks_OverdueOfClosedStatus(t *testing.T) {
+
+	mockRepo := mocks.NewTaskInterfaceRepo(t)
+
+	useCase := useCaseTask{
+		taskRepo: mockRepo,
+	}
+
+	_, err := useCase.ListTasks(TaskListParam{View: ViewOverdue, Status: entity.TaskStatusDone})
+
+	assert.ErrorIs(t, err, ErrOverdueStatus)
+	mockRepo.AssertNotCalled(t, "ListTasks", mock.Anything)
+}
+
+func TestUpdateTask_RearmsReminders(t *testing.T) {
+
+	mockRepo := mocks.NewTaskInterfaceRepo(t)
+
+	useCase := useCaseTask{
+		taskRepo: mockRepo,
+	}
+
+	assignee := uint(5)
+	due := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	remindAt := due.Add(-time.Hour)
+	marked := due.Add(time.Minute)
+	mockRepo.On("GetTaskById", uint(1)).Return(entity.Task{
+		ID: 1, Title: "Call back", Due_at: due, Remind_at: &remindAt, Status: entity.TaskStatusOpen,
+		Assignee_id: &assignee, Reminded_at: &remindAt, Overdue_at: &marked,
+	}, nil)
+	mockRepo.On("UpdateTask", mock.MatchedBy(func(task *entity.Task) bool {
+		return task.Overdue_at == nil && task.Reminded_at != nil && *task.Assignee_id == 5 &&
+			task.Status == entity.TaskStatusDone && task.Completed_at != nil
+	}), uint(1)).Return(nil)
+
+	_, err := useCase.UpdateTask(TaskParam{Title: "Call back", Due_at: due.AddDate(0, 0, 1), Remind_at: &remindAt, Status: entity.TaskStatusDone}, 1)
+
+	assert.NoError(t, err)
+}
+
+func TestSendReminders(t *testing.T) {
+
+	mockRepo := mocks.NewTaskInterfaceRepo(t)
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+	notifier := &recordingNotifier{}
+
+	useCase := useCaseTask{
+		taskRepo:  mockRepo,
+		actorRepo: mockActorRepo,
+		notifier:  notifier,
+	}
+
+	now := time.Now()
+	assignee := uint(5)
+	mockRepo.On("ListNewlyOverdue", now, reminderBatch).Return([]entity.Task{
+		{ID: 1, Title: "late", Assignee_id: &assignee},
+		{ID: 2, Title: "taken by another run"},
+	}, nil)
+	mockRepo.On("MarkOverdue", uint(1), now).Return(true, nil)
+	mockRepo.On("MarkOverdue", uint(2), now).Return(false, nil)
+	mockRepo.On("ListDueReminders", now, reminderBatch).Return([]entity.Task{{ID: 3, Title: "soon", Assignee_id: &assignee}}, nil)
+	mockRepo.On("MarkReminded", uint(3), now).Return(true, nil)
+	mockActorRepo.On("GetActorById", assignee).Return(entity.Actor{ID: 5, Username: "alice"}, nil).Once()
+
+	reminded, overdue, err := useCase.SendReminders(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reminded)
+	assert.Equal(t, 1, overdue)
+	assert.Equal(t, []notify.Notification{
+		{Kind: notify.KindOverdue, Recipient: "alice", Subject: "late", Entity_type: "task", Entity_id: 1},
+		{Kind: notify.KindReminder, Recipient: "alice", Subject: "soon", Entity_type: "task", Entity_id: 3},
+	}, notifier.sent)
+}
+
+func TestSendReminders_NotifierFails(t *testing.T) {
+
+	mockRepo := mocks.NewTaskInterfaceRepo(t)
+	notifier := &recordingNotifier{err: errors.New("webhook down")}
+
+	useCase := useCaseTask{
+		taskRepo: mockRepo,
+		notifier: notifier,
+	}
+
+	now := time.Now()
+	mockRepo.On("ListNewlyOverdue", now, reminderBatch).Return([]entity.Task{}, nil)
+	mockRepo.On("ListDueReminders", now, reminderBatch).Return([]entity.Task{{ID: 3, Title: "soon"}}, nil)
+	mockRepo.On("MarkReminded", uint(3), now).Return(true, nil)
+	mockRepo.On("UnmarkReminded", uint(3)).Return(nil)
+
+	reminded, _, err := useCase.SendReminders(context.Background(), now)
+
+	assert.ErrorContains(t, err, "webhook down")
+	assert.Equal(t, 0, reminded)
+}
+
+func TestCreateTask_UnknownAssignee(t *testing.T) {
+
+	mockActorRepo := mocks.NewActorInterfaceRepo(t)
+
+	useCase := useCaseTask{
+		actorRepo: mockActorRepo,
+	}
+
+	assignee := uint(9)
+	mockActorRepo.On("GetActorByUsername", "admin1").Return(entity.Actor{ID: 5, Username: "admin1"}, nil)
+	mockActorRepo.On("GetActorById", assignee).Return(entity.Actor{}, apperror.NotFound("actor not found"))
+
+	_, err := useCase.CreateTask(TaskParam{Title: "Call back", Due_at: time.Now(), Assignee_id: &assignee}, "admin1")
+
+	assert.ErrorIs(t, err, ErrUnknownAssignee)
+}
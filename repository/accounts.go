@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"gorm.io/gorm"
+)
+
+type Account struct {
+	db *gorm.DB
+}
+
+func NewAccount(dbCrud *gorm.DB) Account {
+	return Account{
+		db: dbCrud,
+	}
+}
+
+type AccountInterfaceRepo interface {
+	CreateAccount(account *entity.Account) error
+	GetAccountById(id uint) (entity.Account, error)
+	ListAccounts(name string, limit int, offset int) ([]entity.Account, int64, error)
+	UpdateAccount(account *entity.Account, id uint) error
+	DeleteAccount(id uint) error
+	DeleteAccountWithContacts(id uint, deletedBy uint) (int64, error)
+	CountContacts(id uint) (int64, error)
+	SetContactsAccount(customerIds []uint, accountId uint) (int64, error)
+	MoveContacts(fromId uint, toId *uint, customerIds []uint) (int64, error)
+}
+
+// CreateAccount new Account
+func (repo Account) CreateAccount(account *entity.Account) error {
+	err := repo.db.Create(account).Error
+	return translateError(err, "account")
+}
+
+// GetAccountById get single Account by id
+func (repo Account) GetAccountById(id uint) (entity.Account, error) {
+	var account entity.Account
+	err := repo.db.First(&account, "id = ?", id).Error
+	return account, translateError(err, "account")
+}
+
+// ListAccounts page of accounts in name order, name filters on a part of the
+// name
+func (repo Account) ListAccounts(name string, limit int, offset int) ([]entity.Account, int64, error) {
+	query := repo.db.Model(&entity.Account{})
+	if name != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(name)+"%")
+	}
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var accounts []entity.Account
+	err = query.Order("name").Order("id").Limit(limit).Offset(offset).Find(&accounts).Error
+	return accounts, total, err
+}
+
+// UpdateAccount name, domain and industry
+func (repo Account) UpdateAccount(account *entity.Account, id uint) error {
+	res := repo.db.Model(&entity.Account{}).Where("id = ?", id).
+		Select("name", "domain", "industry", "updated_at").
+		Updates(account)
+	return affectedOrNotFound(res, &entity.Account{}, "account", "id = ?", id)
+}
+
+// DeleteAccount the contacts, trashed ones included, are unlinked and stay,
+// each recording the unlink as its next version
+func (repo Account) DeleteAccount(id uint) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		_, err := updateVersionedWhere(tx, tx.Where("account_id = ?", id), map[string]any{"account_id": nil})
+		if err != nil {
+			return err
+		}
+		res := tx.Delete(&entity.Account{}, id)
+		return affectedOrNotFound(res, &entity.Account{}, "account", "id = ?", id)
+	})
+}
+
+// DeleteAccountWithContacts in one transaction move the contacts to the trash,
+// recording who deleted them, and delete the account. The number of trashed
+// contacts is returned
+func (repo Account) DeleteAccountWithContacts(id uint, deletedBy uint) (int64, error) {
+	var trashed int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.Customer{}).
+			Where("account_id = ?", id).
+			Updates(map[string]any{
+				"deleted_at":       time.Now().UTC(),
+				"deleted_by":       deletedBy,
+				"email_normalized": nil,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		trashed = res.RowsAffected
+		return Account{db: tx}.DeleteAccount(id)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return trashed, nil
+}
+
+// CountContacts customers of the account that are not in the trash
+func (repo Account) CountContacts(id uint) (int64, error) {
+	var count int64
+	err := repo.db.Model(&entity.Customer{}).Where("account_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// SetContactsAccount link customerIds to accountId, whatever account they had.
+// Trashed and unknown customers are ignored, the number of customers changed
+// is returned and each records the link as its next version
+func (repo Account) SetContactsAccount(customerIds []uint, accountId uint) (int64, error) {
+	var changed int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		changed, err = updateVersionedWhere(tx,
+			tx.Where("id IN ? AND deleted_at IS NULL", customerIds),
+			map[string]any{"account_id": accountId})
+		return err
+	})
+	return changed, err
+}
+
+// MoveContacts move contacts of fromId to toId, only those among customerIds
+// when it is not empty, a nil toId unlinks them. The number of contacts moved
+// is returned and each records the move as its next version
+func (repo Account) MoveContacts(fromId uint, toId *uint, customerIds []uint) (int64, error) {
+	var moved int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("account_id = ? AND deleted_at IS NULL", fromId)
+		if len(customerIds) > 0 {
+			query = query.Where("id IN ?", customerIds)
+		}
+		var err error
+		moved, err = updateVersionedWhere(tx, query, map[string]any{"account_id": toId})
+		return err
+	})
+	return moved, err
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedAccounts(t *testing.T, repo Account, names ...string) []entity.Account {
+	t.Helper()
+	var accounts []entity.Account
+	for _, name := range names {
+		account := entity.Account{Name: name}
+		require.NoError(t, repo.CreateAccount(&account))
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
+func TestAccount_CRUD(t *testing.T) {
+	repo := NewAccount(newTestDB(t))
+	seeded := seedAccounts(t, repo, "Globex", "Acme", "Acme Labs")
+
+	accounts, total, err := repo.ListAccounts("acme", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"Acme", "Acme Labs"}, []string{accounts[0].Name, accounts[1].Name})
+
+	err = repo.UpdateAccount(&entity.Account{Name: "Globex Corp", Domain: "globex.com"}, seeded[0].ID)
+	require.NoError(t, err)
+	found, err := repo.GetAccountById(seeded[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Globex Corp", found.Name)
+	assert.Equal(t, "globex.com", found.Domain)
+
+	require.NoError(t, repo.DeleteAccount(seeded[0].ID))
+	assert.ErrorIs(t, repo.DeleteAccount(seeded[0].ID), apperror.ErrNotFound)
+	assert.ErrorIs(t, repo.UpdateAccount(&entity.Account{Name: "x"}, seeded[0].ID), apperror.ErrNotFound)
+}
+
+func TestAccount_Contacts(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewAccount(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	accounts := seedAccounts(t, repo, "Acme", "Globex")
+	customers := seedCustomers(t, customerRepo, "john", "jane", "jack")
+	acme, globex := accounts[0].ID, accounts[1].ID
+
+	linked, err := repo.SetContactsAccount(customerIds(customers), acme)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), linked)
+
+	contacts, total, err := customerRepo.ListCustomers(CustomerFilter{Account_id: acme, Sort: []SortField{{Column: "id"}}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, customerIds(customers), customerIds(contacts))
+
+	moved, err := repo.MoveContacts(acme, &globex, []uint{customers[1].ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), moved)
+	count, err := repo.CountContacts(globex)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// without ids every contact moves
+	moved, err = repo.MoveContacts(acme, &globex, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), moved)
+
+	// contacts of another account are left alone
+	unlinked, err := repo.MoveContacts(acme, nil, []uint{customers[2].ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), unlinked)
+	unlinked, err = repo.MoveContacts(globex, nil, []uint{customers[2].ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), unlinked)
+	count, err = repo.CountContacts(globex)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestAccount_ContactsVersioned(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewAccount(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	accounts := seedAccounts(t, repo, "Acme", "Globex")
+	customer := seedCustomers(t, customerRepo, "john")[0]
+	acme, globex := accounts[0].ID, accounts[1].ID
+
+	_, err := repo.SetContactsAccount([]uint{customer.ID}, acme)
+	require.NoError(t, err)
+	linkedAt := time.Now()
+	found, err := customerRepo.GetCustomerById(customer.ID)
+	require.NoError(t, err)
+	assert.True(t, found.UpdatedAt.After(customer.UpdatedAt))
+
+	_, err = repo.MoveContacts(acme, &globex, nil)
+	require.NoError(t, err)
+	movedAt := time.Now()
+	require.NoError(t, repo.DeleteAccount(globex))
+
+	versions, total, err := customerRepo.ListCustomerVersions(customer.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	assert.Nil(t, versions[0].Account_id)
+	assert.Nil(t, versions[3].Account_id)
+
+	version, err := customerRepo.GetCustomerVersionAt(customer.ID, linkedAt)
+	require.NoError(t, err)
+	assert.Equal(t, acme, *version.Account_id)
+	version, err = customerRepo.GetCustomerVersionAt(customer.ID, movedAt)
+	require.NoError(t, err)
+	assert.Equal(t, globex, *version.Account_id)
+}
+
+func TestAccount_DeleteUnlinksContacts(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewAccount(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	account := seedAccounts(t, repo, "Acme")[0]
+	customer := seedCustomers(t, customerRepo, "john")[0]
+	_, err := repo.SetContactsAccount([]uint{customer.ID}, account.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteAccount(account.ID))
+
+	found, err := customerRepo.GetCustomerById(customer.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found.Account_id)
+}
+
+func TestAccount_DeleteAccountWithContacts(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewAccount(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	account := seedAccounts(t, repo, "Acme")[0]
+	customers := seedCustomers(t, customerRepo, "john", "jane")
+	actor := seedActor(t, NewActor(dbCrud), "alice")
+	_, err := repo.SetContactsAccount([]uint{customers[0].ID}, account.ID)
+	require.NoError(t, err)
+
+	trashed, err := repo.DeleteAccountWithContacts(account.ID, actor.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), trashed)
+
+	_, err = customerRepo.GetCustomerById(customers[0].ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	_, err = customerRepo.GetCustomerById(customers[1].ID)
+	assert.NoError(t, err)
+	_, err = repo.GetAccountById(account.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	// the contact is restored without its account
+	restored, err := customerRepo.RestoreCustomer(customers[0].ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.Account_id)
+}
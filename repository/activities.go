@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"gorm.io/gorm"
+)
+
+type Activity struct {
+	db *gorm.DB
+}
+
+func NewActivity(dbCrud *gorm.DB) Activity {
+	return Activity{
+		db: dbCrud,
+	}
+}
+
+type ActivityInterfaceRepo interface {
+	CreateActivity(activity *entity.Activity) error
+	GetActivityById(id uint) (entity.Activity, error)
+	UpdateActivity(activity *entity.Activity, id uint) error
+	DeleteActivity(id uint) error
+	ListTimeline(customerId uint, limit int, offset int) ([]entity.TimelineEntry, int64, error)
+}
+
+// CreateActivity new Activity
+func (repo Activity) CreateActivity(activity *entity.Activity) error {
+	err := repo.db.Create(activity).Error
+	return translateError(err, "activity")
+}
+
+// GetActivityById get single Activity by id
+func (repo Activity) GetActivityById(id uint) (entity.Activity, error) {
+	var activity entity.Activity
+	err := repo.db.First(&activity, "id = ?", id).Error
+	return activity, translateError(err, "activity")
+}
+
+// UpdateActivity type, subject, body and when it happened, the customer and
+// the actor who recorded it stay
+func (repo Activity) UpdateActivity(activity *entity.Activity, id uint) error {
+	res := repo.db.Model(&entity.Activity{}).Where("id = ?", id).
+		Select("type", "subject", "body", "occurred_at", "updated_at").
+		Updates(activity)
+	return affectedOrNotFound(res, &entity.Activity{}, "activity", "id = ?", id)
+}
+
+func (repo Activity) DeleteActivity(id uint) error {
+	res := repo.db.Delete(&entity.Activity{}, id)
+	return affectedOrNotFound(res, &entity.Activity{}, "activity", "id = ?", id)
+}
+
+// ListTimeline page of the activities of a customer merged with the event of
+// its creation and one event per update and merge of it in the audit log,
+// most recent first
+func (repo Activity) ListTimeline(customerId uint, limit int, offset int) ([]entity.TimelineEntry, int64, error) {
+	activities := repo.db.Model(&entity.Activity{}).
+		Select("? AS kind, id, type, subject, body, actor_id, occurred_at", entity.TimelineKindActivity).
+		Where("customer_id = ?", customerId)
+	audits := repo.db.Model(&entity.AuditLog{}).
+		Where("entity_type = ? AND entity_id = ?", "customer", customerId)
+	events := audits.Session(&gorm.Session{}).
+		Select("? AS kind, id, CASE action WHEN ? THEN ? ELSE ? END AS type, '' AS subject, '' AS body, actor_id, created_at AS occurred_at",
+			entity.TimelineKindEvent, entity.AuditActionMerge, entity.TimelineEventMerged, entity.TimelineEventUpdated).
+		Where("action IN ?", []string{entity.AuditActionUpdate, entity.AuditActionMerge})
+	created := repo.db.Model(&entity.Customer{}).
+		Select("? AS kind, id, ? AS type, '' AS subject, '' AS body, NULL AS actor_id, created_at AS occurred_at",
+			entity.TimelineKindEvent, entity.TimelineEventCreated).
+		Where("id = ?", customerId)
+	timeline := repo.db.Table("(? UNION ALL ? UNION ALL ?) AS timeline", activities, events, created)
+
+	var total int64
+	err := timeline.Session(&gorm.Session{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entity.TimelineEntry
+	err = timeline.Select("timeline.*, actors.username AS actor_name").
+		Joins("LEFT JOIN actors ON actors.id = timeline.actor_id").
+		Order("timeline.occurred_at DESC, timeline.kind, timeline.id DESC").
+		Limit(limit).Offset(offset).
+		Scan(&entries).Error
+	return entries, total, err
+}
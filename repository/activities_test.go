@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivity_CRUD(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewActivity(dbCrud)
+	customer := seedCustomers(t, NewCustomer(dbCrud), "john")[0]
+	activity := entity.Activity{Customer_id: customer.ID, Type: entity.ActivityTypeCall, Subject: "Intro call", Occurred_at: time.Now()}
+	require.NoError(t, repo.CreateActivity(&activity))
+
+	err := repo.UpdateActivity(&entity.Activity{Type: entity.ActivityTypeMeeting, Body: "Demo", Occurred_at: time.Now()}, activity.ID)
+	require.NoError(t, err)
+	found, err := repo.GetActivityById(activity.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ActivityTypeMeeting, found.Type)
+	assert.Equal(t, "", found.Subject)
+	assert.Equal(t, customer.ID, found.Customer_id)
+
+	require.NoError(t, repo.DeleteActivity(activity.ID))
+	assert.ErrorIs(t, repo.DeleteActivity(activity.ID), apperror.ErrNotFound)
+	assert.ErrorIs(t, repo.UpdateActivity(&entity.Activity{}, activity.ID), apperror.ErrNotFound)
+}
+
+func TestActivity_ListTimeline(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewActivity(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	actor := entity.Actor{Username: "admin1", Role_id: 1}
+	require.NoError(t, dbCrud.Create(&actor).Error)
+	seeded := seedCustomers(t, customerRepo, "john", "jon")
+	start := seeded[0].CreatedAt
+
+	// recorded afterwards, about calls made before the customer was added
+	activities := []entity.Activity{
+		{Customer_id: seeded[0].ID, Actor_id: &actor.ID, Type: entity.ActivityTypeNote, Body: "Wants a quote", Occurred_at: start.Add(-2 * time.Hour)},
+		{Customer_id: seeded[1].ID, Actor_id: &actor.ID, Type: entity.ActivityTypeCall, Subject: "Follow up", Occurred_at: start.Add(-time.Hour)},
+	}
+	for i := range activities {
+		require.NoError(t, repo.CreateActivity(&activities[i]))
+	}
+
+	// the update the merge makes to the survivor is not an event of its own
+	survivor, err := customerRepo.GetCustomerById(seeded[0].ID)
+	require.NoError(t, err)
+	err = customerRepo.MergeCustomers(&survivor, []uint{seeded[1].ID}, &entity.AuditLog{
+		Actor_id:    &actor.ID,
+		Action:      entity.AuditActionMerge,
+		Entity_type: "customer",
+		Entity_id:   survivor.ID,
+	})
+	require.NoError(t, err)
+
+	entries, total, err := repo.ListTimeline(survivor.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total)
+	var types []string
+	for _, entry := range entries {
+		types = append(types, entry.Type)
+	}
+	assert.Equal(t, []string{entity.TimelineEventMerged, entity.TimelineEventCreated, entity.ActivityTypeCall, entity.ActivityTypeNote}, types)
+	assert.Equal(t, "admin1", *entries[0].Actor_name)
+	assert.Nil(t, entries[1].Actor_id)
+	assert.True(t, entries[1].Occurred_at.Equal(start))
+	assert.Equal(t, activities[1].ID, entries[2].ID)
+
+	// every update in the audit log is an event, updates of other entities
+	// with the same id are not
+	for i, entityType := range []string{"customer", "customer", "account"} {
+		require.NoError(t, dbCrud.Create(&entity.AuditLog{
+			Actor_id:    &actor.ID,
+			Action:      entity.AuditActionUpdate,
+			Entity_type: entityType,
+			Entity_id:   survivor.ID,
+			CreatedAt:   time.Now().Add(time.Duration(i+1) * time.Hour),
+		}).Error)
+	}
+	entries, total, err = repo.ListTimeline(survivor.ID, 3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), total)
+	require.Len(t, entries, 3)
+	assert.Equal(t, entity.TimelineEventUpdated, entries[0].Type)
+	assert.Equal(t, entity.TimelineEventUpdated, entries[1].Type)
+	assert.Equal(t, "admin1", *entries[1].Actor_name)
+	assert.Equal(t, entity.TimelineEventMerged, entries[2].Type)
+}
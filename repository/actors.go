@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/alkamalp/crm-golang/entity"
 	"gorm.io/gorm"
 )
@@ -19,40 +21,142 @@ type ActorInterfaceRepo interface {
 	CreateActor(actor *entity.Actor) (*entity.Actor, error)
 	GetActorById(id uint) (entity.Actor, error)
 	UpdateActor(actor *entity.Actor, id uint) (*entity.Actor, error)
-	DeleteActor(username string) (any, error)
-	LoginActor(actor *entity.Actor) (*entity.Actor, error)
+	DeleteActor(username string, deletedBy uint) (any, error)
+	GetActorByUsername(username string) (entity.Actor, error)
+	ListDeletedActors(limit int, offset int) ([]entity.Actor, int64, error)
+	RestoreActor(username string) (entity.Actor, error)
+	PurgeActors(deletedBefore time.Time) (int64, error)
 }
 
-// CreateActor new Actor
+// CreateActor new Actor together with its pending registration approval
 func (repo Actor) CreateActor(actor *entity.Actor) (*entity.Actor, error) {
-	err := repo.db.Model(&entity.Actor{}).Create(actor).Error
-	return actor, err
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Actor{}).Create(actor).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&entity.RegisterApproval{
+			Admin_id: actor.ID,
+			Status:   entity.ApprovalStatusPending,
+		}).Error
+	})
+	return actor, translateError(err, "actor")
 }
 
 // GetActorById get single Actor by id
 func (repo Actor) GetActorById(id uint) (entity.Actor, error) {
 	var actor entity.Actor
-	repo.db.First(&actor, "id = ? ", id)
-	return actor, nil
+	err := repo.db.First(&actor, "id = ? ", id).Error
+	return actor, translateError(err, "actor")
 }
 
 // UpdateActor multiple fields
 func (repo Actor) UpdateActor(actor *entity.Actor, id uint) (*entity.Actor, error) {
-	err := repo.db.Model(&entity.Actor{}).Where("id = ?", id).
-		Updates(actor).Error
-	return nil, err
+	res := repo.db.Model(&entity.Actor{}).Where("id = ?", id).
+		Updates(actor)
+	return nil, affectedOrNotFound(res, &entity.Actor{}, "actor", "id = ?", id)
+}
+
+// DeleteActor move to the trash by username and revoke its sessions, its
+// registration approvals are kept until the actor is purged
+func (repo Actor) DeleteActor(username string, deletedBy uint) (any, error) {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var actor entity.Actor
+		err := tx.Select("id").First(&actor, "username = ?", username).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		err = tx.Model(&entity.Actor{}).
+			Where("id = ?", actor.ID).
+			Updates(map[string]any{
+				"deleted_at": now,
+				"deleted_by": deletedBy,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&entity.Session{}).
+			Where("actor_id = ? AND revoked_at IS NULL", actor.ID).
+			Update("revoked_at", now).Error
+	})
+	return nil, translateError(err, "actor")
+}
+
+// ListDeletedActors page of the trash, most recently deleted first
+func (repo Actor) ListDeletedActors(limit int, offset int) ([]entity.Actor, int64, error) {
+	var total int64
+	trash := repo.db.Unscoped().Model(&entity.Actor{}).Where("deleted_at IS NOT NULL")
+	err := trash.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var actors []entity.Actor
+	err = trash.Order("deleted_at DESC").Order("id").
+		Limit(limit).Offset(offset).
+		Find(&actors).Error
+	return actors, total, err
 }
 
-// DeleteActor by Id and email
-func (repo Actor) DeleteActor(username string) (any, error) {
-	err := repo.db.Model(&entity.Actor{}).
-		Where("username = ?", username).
-		Delete(&entity.Actor{}).
-		Error
-	return nil, err
+// RestoreActor take an actor out of the trash by username, its revoked
+// sessions stay revoked
+func (repo Actor) RestoreActor(username string) (entity.Actor, error) {
+	var actor entity.Actor
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().First(&actor, "username = ? AND deleted_at IS NOT NULL", username).Error
+		if err != nil {
+			return translateError(err, "deleted actor")
+		}
+		return tx.Unscoped().Model(&entity.Actor{}).
+			Where("id = ?", actor.ID).
+			Updates(map[string]any{
+				"deleted_at": nil,
+				"deleted_by": nil,
+			}).Error
+	})
+	if err != nil {
+		return actor, err
+	}
+	actor.Deleted_at = gorm.DeletedAt{}
+	actor.Deleted_by = nil
+	return actor, nil
 }
 
-func (repo Actor) LoginActor(actor *entity.Actor) (*entity.Actor, error) {
-	err := repo.db.Model(&entity.Actor{}).Where("username = ? AND password = ?", actor.Username, actor.Password).Error
-	return actor, err
+// PurgeActors permanently delete actors trashed before deletedBefore with
+// their own registration approvals, approvals they decided for others are
+// kept without the decider
+func (repo Actor) PurgeActors(deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Unscoped().Model(&entity.Actor{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore.UTC()).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		err = tx.Where("admin_id IN ?", ids).Delete(&entity.RegisterApproval{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entity.RegisterApproval{}).
+			Where("super_admin_id IN ?", ids).
+			Update("super_admin_id", nil).
+			Error
+		if err != nil {
+			return err
+		}
+		res := tx.Unscoped().Where("id IN ?", ids).Delete(&entity.Actor{})
+		purged = res.RowsAffected
+		return res.Error
+	})
+	return purged, err
+}
+
+// GetActorByUsername get single Actor by username
+func (repo Actor) GetActorByUsername(username string) (entity.Actor, error) {
+	var actor entity.Actor
+	err := repo.db.First(&actor, "username = ?", username).Error
+	return actor, translateError(err, "actor")
 }
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedActor(t *testing.T, repo Actor, username string) entity.Actor {
+	t.Helper()
+	actor, err := repo.CreateActor(&entity.Actor{
+		Username: username,
+		Password: "hashed",
+		Role_id:  2,
+	})
+	require.NoError(t, err)
+	return *actor
+}
+
+func TestActor_CreateAddsPendingApproval(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewActor(dbCrud)
+
+	actor := seedActor(t, repo, "admin1")
+
+	assert.NotZero(t, actor.ID)
+	assert.False(t, actor.CreatedAt.IsZero())
+	approvals, err := NewApproval(dbCrud).GetApprovals(entity.ApprovalStatusPending)
+	require.NoError(t, err)
+	require.Len(t, approvals, 1)
+	assert.Equal(t, actor.ID, approvals[0].Admin_id)
+	assert.Equal(t, "admin1", approvals[0].Admin.Username)
+}
+
+func TestActor_CreateDuplicateUsername(t *testing.T) {
+	repo := NewActor(newTestDB(t))
+	seedActor(t, repo, "admin1")
+
+	_, err := repo.CreateActor(&entity.Actor{Username: "admin1", Password: "hashed", Role_id: 2})
+
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+}
+
+func TestActor_GetByUsername(t *testing.T) {
+	repo := NewActor(newTestDB(t))
+
+	superAdmin, err := repo.GetActorByUsername("superadmin")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), superAdmin.Role_id)
+	assert.Equal(t, 1, superAdmin.Active)
+
+	_, err = repo.GetActorByUsername("nobody")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestActor_UpdateAndDelete(t *testing.T) {
+	repo := NewActor(newTestDB(t))
+	actor := seedActor(t, repo, "admin1")
+
+	_, err := repo.UpdateActor(&entity.Actor{Username: "admin2"}, actor.ID)
+	require.NoError(t, err)
+	found, err := repo.GetActorById(actor.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "admin2", found.Username)
+
+	_, err = repo.UpdateActor(&entity.Actor{Username: "admin3"}, 42)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	_, err = repo.DeleteActor("admin2", 1)
+	require.NoError(t, err)
+	_, err = repo.GetActorById(actor.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestActor_TrashRestoreAndPurge(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewActor(dbCrud)
+	actor := seedActor(t, repo, "admin1")
+	require.NoError(t, dbCrud.Create(&entity.Session{ID: "s1", Actor_id: actor.ID}).Error)
+
+	_, err := repo.DeleteActor("admin1", 1)
+	require.NoError(t, err)
+
+	_, err = repo.GetActorByUsername("admin1")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	session, err := NewSession(dbCrud).GetSessionById("s1")
+	require.NoError(t, err)
+	assert.NotNil(t, session.RevokedAt)
+	approvals, err := NewApproval(dbCrud).GetApprovals(entity.ApprovalStatusPending)
+	require.NoError(t, err)
+	assert.Empty(t, approvals)
+
+	trash, total, err := repo.ListDeletedActors(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, trash, 1)
+	assert.Equal(t, uint(1), *trash[0].Deleted_by)
+
+	restored, err := repo.RestoreActor("admin1")
+	require.NoError(t, err)
+	assert.Equal(t, actor.ID, restored.ID)
+	_, err = repo.GetActorByUsername("admin1")
+	assert.NoError(t, err)
+	_, err = repo.RestoreActor("admin1")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	_, err = repo.DeleteActor("admin1", 1)
+	require.NoError(t, err)
+	purged, err := repo.PurgeActors(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	purged, err = repo.PurgeActors(time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	_, total, err = repo.ListDeletedActors(10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	var approvalCount int64
+	require.NoError(t, dbCrud.Model(&entity.RegisterApproval{}).Where("admin_id = ?", actor.ID).Count(&approvalCount).Error)
+	assert.Zero(t, approvalCount)
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"gorm.io/gorm"
+)
+
+var ErrApprovalNotPending = apperror.Conflict("registration approval has already been decided", nil)
+
+type Approval struct {
+	db *gorm.DB
+}
+
+func NewApproval(dbCrud *gorm.DB) Approval {
+	return Approval{
+		db: dbCrud,
+	}
+}
+
+type ApprovalInterfaceRepo interface {
+	GetApprovals(status string) ([]entity.RegisterApproval, error)
+	GetApprovalById(id uint) (entity.RegisterApproval, error)
+	DecideApproval(approval *entity.RegisterApproval) error
+}
+
+// GetApprovals list registration approvals with the given status, those of
+// actors in the trash are left out
+func (repo Approval) GetApprovals(status string) ([]entity.RegisterApproval, error) {
+	var approvals []entity.RegisterApproval
+	err := repo.db.Preload("Admin").
+		Where("status = ?", status).
+		Where("admin_id IN (?)", repo.activeActorIds()).
+		Order("created_at").
+		Find(&approvals).
+		Error
+	return approvals, err
+}
+
+// GetApprovalById get single registration approval by id
+func (repo Approval) GetApprovalById(id uint) (entity.RegisterApproval, error) {
+	var approval entity.RegisterApproval
+	err := repo.db.Preload("Admin").
+		Where("admin_id IN (?)", repo.activeActorIds()).
+		First(&approval, "id = ?", id).Error
+	return approval, translateError(err, "registration approval")
+}
+
+// activeActorIds subquery of actors that are not in the trash
+func (repo Approval) activeActorIds() *gorm.DB {
+	return repo.db.Model(&entity.Actor{}).Select("id")
+}
+
+// DecideApproval store the decision and, when approved, verify and activate
+// the registered actor in the same transaction
+func (repo Approval) DecideApproval(approval *entity.RegisterApproval) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.RegisterApproval{}).
+			Where("id = ? AND status = ?", approval.ID, entity.ApprovalStatusPending).
+			Updates(map[string]any{
+				"status":         approval.Status,
+				"super_admin_id": approval.Super_admin_id,
+				"decided_at":     approval.DecidedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		// someone else decided between our read and this update
+		if res.RowsAffected == 0 {
+			return ErrApprovalNotPending
+		}
+
+		if approval.Status != entity.ApprovalStatusApproved {
+			return nil
+		}
+		return tx.Model(&entity.Actor{}).
+			Where("id = ?", approval.Admin_id).
+			Updates(map[string]any{
+				"verified": 1,
+				"active":   1,
+			}).
+			Error
+	})
+}
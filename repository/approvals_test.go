@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproval_DecideApproved(t *testing.T) {
+	dbCrud := newTestDB(t)
+	actorRepo := NewActor(dbCrud)
+	repo := NewApproval(dbCrud)
+	actor := seedActor(t, actorRepo, "admin1")
+	pending, err := repo.GetApprovals(entity.ApprovalStatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	superAdminId := uint(1)
+	decidedAt := time.Now().UTC()
+	approval := pending[0]
+	approval.Status = entity.ApprovalStatusApproved
+	approval.Super_admin_id = &superAdminId
+	approval.DecidedAt = &decidedAt
+	require.NoError(t, repo.DecideApproval(&approval))
+
+	decided, err := repo.GetApprovalById(approval.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.ApprovalStatusApproved, decided.Status)
+	assert.Equal(t, &superAdminId, decided.Super_admin_id)
+	assert.NotNil(t, decided.DecidedAt)
+
+	found, err := actorRepo.GetActorById(actor.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.Verified)
+	assert.Equal(t, 1, found.Active)
+
+	// a second decision on the same registration is refused
+	approval.Status = entity.ApprovalStatusRejected
+	assert.ErrorIs(t, repo.DecideApproval(&approval), ErrApprovalNotPending)
+}
+
+func TestApproval_DecideRejected(t *testing.T) {
+	dbCrud := newTestDB(t)
+	actorRepo := NewActor(dbCrud)
+	repo := NewApproval(dbCrud)
+	actor := seedActor(t, actorRepo, "admin1")
+	pending, err := repo.GetApprovals(entity.ApprovalStatusPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	approval := pending[0]
+	approval.Status = entity.ApprovalStatusRejected
+	require.NoError(t, repo.DecideApproval(&approval))
+
+	found, err := actorRepo.GetActorById(actor.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, found.Verified)
+	assert.Equal(t, 0, found.Active)
+	rejected, err := repo.GetApprovals(entity.ApprovalStatusRejected)
+	require.NoError(t, err)
+	assert.Len(t, rejected, 1)
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"gorm.io/gorm"
+)
+
+// AuditFilter zero fields match every entry, From and To keep entries
+// written at or after From and before To
+type AuditFilter struct {
+	Actor_id    uint
+	Action      string
+	Entity_type string
+	Entity_id   uint
+	Request_id  string
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+}
+
+// auditSource table the snapshot of an audited entity type is read from,
+// omit lists columns that never reach the log
+type auditSource struct {
+	table string
+	omit  []string
+}
+
+var auditSources = map[string]auditSource{
+	"account":  {table: "account"},
+	"activity": {table: "activity"},
+	"actor":    {table: "actors", omit: []string{"password"}},
+	"approval": {table: "register_approval"},
+	"customer": {table: "customer", omit: []string{"email_normalized"}},
+	"deal":     {table: "deal"},
+	"field":    {table: "custom_field"},
+	"segment":  {table: "segment"},
+	"tag":      {table: "tag"},
+	"task":     {table: "task"},
+}
+
+type AuditLog struct {
+	db *gorm.DB
+}
+
+func NewAuditLog(dbCrud *gorm.DB) AuditLog {
+	return AuditLog{
+		db: dbCrud,
+	}
+}
+
+// AuditInterfaceRepo entries can be added and read, never changed
+type AuditInterfaceRepo interface {
+	CreateAuditLog(audit *entity.AuditLog) error
+	ListAuditLogs(filter AuditFilter) ([]entity.AuditLog, int64, error)
+	Snapshot(entityType string, column string, value any) (uint, map[string]any, error)
+}
+
+// CreateAuditLog append an entry to the audit log
+func (repo AuditLog) CreateAuditLog(audit *entity.AuditLog) error {
+	return repo.db.Create(audit).Error
+}
+
+// ListAuditLogs page of entries, the latest first
+func (repo AuditLog) ListAuditLogs(filter AuditFilter) ([]entity.AuditLog, int64, error) {
+	query := repo.db.Model(&entity.AuditLog{})
+	if filter.Actor_id != 0 {
+		query = query.Where("actor_id = ?", filter.Actor_id)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Entity_type != "" {
+		query = query.Where("entity_type = ?", filter.Entity_type)
+	}
+	if filter.Entity_id != 0 {
+		query = query.Where("entity_id = ?", filter.Entity_id)
+	}
+	if filter.Request_id != "" {
+		query = query.Where("request_id = ?", filter.Request_id)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", filter.To.UTC())
+	}
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var audits []entity.AuditLog
+	err = query.Order("id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&audits).Error
+	return audits, total, err
+}
+
+// Snapshot id and columns of the entityType record whose column holds value,
+// trashed records included. A customer also lists its tag ids and custom
+// field values, an account the ids of its contacts
+func (repo AuditLog) Snapshot(entityType string, column string, value any) (uint, map[string]any, error) {
+	source, ok := auditSources[entityType]
+	if !ok {
+		return 0, nil, fmt.Errorf("no audit source for %q", entityType)
+	}
+	row := map[string]any{}
+	res := repo.db.Table(source.table).Where(column+" = ?", value).Limit(1).Find(&row)
+	if res.Error != nil {
+		return 0, nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return 0, nil, apperror.NotFound(entityType + " not found")
+	}
+	for _, omit := range source.omit {
+		delete(row, omit)
+	}
+	for key, value := range row {
+		if raw, ok := value.([]byte); ok {
+			row[key] = string(raw)
+		}
+	}
+	var id uint
+	_, err := fmt.Sscan(fmt.Sprint(row["id"]), &id)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s id %v: %w", entityType, row["id"], err)
+	}
+
+	switch entityType {
+	case "customer":
+		tagIds := []uint{}
+		err = repo.db.Model(&entity.CustomerTag{}).Where("customer_id = ?", id).
+			Order("tag_id").Pluck("tag_id", &tagIds).Error
+		if err != nil {
+			return 0, nil, err
+		}
+		row["tags"] = tagIds
+
+		var values []struct {
+			Name   string
+			Value  string
+			Number *float64
+		}
+		err = repo.db.Model(&entity.CustomFieldValue{}).
+			Select("custom_field.name, custom_field_value.value, custom_field_value.number").
+			Joins("JOIN custom_field ON custom_field.id = custom_field_value.field_id").
+			Where("custom_field_value.customer_id = ?", id).
+			Scan(&values).Error
+		if err != nil {
+			return 0, nil, err
+		}
+		fields := make(map[string]any, len(values))
+		for _, value := range values {
+			fields[value.Name] = value.Value
+			if value.Number != nil {
+				fields[value.Name] = *value.Number
+			}
+		}
+		row["custom_fields"] = fields
+	case "account":
+		contactIds := []uint{}
+		err = repo.db.Model(&entity.Customer{}).Where("account_id = ?", id).
+			Order("id").Pluck("id", &contactIds).Error
+		if err != nil {
+			return 0, nil, err
+		}
+		row["contacts"] = contactIds
+	}
+	return id, row, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog_Snapshot(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewAuditLog(dbCrud)
+	tagRepo := NewTag(dbCrud)
+	customer := seedCustomers(t, NewCustomer(dbCrud), "john")[0]
+	tag := entity.Tag{Name: "vip"}
+	require.NoError(t, tagRepo.CreateTag(&tag))
+	require.NoError(t, tagRepo.AddCustomerTags(customer.ID, []uint{tag.ID}))
+
+	id, snapshot, err := repo.Snapshot("customer", "id", customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, customer.ID, id)
+	assert.Equal(t, "john", snapshot["first_name"])
+	assert.Equal(t, []uint{tag.ID}, snapshot["tags"])
+	assert.Equal(t, map[string]any{}, snapshot["custom_fields"])
+	assert.NotContains(t, snapshot, "email_normalized")
+
+	actor := seedActor(t, NewActor(dbCrud), "alice")
+	id, snapshot, err = repo.Snapshot("actor", "username", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, actor.ID, id)
+	assert.NotContains(t, snapshot, "password")
+
+	_, _, err = repo.Snapshot("customer", "id", 999)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestAuditLog_ListAuditLogs(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewAuditLog(dbCrud)
+	actor := seedActor(t, NewActor(dbCrud), "alice")
+	start := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	for i, audit := range []entity.AuditLog{
+		{Actor_id: &actor.ID, Action: entity.AuditActionCreate, Entity_type: "customer", Entity_id: 1, Request_id: "r1"},
+		{Actor_id: &actor.ID, Action: entity.AuditActionUpdate, Entity_type: "customer", Entity_id: 1, Request_id: "r2"},
+		{Action: entity.AuditActionCreate, Entity_type: "tag", Entity_id: 1, Request_id: "r3"},
+	} {
+		audit.CreatedAt = start.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, repo.CreateAuditLog(&audit))
+	}
+
+	audits, total, err := repo.ListAuditLogs(AuditFilter{Actor_id: actor.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "r2", audits[0].Request_id)
+
+	from, to := start.Add(30*time.Minute), start.Add(2*time.Hour)
+	audits, total, err = repo.ListAuditLogs(AuditFilter{Entity_type: "customer", From: &from, To: &to, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, entity.AuditActionUpdate, audits[0].Action)
+
+	_, total, err = repo.ListAuditLogs(AuditFilter{Request_id: "r3", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestAuditLog_AppendOnly(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewAuditLog(dbCrud)
+	audit := entity.AuditLog{Action: entity.AuditActionCreate, Entity_type: "tag", Entity_id: 1}
+	require.NoError(t, repo.CreateAuditLog(&audit))
+
+	err := dbCrud.Model(&entity.AuditLog{}).Where("id = ?", audit.ID).Update("action", "delete").Error
+	assert.ErrorContains(t, err, "append-only")
+	err = dbCrud.Delete(&entity.AuditLog{}, audit.ID).Error
+	assert.ErrorContains(t, err, "append-only")
+}
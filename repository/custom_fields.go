@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CustomField struct {
+	db *gorm.DB
+}
+
+func NewCustomField(dbCrud *gorm.DB) CustomField {
+	return CustomField{
+		db: dbCrud,
+	}
+}
+
+type CustomFieldInterfaceRepo interface {
+	CreateCustomField(field *entity.CustomField) error
+	GetCustomFieldById(id uint) (entity.CustomField, error)
+	ListCustomFields() ([]entity.CustomField, error)
+	UpdateCustomField(field *entity.CustomField, id uint) error
+	DeleteCustomField(id uint) error
+	SetCustomFieldValues(customerId uint, values []entity.CustomFieldValue, clearFieldIds []uint) error
+}
+
+// CreateCustomField new CustomField
+func (repo CustomField) CreateCustomField(field *entity.CustomField) error {
+	err := repo.db.Create(field).Error
+	return translateError(err, "custom field")
+}
+
+// GetCustomFieldById get single CustomField by id
+func (repo CustomField) GetCustomFieldById(id uint) (entity.CustomField, error) {
+	var field entity.CustomField
+	err := repo.db.First(&field, "id = ?", id).Error
+	return field, translateError(err, "custom field")
+}
+
+// ListCustomFields every field in the order they were defined
+func (repo CustomField) ListCustomFields() ([]entity.CustomField, error) {
+	var fields []entity.CustomField
+	err := repo.db.Order("id").Find(&fields).Error
+	return fields, err
+}
+
+// UpdateCustomField label, required and rules, the name and type of a field
+// never change
+func (repo CustomField) UpdateCustomField(field *entity.CustomField, id uint) error {
+	res := repo.db.Model(&entity.CustomField{}).Where("id = ?", id).
+		Select("label", "required", "rules", "updated_at").
+		Updates(field)
+	return affectedOrNotFound(res, &entity.CustomField{}, "custom field", "id = ?", id)
+}
+
+// DeleteCustomField the values of every customer go with it
+func (repo CustomField) DeleteCustomField(id uint) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("field_id = ?", id).Delete(&entity.CustomFieldValue{}).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Delete(&entity.CustomField{}, id)
+		return affectedOrNotFound(res, &entity.CustomField{}, "custom field", "id = ?", id)
+	})
+}
+
+// SetCustomFieldValues in one transaction save values, replacing what the
+// customer had for those fields, and remove the values of clearFieldIds
+func (repo CustomField) SetCustomFieldValues(customerId uint, values []entity.CustomFieldValue, clearFieldIds []uint) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if len(clearFieldIds) > 0 {
+			err := tx.Where("customer_id = ? AND field_id IN ?", customerId, clearFieldIds).
+				Delete(&entity.CustomFieldValue{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		now := time.Now()
+		for i := range values {
+			values[i].Customer_id = customerId
+			values[i].UpdatedAt = now
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "customer_id"}, {Name: "field_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "number", "updated_at"}),
+		}).Create(&values).Error
+		return translateError(err, "custom field value")
+	})
+}
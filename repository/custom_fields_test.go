@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedCustomFields(t *testing.T, repo CustomField, fields ...entity.CustomField) []entity.CustomField {
+	t.Helper()
+	for i := range fields {
+		require.NoError(t, repo.CreateCustomField(&fields[i]))
+	}
+	return fields
+}
+
+func numberValue(fieldId uint, number float64) entity.CustomFieldValue {
+	return entity.CustomFieldValue{Field_id: fieldId, Value: "", Number: &number}
+}
+
+func TestCustomField_CRUD(t *testing.T) {
+	repo := NewCustomField(newTestDB(t))
+	max := 5.0
+	field := seedCustomFields(t, repo, entity.CustomField{
+		Name:  "rating",
+		Label: "Rating",
+		Type:  entity.FieldTypeNumber,
+		Rules: entity.CustomFieldRules{Max: &max, Integer: true},
+	})[0]
+
+	found, err := repo.GetCustomFieldById(field.ID)
+	require.NoError(t, err)
+	assert.Equal(t, field.Rules, found.Rules)
+	assert.ErrorIs(t, repo.CreateCustomField(&entity.CustomField{Name: "rating", Type: entity.FieldTypeString}), apperror.ErrConflict)
+
+	err = repo.UpdateCustomField(&entity.CustomField{Label: "Score", Required: true}, field.ID)
+	require.NoError(t, err)
+	fields, err := repo.ListCustomFields()
+	require.NoError(t, err)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "Score", fields[0].Label)
+	assert.True(t, fields[0].Required)
+	assert.Equal(t, entity.CustomFieldRules{}, fields[0].Rules)
+
+	require.NoError(t, repo.DeleteCustomField(field.ID))
+	assert.ErrorIs(t, repo.DeleteCustomField(field.ID), apperror.ErrNotFound)
+	assert.ErrorIs(t, repo.UpdateCustomField(&entity.CustomField{Label: "x"}, field.ID), apperror.ErrNotFound)
+}
+
+func TestCustomField_SetCustomFieldValues(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomField(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	customer := seedCustomers(t, customerRepo, "john")[0]
+	fields := seedCustomFields(t, repo,
+		entity.CustomField{Name: "plan", Type: entity.FieldTypeEnum},
+		entity.CustomField{Name: "seats", Type: entity.FieldTypeNumber},
+	)
+
+	require.NoError(t, repo.SetCustomFieldValues(customer.ID, []entity.CustomFieldValue{
+		{Field_id: fields[0].ID, Value: "pro"},
+		numberValue(fields[1].ID, 3),
+	}, nil))
+	require.NoError(t, repo.SetCustomFieldValues(customer.ID, []entity.CustomFieldValue{
+		{Field_id: fields[0].ID, Value: "team"},
+	}, []uint{fields[1].ID}))
+
+	found, err := customerRepo.GetCustomerById(customer.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Field_values, 1)
+	assert.Equal(t, "team", found.Field_values[0].Value)
+
+	// deleting the field takes its values along
+	require.NoError(t, repo.DeleteCustomField(fields[0].ID))
+	found, err = customerRepo.GetCustomerById(customer.ID)
+	require.NoError(t, err)
+	assert.Empty(t, found.Field_values)
+}
+
+func TestCustomer_ImportCustomersCustomFields(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomField(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	john := seedCustomers(t, customerRepo, "john")[0]
+	fields := seedCustomFields(t, repo,
+		entity.CustomField{Name: "plan", Type: entity.FieldTypeEnum},
+		entity.CustomField{Name: "seats", Type: entity.FieldTypeNumber},
+	)
+	require.NoError(t, repo.SetCustomFieldValues(john.ID, []entity.CustomFieldValue{
+		{Field_id: fields[0].ID, Value: "pro"},
+	}, nil))
+
+	jane := &entity.Customer{First_name: "jane", Email: "jane@example.com", Field_values: []entity.CustomFieldValue{
+		{Field_id: fields[0].ID, Value: "team"},
+	}}
+	err := customerRepo.ImportCustomers([]*entity.Customer{jane}, []*entity.Customer{
+		{ID: john.ID, First_name: "Johnny", Field_values: []entity.CustomFieldValue{numberValue(fields[1].ID, 4)}},
+	})
+	require.NoError(t, err)
+
+	found, err := customerRepo.GetCustomerById(jane.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Field_values, 1)
+	assert.Equal(t, "team", found.Field_values[0].Value)
+	// the update keeps the plan it does not hold
+	found, err = customerRepo.GetCustomerById(john.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Field_values, 2)
+}
+
+func TestCustomer_ListByCustomFields(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	fieldRepo := NewCustomField(dbCrud)
+	seeded := seedCustomers(t, repo, "john", "jane", "jack")
+	fields := seedCustomFields(t, fieldRepo,
+		entity.CustomField{Name: "plan", Type: entity.FieldTypeEnum},
+		entity.CustomField{Name: "seats", Type: entity.FieldTypeNumber},
+		entity.CustomField{Name: "company", Type: entity.FieldTypeString},
+	)
+	plan, seats, company := fields[0].ID, fields[1].ID, fields[2].ID
+	require.NoError(t, fieldRepo.SetCustomFieldValues(seeded[0].ID, []entity.CustomFieldValue{
+		{Field_id: plan, Value: "pro"}, numberValue(seats, 5), {Field_id: company, Value: "Acme Corp"},
+	}, nil))
+	require.NoError(t, fieldRepo.SetCustomFieldValues(seeded[1].ID, []entity.CustomFieldValue{
+		{Field_id: plan, Value: "free"}, numberValue(seats, 50),
+	}, nil))
+	require.NoError(t, fieldRepo.SetCustomFieldValues(seeded[2].ID, []entity.CustomFieldValue{
+		{Field_id: plan, Value: "team"}, numberValue(seats, 12), {Field_id: company, Value: "acme labs"},
+	}, nil))
+	sort := []SortField{{Column: "id"}}
+	min, max := 10.0, 20.0
+
+	tests := []struct {
+		name   string
+		fields []FieldCondition
+		want   []uint
+	}{
+		{name: "enum values", fields: []FieldCondition{{Field_id: plan, Values: []string{"pro", "team"}}}, want: []uint{seeded[0].ID, seeded[2].ID}},
+		{name: "number range", fields: []FieldCondition{{Field_id: seats, MinNumber: &min, MaxNumber: &max}}, want: []uint{seeded[2].ID}},
+		{name: "number from", fields: []FieldCondition{{Field_id: seats, MinNumber: &min}}, want: []uint{seeded[1].ID, seeded[2].ID}},
+		{name: "contains ignoring case", fields: []FieldCondition{{Field_id: company, Contains: "ACME"}}, want: []uint{seeded[0].ID, seeded[2].ID}},
+		{
+			name: "every condition",
+			fields: []FieldCondition{
+				{Field_id: company, Contains: "acme"},
+				{Field_id: seats, MaxNumber: &max},
+				{Field_id: plan, Values: []string{"team"}},
+			},
+			want: []uint{seeded[2].ID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customers, total, err := repo.ListCustomers(CustomerFilter{Fields: tt.fields, Sort: sort, Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), total)
+			assert.Equal(t, tt.want, customerIds(customers))
+		})
+	}
+}
+
+func TestCustomer_MergeCustomersKeepsCustomFields(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	fieldRepo := NewCustomField(dbCrud)
+	seeded := seedCustomers(t, repo, "john", "jon")
+	fields := seedCustomFields(t, fieldRepo,
+		entity.CustomField{Name: "plan", Type: entity.FieldTypeEnum},
+		entity.CustomField{Name: "company", Type: entity.FieldTypeString},
+	)
+	require.NoError(t, fieldRepo.SetCustomFieldValues(seeded[0].ID, []entity.CustomFieldValue{
+		{Field_id: fields[0].ID, Value: "pro"},
+	}, nil))
+	require.NoError(t, fieldRepo.SetCustomFieldValues(seeded[1].ID, []entity.CustomFieldValue{
+		{Field_id: fields[0].ID, Value: "free"}, {Field_id: fields[1].ID, Value: "Acme"},
+	}, nil))
+
+	survivor, err := repo.GetCustomerById(seeded[0].ID)
+	require.NoError(t, err)
+	err = repo.MergeCustomers(&survivor, []uint{seeded[1].ID}, &entity.AuditLog{Action: entity.AuditActionMerge, Entity_type: "customer"})
+	require.NoError(t, err)
+
+	// the survivor keeps its own value and takes the ones it lacked
+	found, err := repo.GetCustomerById(survivor.ID)
+	require.NoError(t, err)
+	values := map[uint]string{}
+	for _, value := range found.Field_values {
+		values[value.Field_id] = value.Value
+	}
+	assert.Equal(t, map[uint]string{fields[0].ID: "pro", fields[1].ID: "Acme"}, values)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"gorm.io/gorm"
+)
+
+type Duplicate struct {
+	db *gorm.DB
+}
+
+func NewDuplicate(dbCrud *gorm.DB) Duplicate {
+	return Duplicate{
+		db: dbCrud,
+	}
+}
+
+type DuplicateInterfaceRepo interface {
+	SaveDuplicateScan(scan *entity.DuplicateScan, pairs []entity.CustomerDuplicate) error
+	GetLatestDuplicateScan() (entity.DuplicateScan, error)
+	ListCustomerDuplicates(scanId uint, minScore float64, limit int, offset int) ([]entity.CustomerDuplicate, int64, error)
+}
+
+// SaveDuplicateScan store scan with its pairs and drop the earlier scans
+func (repo Duplicate) SaveDuplicateScan(scan *entity.DuplicateScan, pairs []entity.CustomerDuplicate) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(scan).Error
+		if err != nil {
+			return err
+		}
+		for i := range pairs {
+			pairs[i].Scan_id = scan.ID
+		}
+		if len(pairs) > 0 {
+			err = tx.Omit("Customer", "Other").CreateInBatches(pairs, 500).Error
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Where("scan_id < ?", scan.ID).Delete(&entity.CustomerDuplicate{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id < ?", scan.ID).Delete(&entity.DuplicateScan{}).Error
+	})
+}
+
+// GetLatestDuplicateScan the scan whose pairs are listed
+func (repo Duplicate) GetLatestDuplicateScan() (entity.DuplicateScan, error) {
+	var scan entity.DuplicateScan
+	err := repo.db.Order("id DESC").First(&scan).Error
+	return scan, translateError(err, "duplicate scan")
+}
+
+// ListCustomerDuplicates page of the pairs of a scan scoring at least
+// minScore or sharing their email, best first. Pairs with a customer that
+// has been deleted since the scan are left out
+func (repo Duplicate) ListCustomerDuplicates(scanId uint, minScore float64, limit int, offset int) ([]entity.CustomerDuplicate, int64, error) {
+	query := repo.db.Model(&entity.CustomerDuplicate{}).
+		Joins("JOIN customer ON customer.id = customer_duplicate.customer_id AND customer.deleted_at IS NULL").
+		Joins("JOIN customer other ON other.id = customer_duplicate.other_id AND other.deleted_at IS NULL").
+		Where("customer_duplicate.scan_id = ?", scanId).
+		Where("(customer_duplicate.score >= ? OR customer_duplicate.email_score = 1)", minScore)
+
+	var total int64
+	err := query.Session(&gorm.Session{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var pairs []entity.CustomerDuplicate
+	err = query.Preload("Customer").Preload("Other").
+		Order("customer_duplicate.score DESC, customer_duplicate.customer_id, customer_duplicate.other_id").
+		Limit(limit).Offset(offset).
+		Find(&pairs).Error
+	return pairs, total, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicate_ScanAndList(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewDuplicate(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	seeded := seedCustomers(t, customerRepo, "john", "jon", "johnny", "jane")
+
+	_, err := repo.GetLatestDuplicateScan()
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	first := entity.DuplicateScan{Customers: 4, Pairs: 1}
+	require.NoError(t, repo.SaveDuplicateScan(&first, []entity.CustomerDuplicate{
+		{Customer_id: seeded[0].ID, Other_id: seeded[3].ID, Score: 0.9},
+	}))
+	scan := entity.DuplicateScan{Customers: 4, Pairs: 3, Truncated_blocks: 1}
+	require.NoError(t, repo.SaveDuplicateScan(&scan, []entity.CustomerDuplicate{
+		{Customer_id: seeded[0].ID, Other_id: seeded[1].ID, Score: 0.95, Reasons: "similar name"},
+		{Customer_id: seeded[0].ID, Other_id: seeded[2].ID, Score: 0.85},
+		{Customer_id: seeded[1].ID, Other_id: seeded[2].ID, Score: 0.75, Email_score: 1},
+		{Customer_id: seeded[2].ID, Other_id: seeded[3].ID, Score: 0.72},
+	}))
+
+	latest, err := repo.GetLatestDuplicateScan()
+	require.NoError(t, err)
+	assert.Equal(t, scan.ID, latest.ID)
+	assert.Equal(t, 1, latest.Truncated_blocks)
+	var count int64
+	require.NoError(t, dbCrud.Model(&entity.CustomerDuplicate{}).Where("scan_id = ?", first.ID).Count(&count).Error)
+	assert.Zero(t, count, "earlier scans are dropped")
+
+	// below min score but sharing the email
+	pairs, total, err := repo.ListCustomerDuplicates(scan.ID, 0.85, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, pairs, 2)
+	assert.Equal(t, seeded[1].ID, pairs[0].Other.ID)
+	assert.Equal(t, "jon", pairs[0].Other.First_name)
+	assert.Equal(t, "john", pairs[0].Customer.First_name)
+	pairs, _, err = repo.ListCustomerDuplicates(scan.ID, 0.85, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, 0.75, pairs[0].Score)
+
+	_, err = customerRepo.DeleteCustomer(seeded[1].ID, 1)
+	require.NoError(t, err)
+	pairs, total, err = repo.ListCustomerDuplicates(scan.ID, 0.7, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, pairs, 2)
+}
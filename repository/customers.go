@@ -1,10 +1,66 @@
 package repository
 
 import (
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// CustomerSortColumns columns a customer list may be sorted by
+var CustomerSortColumns = map[string]bool{
+	"id":         true,
+	"first_name": true,
+	"last_name":  true,
+	"email":      true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// CustomerFilter criteria for ListCustomers, After holds the Sort values of
+// the last row of the previous page for cursor pagination. Customers must
+// have every one of Tag_ids, match every custom field condition in Fields
+// and the criteria of a saved Segment too, a non zero Account_id keeps the
+// contacts of that account
+type CustomerFilter struct {
+	First_name  string
+	Last_name   string
+	Email       string
+	Account_id  uint
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Tag_ids     []uint
+	Fields      []FieldCondition
+	Segment     *CustomerFilter
+	Sort        []SortField
+	Limit       int
+	Offset      int
+	After       []any
+}
+
+// FieldCondition criteria on the value of one custom field, every part that
+// is set must hold: Values lists the accepted values, Contains a part of the
+// value ignoring case, the Number bounds compare number fields and the Text
+// bounds compare the value as text, which orders YYYY-MM-DD dates
+type FieldCondition struct {
+	Field_id  uint
+	Values    []string
+	Contains  string
+	MinNumber *float64
+	MaxNumber *float64
+	MinText   *string
+	MaxText   *string
+}
+
 type Customer struct {
 	db *gorm.DB
 }
@@ -19,34 +75,609 @@ type CustomerInterfaceRepo interface {
 	CreateCustomer(Customer *entity.Customer) (*entity.Customer, error)
 	GetCustomerById(id uint) (entity.Customer, error)
 	UpdateCustomer(customer *entity.Customer, id uint) (any, error)
-	DeleteCustomer(id uint) (any, error)
+	DeleteCustomer(id uint, deletedBy uint) (any, error)
+	ListCustomers(filter CustomerFilter) ([]entity.Customer, int64, error)
+	GetCustomerByEmail(email string) (entity.Customer, error)
+	EachCustomerBatch(size int, fn func(customers []entity.Customer) error) error
+	MergeCustomers(survivor *entity.Customer, victimIds []uint, audit *entity.AuditLog) error
+	GetCustomerRedirect(oldId uint) (entity.CustomerRedirect, error)
+	ListDeletedCustomers(limit int, offset int) ([]entity.Customer, int64, error)
+	RestoreCustomer(id uint) (entity.Customer, error)
+	PurgeCustomers(deletedBefore time.Time) ([]entity.Customer, error)
+	SetCustomerAvatar(id uint, avatar string, key *string) error
+	GetCustomersByEmails(emails []string) ([]entity.Customer, error)
+	ImportCustomers(creates []*entity.Customer, updates []*entity.Customer) error
+	ExportCustomers(filter CustomerFilter, fn func(customer entity.Customer) error) error
+	RevertCustomer(id uint, version uint) error
+	ListCustomerVersions(customerId uint, limit int, offset int) ([]entity.CustomerVersion, int64, error)
+	GetCustomerVersion(customerId uint, version uint) (entity.CustomerVersion, error)
+	GetCustomerVersionAt(customerId uint, at time.Time) (entity.CustomerVersion, error)
+}
+
+// NormalizeEmail form of an email that must be unique across customers
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 // CreateCustomer new Customer
 func (repo Customer) CreateCustomer(customer *entity.Customer) (*entity.Customer, error) {
+	if customer.Email != "" {
+		normalized := NormalizeEmail(customer.Email)
+		customer.Email_normalized = &normalized
+	}
 	err := repo.db.Model(&entity.Customer{}).Create(customer).Error
-	return customer, err
+	return customer, translateError(err, "customer")
 }
 
 // GetCustomerById get single Customer by id
 func (repo Customer) GetCustomerById(id uint) (entity.Customer, error) {
 	var customer entity.Customer
-	repo.db.First(&customer, "id = ? ", id)
-	return customer, nil
+	err := repo.db.Preload("Tags", orderTags).Preload("Field_values").
+		First(&customer, "id = ? ", id).Error
+	return customer, translateError(err, "customer")
 }
 
-// UpdateCustomer multiple fields
+// UpdateCustomer multiple fields, recording the new state as the next
+// version when a versioned field changed
 func (repo Customer) UpdateCustomer(customer *entity.Customer, id uint) (any, error) {
-	err := repo.db.Model(&entity.Customer{}).Where("id = ?", id).
-		Updates(customer).Error
-	return nil, err
+	if customer.Email != "" {
+		normalized := NormalizeEmail(customer.Email)
+		customer.Email_normalized = &normalized
+	}
+	return nil, repo.db.Transaction(func(tx *gorm.DB) error {
+		return updateVersioned(tx, id, nil, func(query *gorm.DB) *gorm.DB {
+			return query.Updates(customer)
+		})
+	})
+}
+
+// RevertCustomer set the versioned fields back to those of version, which
+// is recorded as the next version even when nothing changed
+func (repo Customer) RevertCustomer(id uint, version uint) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var target entity.CustomerVersion
+		err := tx.First(&target, "customer_id = ? AND version = ?", id, version).Error
+		if err != nil {
+			return translateError(err, "customer version")
+		}
+		fields := entity.Customer{
+			First_name: target.First_name,
+			Last_name:  target.Last_name,
+			Email:      target.Email,
+			Avatar:     target.Avatar,
+			Account_id: target.Account_id,
+			UpdatedAt:  time.Now(),
+		}
+		if fields.Email != "" {
+			normalized := NormalizeEmail(fields.Email)
+			fields.Email_normalized = &normalized
+		}
+		return updateVersioned(tx, id, &target.Version, func(query *gorm.DB) *gorm.DB {
+			return query.Select("first_name", "last_name", "email", "email_normalized", "avatar",
+				"account_id", "updated_at").Updates(&fields)
+		})
+	})
+}
+
+// updateVersioned run update on customer id and record the state it leaves
+// as the next version
+func updateVersioned(tx *gorm.DB, id uint, revertedFrom *uint, update func(query *gorm.DB) *gorm.DB) error {
+	var before entity.Customer
+	err := tx.First(&before, "id = ?", id).Error
+	if err != nil {
+		return translateError(err, "customer")
+	}
+	res := update(tx.Model(&entity.Customer{}).Where("id = ?", id))
+	err = affectedOrNotFound(res, &entity.Customer{}, "customer", "id = ?", id)
+	if err != nil {
+		return err
+	}
+	return recordVersions(tx, []entity.Customer{before}, revertedFrom)
+}
+
+// updateVersionedWhere set values on the customers query selects, trashed
+// ones included, and record the state each is left in as its next version.
+// The number of customers updated is returned
+func updateVersionedWhere(tx *gorm.DB, query *gorm.DB, values map[string]any) (int64, error) {
+	var before []entity.Customer
+	err := query.Unscoped().Find(&before).Error
+	if err != nil || len(before) == 0 {
+		return 0, err
+	}
+	ids := make([]uint, len(before))
+	for i, customer := range before {
+		ids[i] = customer.ID
+	}
+	values["updated_at"] = time.Now()
+	res := tx.Unscoped().Model(&entity.Customer{}).Where("id IN ?", ids).Updates(values)
+	if res.Error != nil {
+		return 0, translateError(res.Error, "customer")
+	}
+	return res.RowsAffected, recordVersions(tx, before, nil)
+}
+
+// recordVersions record the state the customers in before were left in by
+// an update as their next version, unless no versioned field changed. The
+// first recorded update of a customer also records the state before it, as
+// version 1
+func recordVersions(tx *gorm.DB, before []entity.Customer, revertedFrom *uint) error {
+	ids := make([]uint, len(before))
+	for i, customer := range before {
+		ids[i] = customer.ID
+	}
+	var after []entity.Customer
+	err := tx.Unscoped().Where("id IN ?", ids).Find(&after).Error
+	if err != nil {
+		return err
+	}
+	var latest []struct {
+		Customer_id uint
+		Version     uint
+	}
+	err = tx.Model(&entity.CustomerVersion{}).Where("customer_id IN ?", ids).
+		Select("customer_id, MAX(version) AS version").Group("customer_id").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+	latestOf := make(map[uint]uint, len(latest))
+	for _, row := range latest {
+		latestOf[row.Customer_id] = row.Version
+	}
+	afterOf := make(map[uint]entity.Customer, len(after))
+	for _, customer := range after {
+		afterOf[customer.ID] = customer
+	}
+
+	var versions []entity.CustomerVersion
+	for _, customer := range before {
+		previous, next := entity.NewCustomerVersion(customer), entity.NewCustomerVersion(afterOf[customer.ID])
+		if revertedFrom == nil && previous.SameFields(next) {
+			continue
+		}
+		version := latestOf[customer.ID]
+		if version == 0 {
+			version = 1
+			previous.Version = version
+			previous.CreatedAt = customer.UpdatedAt.UTC()
+			versions = append(versions, previous)
+		}
+		next.Version = version + 1
+		next.Reverted_from = revertedFrom
+		next.CreatedAt = afterOf[customer.ID].UpdatedAt.UTC()
+		versions = append(versions, next)
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	return translateError(tx.CreateInBatches(&versions, 500).Error, "customer version")
+}
+
+// ListCustomerVersions page of the versions of a customer, the latest first
+func (repo Customer) ListCustomerVersions(customerId uint, limit int, offset int) ([]entity.CustomerVersion, int64, error) {
+	query := repo.db.Model(&entity.CustomerVersion{}).Where("customer_id = ?", customerId)
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var versions []entity.CustomerVersion
+	err = query.Order("version DESC").Limit(limit).Offset(offset).Find(&versions).Error
+	return versions, total, err
+}
+
+// GetCustomerVersion get single version of a customer
+func (repo Customer) GetCustomerVersion(customerId uint, version uint) (entity.CustomerVersion, error) {
+	var customerVersion entity.CustomerVersion
+	err := repo.db.First(&customerVersion, "customer_id = ? AND version = ?", customerId, version).Error
+	return customerVersion, translateError(err, "customer version")
+}
+
+// GetCustomerVersionAt version of a customer in effect at at, the latest one
+// recorded at or before it
+func (repo Customer) GetCustomerVersionAt(customerId uint, at time.Time) (entity.CustomerVersion, error) {
+	var customerVersion entity.CustomerVersion
+	err := repo.db.Where("customer_id = ? AND created_at <= ?", customerId, at.UTC()).
+		Order("version DESC").First(&customerVersion).Error
+	return customerVersion, translateError(err, "customer version")
+}
+
+// GetCustomerByEmail customer owning the normalized form of email
+func (repo Customer) GetCustomerByEmail(email string) (entity.Customer, error) {
+	var customer entity.Customer
+	err := repo.db.First(&customer, "email_normalized = ?", NormalizeEmail(email)).Error
+	return customer, translateError(err, "customer")
+}
+
+// GetCustomersByEmails customers owning the normalized form of any of emails
+func (repo Customer) GetCustomersByEmails(emails []string) ([]entity.Customer, error) {
+	normalized := make([]string, len(emails))
+	for i, email := range emails {
+		normalized[i] = NormalizeEmail(email)
+	}
+	var customers []entity.Customer
+	err := repo.db.Where("email_normalized IN ?", normalized).Find(&customers).Error
+	return customers, err
+}
+
+// ImportCustomers create and update customers in one transaction, updates
+// carry the id of the customer and leave empty fields and custom field
+// values they do not hold as they are
+func (repo Customer) ImportCustomers(creates []*entity.Customer, updates []*entity.Customer) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		txRepo := Customer{db: tx}
+		for _, customer := range creates {
+			_, err := txRepo.CreateCustomer(customer)
+			if err != nil {
+				return err
+			}
+		}
+		for _, customer := range updates {
+			fields := *customer
+			fields.ID = 0
+			fields.Field_values = nil
+			_, err := txRepo.UpdateCustomer(&fields, customer.ID)
+			if err != nil {
+				return err
+			}
+			err = CustomField{db: tx}.SetCustomFieldValues(customer.ID, customer.Field_values, nil)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EachCustomerBatch call fn with every customer in id order, size rows at a
+// time
+func (repo Customer) EachCustomerBatch(size int, fn func(customers []entity.Customer) error) error {
+	var customers []entity.Customer
+	return repo.db.Model(&entity.Customer{}).Order("id").
+		FindInBatches(&customers, size, func(tx *gorm.DB, batch int) error {
+			return fn(customers)
+		}).Error
+}
+
+// MergeCustomers in one transaction delete the victims, save the survivor's
+// merged values, redirect the victim ids to the survivor and write audit
+func (repo Customer) MergeCustomers(survivor *entity.Customer, victimIds []uint, audit *entity.AuditLog) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		// customers merged into a victim earlier now resolve to the survivor,
+		// before deleting the victim cascades their redirects away
+		err := tx.Model(&entity.CustomerRedirect{}).
+			Where("survivor_id IN ?", victimIds).
+			Update("survivor_id", survivor.ID).Error
+		if err != nil {
+			return err
+		}
+
+		// tags of the victims move to the survivor
+		var tagIds []uint
+		err = tx.Model(&entity.CustomerTag{}).Where("customer_id IN ?", victimIds).
+			Distinct().Pluck("tag_id", &tagIds).Error
+		if err != nil {
+			return err
+		}
+		err = addCustomerTags(tx, survivor.ID, tagIds)
+		if err != nil {
+			return err
+		}
+
+		// so do custom field values the survivor has no value for, the
+		// earliest victim in victimIds wins
+		var values []entity.CustomFieldValue
+		err = tx.Where("customer_id IN ?", victimIds).Find(&values).Error
+		if err != nil {
+			return err
+		}
+		position := make(map[uint]int, len(victimIds))
+		for i, id := range victimIds {
+			position[id] = i
+		}
+		sort.SliceStable(values, func(i, j int) bool {
+			return position[values[i].Customer_id] < position[values[j].Customer_id]
+		})
+		for _, value := range values {
+			value.Customer_id = survivor.ID
+			err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&value).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// activities, deals and tasks of the victims now belong to the survivor
+		err = tx.Model(&entity.Activity{}).Where("customer_id IN ?", victimIds).
+			Update("customer_id", survivor.ID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entity.Deal{}).Where("customer_id IN ?", victimIds).
+			Update("customer_id", survivor.ID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entity.Task{}).Where("customer_id IN ?", victimIds).
+			Update("customer_id", survivor.ID).Error
+		if err != nil {
+			return err
+		}
+
+		// victims go first so the survivor can take over one of their emails,
+		// they live on in the survivor and the audit entry, not the trash
+		res := tx.Unscoped().Where("id IN ? AND deleted_at IS NULL", victimIds).Delete(&entity.Customer{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != int64(len(victimIds)) {
+			return apperror.NotFound("customer not found")
+		}
+
+		normalized := NormalizeEmail(survivor.Email)
+		survivor.Email_normalized = &normalized
+		err = updateVersioned(tx, survivor.ID, nil, func(query *gorm.DB) *gorm.DB {
+			return query.Select("first_name", "last_name", "email", "email_normalized", "avatar", "avatar_key", "updated_at").
+				Updates(survivor)
+		})
+		if err != nil {
+			return err
+		}
+
+		redirects := make([]entity.CustomerRedirect, len(victimIds))
+		for i, id := range victimIds {
+			redirects[i] = entity.CustomerRedirect{Old_id: id, Survivor_id: survivor.ID}
+		}
+		err = tx.Create(&redirects).Error
+		if err != nil {
+			return translateError(err, "customer redirect")
+		}
+		return tx.Create(audit).Error
+	})
+}
+
+// GetCustomerRedirect survivor of a merged customer id
+func (repo Customer) GetCustomerRedirect(oldId uint) (entity.CustomerRedirect, error) {
+	var redirect entity.CustomerRedirect
+	err := repo.db.First(&redirect, "old_id = ?", oldId).Error
+	return redirect, translateError(err, "customer")
 }
 
-// DeleteCustomer by Id and email
-func (repo Customer) DeleteCustomer(id uint) (any, error) {
-	err := repo.db.Model(&entity.Customer{}).
+// DeleteCustomer move to the trash, the email is released so a new customer
+// can use it
+func (repo Customer) DeleteCustomer(id uint, deletedBy uint) (any, error) {
+	res := repo.db.Model(&entity.Customer{}).
 		Where("id = ?", id).
-		Delete(&entity.Customer{}).
-		Error
-	return nil, err
+		Updates(map[string]any{
+			"deleted_at":       time.Now().UTC(),
+			"deleted_by":       deletedBy,
+			"email_normalized": nil,
+		})
+	return nil, affectedOrNotFound(res, &entity.Customer{}, "customer", "id = ?", id)
+}
+
+// ListDeletedCustomers page of the trash, most recently deleted first
+func (repo Customer) ListDeletedCustomers(limit int, offset int) ([]entity.Customer, int64, error) {
+	var total int64
+	trash := repo.db.Unscoped().Model(&entity.Customer{}).Where("deleted_at IS NOT NULL")
+	err := trash.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var customers []entity.Customer
+	err = trash.Order("deleted_at DESC").Order("id").
+		Limit(limit).Offset(offset).
+		Find(&customers).Error
+	return customers, total, err
+}
+
+// RestoreCustomer take a customer out of the trash, the customer is returned
+// with a conflict when its email was taken in the meantime
+func (repo Customer) RestoreCustomer(id uint) (entity.Customer, error) {
+	var customer entity.Customer
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().First(&customer, "id = ? AND deleted_at IS NOT NULL", id).Error
+		if err != nil {
+			return translateError(err, "deleted customer")
+		}
+
+		var normalized *string
+		if customer.Email != "" {
+			email := NormalizeEmail(customer.Email)
+			normalized = &email
+		}
+		return tx.Unscoped().Model(&entity.Customer{}).
+			Where("id = ?", id).
+			Updates(map[string]any{
+				"deleted_at":       nil,
+				"deleted_by":       nil,
+				"email_normalized": normalized,
+			}).Error
+	})
+	if err != nil {
+		return customer, translateError(err, "customer")
+	}
+	customer.Deleted_at = gorm.DeletedAt{}
+	customer.Deleted_by = nil
+	customer.Email_normalized = nil
+	return customer, nil
+}
+
+// PurgeCustomers permanently delete customers trashed before deletedBefore,
+// the purged customers are returned so their files can go too
+func (repo Customer) PurgeCustomers(deletedBefore time.Time) ([]entity.Customer, error) {
+	var purged []entity.Customer
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore.UTC()).
+			Find(&purged).Error
+		if err != nil || len(purged) == 0 {
+			return err
+		}
+		ids := make([]uint, len(purged))
+		for i, customer := range purged {
+			ids[i] = customer.ID
+		}
+		return tx.Unscoped().Delete(&entity.Customer{}, ids).Error
+	})
+	return purged, err
+}
+
+// SetCustomerAvatar point avatar at an upload stored under key, a nil key
+// leaves only the link. A new avatar is recorded as the next version
+func (repo Customer) SetCustomerAvatar(id uint, avatar string, key *string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		return updateVersioned(tx, id, nil, func(query *gorm.DB) *gorm.DB {
+			return query.Updates(map[string]any{
+				"avatar":     avatar,
+				"avatar_key": key,
+				"updated_at": time.Now(),
+			})
+		})
+	})
+}
+
+// ListCustomers page of customers matching filter and the total number of
+// matches regardless of paging
+func (repo Customer) ListCustomers(filter CustomerFilter) ([]entity.Customer, int64, error) {
+	var total int64
+	err := repo.filterCustomers(filter).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := repo.filterCustomers(filter)
+	if len(filter.After) > 0 {
+		sql, vars := keysetCondition(filter.Sort, filter.After)
+		query = query.Where(sql, vars...)
+	}
+
+	var customers []entity.Customer
+	err = orderCustomers(query, filter.Sort).Preload("Tags", orderTags).Preload("Field_values").
+		Limit(filter.Limit).Offset(filter.Offset).Find(&customers).Error
+	return customers, total, err
+}
+
+// ExportCustomers call fn with every customer matching filter in order, rows
+// are read one at a time from a cursor, paging fields of filter are ignored
+func (repo Customer) ExportCustomers(filter CustomerFilter, fn func(customer entity.Customer) error) error {
+	rows, err := orderCustomers(repo.filterCustomers(filter), filter.Sort).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var customer entity.Customer
+		err = repo.db.ScanRows(rows, &customer)
+		if err != nil {
+			return err
+		}
+		err = fn(customer)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func orderCustomers(query *gorm.DB, sort []SortField) *gorm.DB {
+	for _, field := range sort {
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: field.Column},
+			Desc:   field.Desc,
+		})
+	}
+	return query
+}
+
+func (repo Customer) filterCustomers(filter CustomerFilter) *gorm.DB {
+	return whereCustomers(repo.db.Model(&entity.Customer{}), filter)
+}
+
+func whereCustomers(query *gorm.DB, filter CustomerFilter) *gorm.DB {
+	if filter.First_name != "" {
+		query = query.Where("LOWER(first_name) LIKE ?", "%"+strings.ToLower(filter.First_name)+"%")
+	}
+	if filter.Last_name != "" {
+		query = query.Where("LOWER(last_name) LIKE ?", "%"+strings.ToLower(filter.Last_name)+"%")
+	}
+	if filter.Email != "" {
+		query = query.Where("LOWER(email) LIKE ?", "%"+strings.ToLower(filter.Email)+"%")
+	}
+	if filter.Account_id != 0 {
+		query = query.Where("account_id = ?", filter.Account_id)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", filter.CreatedFrom.UTC())
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at <= ?", filter.CreatedTo.UTC())
+	}
+	if len(filter.Tag_ids) > 0 {
+		query = query.Where("id IN (?)", query.Session(&gorm.Session{NewDB: true}).Model(&entity.CustomerTag{}).
+			Select("customer_id").
+			Where("tag_id IN ?", filter.Tag_ids).
+			Group("customer_id").
+			Having("COUNT(*) = ?", len(filter.Tag_ids)))
+	}
+	for _, condition := range filter.Fields {
+		query = query.Where("id IN (?)", whereFieldValue(query.Session(&gorm.Session{NewDB: true}).
+			Model(&entity.CustomFieldValue{}).Select("customer_id"), condition))
+	}
+	if filter.Segment != nil {
+		query = whereCustomers(query, *filter.Segment)
+	}
+	return query
+}
+
+func whereFieldValue(query *gorm.DB, condition FieldCondition) *gorm.DB {
+	query = query.Where("field_id = ?", condition.Field_id)
+	if len(condition.Values) > 0 {
+		query = query.Where("value IN ?", condition.Values)
+	}
+	if condition.Contains != "" {
+		query = query.Where("LOWER(value) LIKE ?", "%"+strings.ToLower(condition.Contains)+"%")
+	}
+	if condition.MinNumber != nil {
+		query = query.Where("number >= ?", *condition.MinNumber)
+	}
+	if condition.MaxNumber != nil {
+		query = query.Where("number <= ?", *condition.MaxNumber)
+	}
+	if condition.MinText != nil {
+		query = query.Where("value >= ?", *condition.MinText)
+	}
+	if condition.MaxText != nil {
+		query = query.Where("value <= ?", *condition.MaxText)
+	}
+	return query
+}
+
+// orderTags tags of a customer in name order
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("name")
+}
+
+// keysetCondition rows strictly after values in sort order, e.g. for
+// (last_name, id) "last_name > ? OR (last_name = ? AND id > ?)"
+func keysetCondition(sort []SortField, values []any) (string, []any) {
+	var ors []string
+	var vars []any
+	for i, field := range sort {
+		var ands []string
+		for j := 0; j < i; j++ {
+			ands = append(ands, sort[j].Column+" = ?")
+			vars = append(vars, values[j])
+		}
+		op := " > ?"
+		if field.Desc {
+			op = " < ?"
+		}
+		ands = append(ands, field.Column+op)
+		vars = append(vars, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return strings.Join(ors, " OR "), vars
 }
@@ -0,0 +1,423 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedCustomers(t *testing.T, repo Customer, names ...string) []entity.Customer {
+	t.Helper()
+	var customers []entity.Customer
+	for _, name := range names {
+		customer, err := repo.CreateCustomer(&entity.Customer{
+			First_name: name,
+			Last_name:  "Doe",
+			Email:      name + "@example.com",
+		})
+		require.NoError(t, err)
+		customers = append(customers, *customer)
+	}
+	return customers
+}
+
+func TestCustomer_CreateAndGet(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+
+	created, err := repo.CreateCustomer(&entity.Customer{
+		First_name: "John",
+		Last_name:  "Doe",
+		Email:      "john.doe@example.com",
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	found, err := repo.GetCustomerById(created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "John", found.First_name)
+	assert.Equal(t, "john.doe@example.com", found.Email)
+}
+
+func TestCustomer_GetNotFound(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+
+	_, err := repo.GetCustomerById(42)
+
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestCustomer_Update(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	customer := seedCustomers(t, repo, "john")[0]
+
+	_, err := repo.UpdateCustomer(&entity.Customer{Last_name: "Smith"}, customer.ID)
+	require.NoError(t, err)
+
+	found, err := repo.GetCustomerById(customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "john", found.First_name)
+	assert.Equal(t, "Smith", found.Last_name)
+
+	_, err = repo.UpdateCustomer(&entity.Customer{Last_name: "Smith"}, 42)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestCustomer_Delete(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	customer := seedCustomers(t, repo, "john")[0]
+
+	_, err := repo.DeleteCustomer(customer.ID, 1)
+	require.NoError(t, err)
+
+	_, err = repo.GetCustomerById(customer.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	_, err = repo.DeleteCustomer(customer.ID, 1)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestCustomer_ListFilterAndSort(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	seedCustomers(t, repo, "alice", "bob", "Alina", "carol")
+
+	customers, total, err := repo.ListCustomers(CustomerFilter{
+		First_name: "ALI",
+		Sort:       []SortField{{Column: "first_name", Desc: true}, {Column: "id"}},
+		Limit:      10,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, customers, 2)
+	assert.Equal(t, "alice", customers[0].First_name)
+	assert.Equal(t, "Alina", customers[1].First_name)
+}
+
+func TestCustomer_ListOffsetAndKeyset(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	seeded := seedCustomers(t, repo, "a", "b", "c", "d", "e")
+	sort := []SortField{{Column: "id"}}
+
+	page, total, err := repo.ListCustomers(CustomerFilter{Sort: sort, Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	require.Len(t, page, 2)
+	assert.Equal(t, seeded[2].ID, page[0].ID)
+
+	page, total, err = repo.ListCustomers(CustomerFilter{Sort: sort, Limit: 2, After: []any{seeded[3].ID}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	require.Len(t, page, 1)
+	assert.Equal(t, seeded[4].ID, page[0].ID)
+}
+
+func TestCustomer_ListCreatedRange(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	seeded := seedCustomers(t, repo, "old", "new")
+	lastMonth := time.Now().AddDate(0, -1, 0)
+	require.NoError(t, dbCrud.Model(&entity.Customer{}).
+		Where("id = ?", seeded[0].ID).
+		Update("created_at", lastMonth.UTC()).Error)
+
+	// a non UTC bound must compare the same as its UTC equivalent
+	from := time.Now().Add(-time.Hour).In(time.FixedZone("WIB", 7*60*60))
+	customers, total, err := repo.ListCustomers(CustomerFilter{
+		CreatedFrom: &from,
+		Sort:        []SortField{{Column: "id"}},
+		Limit:       10,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, customers, 1)
+	assert.Equal(t, "new", customers[0].First_name)
+}
+
+func TestCustomer_EmailUniqueIgnoresCase(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	john := seedCustomers(t, repo, "john")[0]
+
+	found, err := repo.GetCustomerByEmail(" JOHN@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, john.ID, found.ID)
+
+	_, err = repo.CreateCustomer(&entity.Customer{First_name: "Johnny", Email: "John@EXAMPLE.com"})
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+
+	jane := seedCustomers(t, repo, "jane")[0]
+	_, err = repo.UpdateCustomer(&entity.Customer{Email: "JOHN@example.com"}, jane.ID)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+
+	_, err = repo.GetCustomerByEmail("nobody@example.com")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestCustomer_EachCustomerBatch(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	seedCustomers(t, repo, "a", "b", "c", "d", "e")
+
+	var sizes []int
+	var names []string
+	err := repo.EachCustomerBatch(2, func(customers []entity.Customer) error {
+		sizes = append(sizes, len(customers))
+		for _, customer := range customers {
+			names = append(names, customer.First_name)
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+}
+
+func TestCustomer_MergeCustomers(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	seeded := seedCustomers(t, repo, "john", "jon", "johnny")
+	survivor, victim, earlier := seeded[0], seeded[1], seeded[2]
+
+	// johnny was merged into jon before, it must follow jon into john
+	require.NoError(t, dbCrud.Create(&entity.CustomerRedirect{Old_id: 99, Survivor_id: victim.ID}).Error)
+
+	survivor.Email = "JON@example.com"
+	audit := &entity.AuditLog{Action: entity.AuditActionMerge, Entity_type: "customer", Entity_id: survivor.ID}
+	err := repo.MergeCustomers(&survivor, []uint{victim.ID, earlier.ID}, audit)
+	require.NoError(t, err)
+
+	found, err := repo.GetCustomerById(survivor.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "JON@example.com", found.Email)
+	_, err = repo.GetCustomerById(victim.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	for _, oldId := range []uint{victim.ID, earlier.ID, 99} {
+		redirect, err := repo.GetCustomerRedirect(oldId)
+		require.NoError(t, err)
+		assert.Equal(t, survivor.ID, redirect.Survivor_id)
+	}
+	assert.NotZero(t, audit.ID)
+}
+
+func TestCustomer_MergeCustomersRollsBack(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	seeded := seedCustomers(t, repo, "john", "jon")
+
+	survivor := seeded[0]
+	survivor.First_name = "changed"
+	err := repo.MergeCustomers(&survivor, []uint{seeded[1].ID, 42}, &entity.AuditLog{})
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	found, err := repo.GetCustomerById(seeded[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "john", found.First_name)
+	_, err = repo.GetCustomerById(seeded[1].ID)
+	assert.NoError(t, err)
+	var audits int64
+	require.NoError(t, dbCrud.Model(&entity.AuditLog{}).Count(&audits).Error)
+	assert.Zero(t, audits)
+}
+
+func TestCustomer_TrashRestoreAndPurge(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	customer := seedCustomers(t, repo, "john")[0]
+
+	_, err := repo.DeleteCustomer(customer.ID, 1)
+	require.NoError(t, err)
+	_, total, err := repo.ListCustomers(CustomerFilter{Sort: []SortField{{Column: "id"}}, Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	trash, total, err := repo.ListDeletedCustomers(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, trash, 1)
+	assert.True(t, trash[0].Deleted_at.Valid)
+	assert.Equal(t, uint(1), *trash[0].Deleted_by)
+
+	restored, err := repo.RestoreCustomer(customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", restored.Email)
+	found, err := repo.GetCustomerByEmail("john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, customer.ID, found.ID)
+	_, err = repo.RestoreCustomer(customer.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	_, err = repo.DeleteCustomer(customer.ID, 1)
+	require.NoError(t, err)
+	purged, err := repo.PurgeCustomers(time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, purged)
+	purged, err = repo.PurgeCustomers(time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, customer.ID, purged[0].ID)
+	_, total, err = repo.ListDeletedCustomers(10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestCustomer_RestoreEmailTakenMeanwhile(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	customer := seedCustomers(t, repo, "john")[0]
+
+	_, err := repo.DeleteCustomer(customer.ID, 1)
+	require.NoError(t, err)
+	seedCustomers(t, repo, "john")
+
+	restored, err := repo.RestoreCustomer(customer.ID)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	assert.Equal(t, "john@example.com", restored.Email)
+	_, total, err := repo.ListDeletedCustomers(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestCustomer_ImportCustomers(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	john := seedCustomers(t, repo, "john")[0]
+
+	found, err := repo.GetCustomersByEmails([]string{"JOHN@example.com", "nobody@example.com"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, john.ID, found[0].ID)
+
+	err = repo.ImportCustomers(
+		[]*entity.Customer{{First_name: "jane", Email: "jane@example.com"}},
+		[]*entity.Customer{{ID: john.ID, First_name: "Johnny", Email: "John@example.com"}},
+	)
+	require.NoError(t, err)
+	updated, err := repo.GetCustomerById(john.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Johnny", updated.First_name)
+	assert.Equal(t, john.Last_name, updated.Last_name)
+	_, err = repo.GetCustomerByEmail("jane@example.com")
+	assert.NoError(t, err)
+
+	// the second create clashes with jane, the whole batch is rolled back
+	err = repo.ImportCustomers([]*entity.Customer{
+		{First_name: "ann", Email: "ann@example.com"},
+		{First_name: "jane", Email: "JANE@example.com"},
+	}, nil)
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	_, err = repo.GetCustomerByEmail("ann@example.com")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestCustomer_ExportCustomers(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	seeded := seedCustomers(t, repo, "ann", "bob", "cid", "dan")
+	_, err := repo.DeleteCustomer(seeded[1].ID, 1)
+	require.NoError(t, err)
+
+	var names []string
+	err = repo.ExportCustomers(CustomerFilter{
+		Sort:  []SortField{{Column: "first_name", Desc: true}},
+		Limit: 1,
+	}, func(customer entity.Customer) error {
+		names = append(names, customer.First_name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dan", "cid", "ann"}, names)
+
+	stop := errors.New("stop")
+	err = repo.ExportCustomers(CustomerFilter{}, func(customer entity.Customer) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestCustomer_Versions(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	customer := seedCustomers(t, repo, "jon")[0]
+
+	// only touching updated_at records no version
+	_, err := repo.UpdateCustomer(&entity.Customer{UpdatedAt: time.Now()}, customer.ID)
+	require.NoError(t, err)
+	_, total, err := repo.ListCustomerVersions(customer.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+
+	_, err = repo.UpdateCustomer(&entity.Customer{First_name: "John", UpdatedAt: time.Now()}, customer.ID)
+	require.NoError(t, err)
+	_, err = repo.UpdateCustomer(&entity.Customer{Email: "john@example.com", UpdatedAt: time.Now()}, customer.ID)
+	require.NoError(t, err)
+
+	versions, total, err := repo.ListCustomerVersions(customer.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []uint{3, 2, 1}, []uint{versions[0].Version, versions[1].Version, versions[2].Version})
+	assert.Equal(t, "jon", versions[2].First_name)
+	assert.Equal(t, "John", versions[1].First_name)
+	assert.Equal(t, "john@example.com", versions[0].Email)
+
+	version, err := repo.GetCustomerVersionAt(customer.ID, versions[1].CreatedAt)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), version.Version)
+	_, err = repo.GetCustomerVersionAt(customer.ID, customer.CreatedAt.Add(-time.Hour))
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	require.NoError(t, repo.RevertCustomer(customer.ID, 1))
+	reverted, err := repo.GetCustomerById(customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "jon", reverted.First_name)
+	assert.Equal(t, "jon@example.com", reverted.Email)
+	latest, err := repo.GetCustomerVersion(customer.ID, 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), *latest.Reverted_from)
+
+	assert.ErrorIs(t, repo.RevertCustomer(customer.ID, 9), apperror.ErrNotFound)
+}
+
+func TestCustomer_SetCustomerAvatarVersioned(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	customer := seedCustomers(t, repo, "john")[0]
+
+	beforeUpload := time.Now()
+	key := "avatars/1.png"
+	require.NoError(t, repo.SetCustomerAvatar(customer.ID, "/avatars/1.png", &key))
+	afterUpload := time.Now()
+	_, err := repo.UpdateCustomer(&entity.Customer{First_name: "Johnny", UpdatedAt: time.Now()}, customer.ID)
+	require.NoError(t, err)
+
+	version, err := repo.GetCustomerVersionAt(customer.ID, beforeUpload)
+	require.NoError(t, err)
+	assert.Equal(t, "", version.Avatar)
+	// the upload is its own version, not folded into the next update
+	version, err = repo.GetCustomerVersionAt(customer.ID, afterUpload)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), version.Version)
+	assert.Equal(t, "/avatars/1.png", version.Avatar)
+	assert.Equal(t, "john", version.First_name)
+
+	assert.ErrorIs(t, repo.SetCustomerAvatar(42, "/avatars/42.png", nil), apperror.ErrNotFound)
+}
+
+func TestCustomer_MergeCustomersVersioned(t *testing.T) {
+	repo := NewCustomer(newTestDB(t))
+	seeded := seedCustomers(t, repo, "jon", "john")
+	survivor := seeded[0]
+	survivor.First_name = "John"
+	survivor.UpdatedAt = time.Now()
+
+	require.NoError(t, repo.MergeCustomers(&survivor, []uint{seeded[1].ID}, &entity.AuditLog{
+		Action: entity.AuditActionMerge, Entity_type: "customer", Entity_id: survivor.ID,
+	}))
+
+	versions, total, err := repo.ListCustomerVersions(survivor.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "John", versions[0].First_name)
+	assert.Equal(t, "jon", versions[1].First_name)
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"gorm.io/gorm"
+)
+
+// DealFilter zero fields match every deal
+type DealFilter struct {
+	Stage       string
+	Owner_id    uint
+	Customer_id uint
+	Limit       int
+	Offset      int
+}
+
+// PipelineFilter deals counted by the pipeline summary, expected to close on
+// or after CloseFrom and before CloseBefore. A range leaves out deals without
+// an expected close date
+type PipelineFilter struct {
+	Owner_id    uint
+	CloseFrom   *time.Time
+	CloseBefore *time.Time
+}
+
+type Deal struct {
+	db *gorm.DB
+}
+
+func NewDeal(dbCrud *gorm.DB) Deal {
+	return Deal{
+		db: dbCrud,
+	}
+}
+
+type DealInterfaceRepo interface {
+	CreateDeal(deal *entity.Deal, change *entity.DealStageChange) error
+	GetDealById(id uint) (entity.Deal, error)
+	ListDeals(filter DealFilter) ([]entity.Deal, int64, error)
+	UpdateDeal(deal *entity.Deal, id uint) error
+	DeleteDeal(id uint) error
+	MoveDealStage(id uint, from string, change *entity.DealStageChange) error
+	ListStageChanges(dealId uint) ([]entity.DealStageChange, error)
+	PipelineSummary(filter PipelineFilter) ([]entity.PipelineTotal, error)
+}
+
+// CreateDeal new Deal along with the change recording its first stage
+func (repo Deal) CreateDeal(deal *entity.Deal, change *entity.DealStageChange) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(deal).Error
+		if err != nil {
+			return translateError(err, "deal")
+		}
+		change.Deal_id = deal.ID
+		return tx.Create(change).Error
+	})
+}
+
+// GetDealById get single Deal by id
+func (repo Deal) GetDealById(id uint) (entity.Deal, error) {
+	var deal entity.Deal
+	err := repo.db.First(&deal, "id = ?", id).Error
+	return deal, translateError(err, "deal")
+}
+
+// ListDeals page of deals, most recently created first
+func (repo Deal) ListDeals(filter DealFilter) ([]entity.Deal, int64, error) {
+	query := repo.db.Model(&entity.Deal{})
+	if filter.Stage != "" {
+		query = query.Where("stage = ?", filter.Stage)
+	}
+	if filter.Owner_id != 0 {
+		query = query.Where("owner_id = ?", filter.Owner_id)
+	}
+	if filter.Customer_id != 0 {
+		query = query.Where("customer_id = ?", filter.Customer_id)
+	}
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var deals []entity.Deal
+	err = query.Order("id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&deals).Error
+	return deals, total, err
+}
+
+// UpdateDeal everything but the stage, which only moves with MoveDealStage
+func (repo Deal) UpdateDeal(deal *entity.Deal, id uint) error {
+	res := repo.db.Model(&entity.Deal{}).Where("id = ?", id).
+		Select("title", "customer_id", "owner_id", "value", "currency", "expected_close_date", "updated_at").
+		Updates(deal)
+	return affectedOrNotFound(res, &entity.Deal{}, "deal", "id = ?", id)
+}
+
+// DeleteDeal its stage changes go with it
+func (repo Deal) DeleteDeal(id uint) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("deal_id = ?", id).Delete(&entity.DealStageChange{}).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Delete(&entity.Deal{}, id)
+		return affectedOrNotFound(res, &entity.Deal{}, "deal", "id = ?", id)
+	})
+}
+
+// MoveDealStage in one transaction move the deal from stage from to the
+// stage of change and record change. A deal no longer in stage from, moved
+// by a concurrent request, is a conflict
+func (repo Deal) MoveDealStage(id uint, from string, change *entity.DealStageChange) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.Deal{}).Where("id = ? AND stage = ?", id, from).
+			Updates(map[string]any{
+				"stage":            change.To_stage,
+				"stage_changed_at": change.Changed_at,
+				"updated_at":       change.Changed_at,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			err := affectedOrNotFound(res, &entity.Deal{}, "deal", "id = ?", id)
+			if err != nil {
+				return err
+			}
+			return apperror.Conflict("deal is no longer in stage "+from, nil)
+		}
+		change.Deal_id = id
+		return tx.Create(change).Error
+	})
+}
+
+// ListStageChanges every stage the deal went through, oldest first
+func (repo Deal) ListStageChanges(dealId uint) ([]entity.DealStageChange, error) {
+	var changes []entity.DealStageChange
+	err := repo.db.Where("deal_id = ?", dealId).Order("changed_at").Order("id").Find(&changes).Error
+	return changes, err
+}
+
+// PipelineSummary number and total value of the deals per stage and
+// currency
+func (repo Deal) PipelineSummary(filter PipelineFilter) ([]entity.PipelineTotal, error) {
+	query := repo.db.Model(&entity.Deal{}).
+		Select("stage, currency, COUNT(*) AS count, SUM(value) AS value")
+	if filter.Owner_id != 0 {
+		query = query.Where("owner_id = ?", filter.Owner_id)
+	}
+	if filter.CloseFrom != nil {
+		query = query.Where("expected_close_date >= ?", filter.CloseFrom.UTC())
+	}
+	if filter.CloseBefore != nil {
+		query = query.Where("expected_close_date < ?", filter.CloseBefore.UTC())
+	}
+	var totals []entity.PipelineTotal
+	err := query.Group("stage").Group("currency").Order("stage").Order("currency").Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedDeal(t *testing.T, repo Deal, deal entity.Deal) entity.Deal {
+	t.Helper()
+	require.NoError(t, repo.CreateDeal(&deal, &entity.DealStageChange{To_stage: deal.Stage, Changed_at: time.Now()}))
+	return deal
+}
+
+func closeDate(value string) *time.Time {
+	date, _ := time.Parse("2006-01-02", value)
+	return &date
+}
+
+func TestDeal_CRUD(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewDeal(dbCrud)
+	customer := seedCustomers(t, NewCustomer(dbCrud), "john")[0]
+	deal := seedDeal(t, repo, entity.Deal{Title: "Licenses", Customer_id: customer.ID, Stage: "lead", Value: 1200.5, Currency: "USD"})
+
+	found, err := repo.GetDealById(deal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1200.5, found.Value)
+
+	err = repo.UpdateDeal(&entity.Deal{Title: "More licenses", Customer_id: customer.ID, Value: 2000, Currency: "EUR", Expected_close_date: closeDate("2026-12-31")}, deal.ID)
+	require.NoError(t, err)
+	found, err = repo.GetDealById(deal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "More licenses", found.Title)
+	assert.Equal(t, "lead", found.Stage)
+	assert.Equal(t, "2026-12-31", found.Expected_close_date.Format("2006-01-02"))
+
+	deals, total, err := repo.ListDeals(DealFilter{Customer_id: customer.ID, Stage: "lead", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, deal.ID, deals[0].ID)
+
+	require.NoError(t, repo.DeleteDeal(deal.ID))
+	assert.ErrorIs(t, repo.DeleteDeal(deal.ID), apperror.ErrNotFound)
+	changes, err := repo.ListStageChanges(deal.ID)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDeal_MoveDealStage(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewDeal(dbCrud)
+	customer := seedCustomers(t, NewCustomer(dbCrud), "john")[0]
+	deal := seedDeal(t, repo, entity.Deal{Title: "Licenses", Customer_id: customer.ID, Stage: "lead", Currency: "USD"})
+
+	from := "lead"
+	movedAt := time.Now().Add(time.Minute)
+	err := repo.MoveDealStage(deal.ID, from, &entity.DealStageChange{From_stage: &from, To_stage: "qualified", Changed_at: movedAt})
+	require.NoError(t, err)
+
+	// a second move from the stage it left loses
+	err = repo.MoveDealStage(deal.ID, from, &entity.DealStageChange{From_stage: &from, To_stage: "lost", Changed_at: movedAt})
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+	err = repo.MoveDealStage(deal.ID+1, from, &entity.DealStageChange{From_stage: &from, To_stage: "lost", Changed_at: movedAt})
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	found, err := repo.GetDealById(deal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "qualified", found.Stage)
+	assert.WithinDuration(t, movedAt, found.Stage_changed_at, time.Second)
+
+	changes, err := repo.ListStageChanges(deal.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Nil(t, changes[0].From_stage)
+	assert.Equal(t, "lead", changes[0].To_stage)
+	assert.Equal(t, "lead", *changes[1].From_stage)
+	assert.Equal(t, "qualified", changes[1].To_stage)
+}
+
+func TestDeal_PipelineSummary(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewDeal(dbCrud)
+	customer := seedCustomers(t, NewCustomer(dbCrud), "john")[0]
+	owner := seedActor(t, NewActor(dbCrud), "alice")
+	seedDeal(t, repo, entity.Deal{Customer_id: customer.ID, Stage: "lead", Value: 100, Currency: "USD", Owner_id: &owner.ID, Expected_close_date: closeDate("2026-11-30")})
+	seedDeal(t, repo, entity.Deal{Customer_id: customer.ID, Stage: "lead", Value: 50.25, Currency: "USD", Expected_close_date: closeDate("2026-12-01")})
+	seedDeal(t, repo, entity.Deal{Customer_id: customer.ID, Stage: "lead", Value: 70, Currency: "EUR", Owner_id: &owner.ID})
+	seedDeal(t, repo, entity.Deal{Customer_id: customer.ID, Stage: "won", Value: 500, Currency: "USD", Owner_id: &owner.ID, Expected_close_date: closeDate("2026-12-31")})
+
+	totals, err := repo.PipelineSummary(PipelineFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []entity.PipelineTotal{
+		{Stage: "lead", Currency: "EUR", Count: 1, Value: 70},
+		{Stage: "lead", Currency: "USD", Count: 2, Value: 150.25},
+		{Stage: "won", Currency: "USD", Count: 1, Value: 500},
+	}, totals)
+
+	totals, err = repo.PipelineSummary(PipelineFilter{Owner_id: owner.ID, CloseFrom: closeDate("2026-11-30"), CloseBefore: closeDate("2027-01-01")})
+	require.NoError(t, err)
+	assert.Equal(t, []entity.PipelineTotal{
+		{Stage: "lead", Currency: "USD", Count: 1, Value: 100},
+		{Stage: "won", Currency: "USD", Count: 1, Value: 500},
+	}, totals)
+
+	// the last day of a range counts in full
+	totals, err = repo.PipelineSummary(PipelineFilter{CloseFrom: closeDate("2026-12-01"), CloseBefore: closeDate("2026-12-02")})
+	require.NoError(t, err)
+	assert.Equal(t, []entity.PipelineTotal{{Stage: "lead", Currency: "USD", Count: 1, Value: 50.25}}, totals)
+}
+
+func TestCustomer_MergeCustomersMovesDeals(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	dealRepo := NewDeal(dbCrud)
+	seeded := seedCustomers(t, repo, "john", "jon")
+	deal := seedDeal(t, dealRepo, entity.Deal{Customer_id: seeded[1].ID, Stage: "lead", Currency: "USD"})
+
+	survivor, err := repo.GetCustomerById(seeded[0].ID)
+	require.NoError(t, err)
+	err = repo.MergeCustomers(&survivor, []uint{seeded[1].ID}, &entity.AuditLog{Action: entity.AuditActionMerge, Entity_type: "customer"})
+	require.NoError(t, err)
+
+	found, err := dealRepo.GetDealById(deal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, survivor.ID, found.Customer_id)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"gorm.io/gorm"
+)
+
+// translateError turn gorm errors into apperror kinds, name is the record
+// type used in the message e.g. "customer"
+func translateError(err error, name string) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return apperror.NotFound(name + " not found")
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return apperror.Conflict(name+" already exists", nil)
+	}
+	return err
+}
+
+// affectedOrNotFound check an update or delete touched a row. MySQL reports
+// 0 affected rows when an update changes nothing, so existence is checked
+// before answering not found
+func affectedOrNotFound(res *gorm.DB, model any, name string, query string, args ...any) error {
+	if res.Error != nil {
+		return translateError(res.Error, name)
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+	var count int64
+	err := res.Session(&gorm.Session{NewDB: true}).Model(model).Where(query, args...).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return apperror.NotFound(name + " not found")
+	}
+	return nil
+}
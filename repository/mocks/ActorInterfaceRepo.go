@@ -5,6 +5,7 @@ package mocks
 import (
 	entity "github.com/alkamalp/crm-golang/entity"
 	mock "github.com/stretchr/testify/mock"
+	time "time"
 )
 
 // ActorInterfaceRepo is an autogenerated mock type for the ActorInterfaceRepo type
@@ -38,25 +39,25 @@ func (_m *ActorInterfaceRepo) CreateActor(actor *entity.Actor) (*entity.Actor, e
 	return r0, r1
 }
 
-// DeleteActor provides a mock function with given fields: username
-func (_m *ActorInterfaceRepo) DeleteActor(username string) (interface{}, error) {
-	ret := _m.Called(username)
+// DeleteActor provides a mock function with given fields: username, deletedBy
+func (_m *ActorInterfaceRepo) DeleteActor(username string, deletedBy uint) (interface{}, error) {
+	ret := _m.Called(username, deletedBy)
 
 	var r0 interface{}
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (interface{}, error)); ok {
-		return rf(username)
+	if rf, ok := ret.Get(0).(func(string, uint) (interface{}, error)); ok {
+		return rf(username, deletedBy)
 	}
-	if rf, ok := ret.Get(0).(func(string) interface{}); ok {
-		r0 = rf(username)
+	if rf, ok := ret.Get(0).(func(string, uint) interface{}); ok {
+		r0 = rf(username, deletedBy)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(interface{})
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(username)
+	if rf, ok := ret.Get(1).(func(string, uint) error); ok {
+		r1 = rf(username, deletedBy)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -88,25 +89,104 @@ func (_m *ActorInterfaceRepo) GetActorById(id uint) (entity.Actor, error) {
 	return r0, r1
 }
 
-// LoginActor provides a mock function with given fields: actor
-func (_m *ActorInterfaceRepo) LoginActor(actor *entity.Actor) (*entity.Actor, error) {
-	ret := _m.Called(actor)
+// GetActorByUsername provides a mock function with given fields: username
+func (_m *ActorInterfaceRepo) GetActorByUsername(username string) (entity.Actor, error) {
+	ret := _m.Called(username)
 
-	var r0 *entity.Actor
+	var r0 entity.Actor
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*entity.Actor) (*entity.Actor, error)); ok {
-		return rf(actor)
+	if rf, ok := ret.Get(0).(func(string) (entity.Actor, error)); ok {
+		return rf(username)
 	}
-	if rf, ok := ret.Get(0).(func(*entity.Actor) *entity.Actor); ok {
-		r0 = rf(actor)
+	if rf, ok := ret.Get(0).(func(string) entity.Actor); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Get(0).(entity.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeletedActors provides a mock function with given fields: limit, offset
+func (_m *ActorInterfaceRepo) ListDeletedActors(limit int, offset int) ([]entity.Actor, int64, error) {
+	ret := _m.Called(limit, offset)
+
+	var r0 []entity.Actor
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int, int) ([]entity.Actor, int64, error)); ok {
+		return rf(limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) []entity.Actor); ok {
+		r0 = rf(limit, offset)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.Actor)
+			r0 = ret.Get(0).([]entity.Actor)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*entity.Actor) error); ok {
-		r1 = rf(actor)
+	if rf, ok := ret.Get(1).(func(int, int) int64); ok {
+		r1 = rf(limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(int, int) error); ok {
+		r2 = rf(limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PurgeActors provides a mock function with given fields: deletedBefore
+func (_m *ActorInterfaceRepo) PurgeActors(deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(deletedBefore)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreActor provides a mock function with given fields: username
+func (_m *ActorInterfaceRepo) RestoreActor(username string) (entity.Actor, error) {
+	ret := _m.Called(username)
+
+	var r0 entity.Actor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.Actor, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.Actor); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Get(0).(entity.Actor)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -118,17 +198,26 @@ func (_m *ActorInterfaceRepo) LoginActor(actor *entity.Actor) (*entity.Actor, er
 func (_m *ActorInterfaceRepo) UpdateActor(actor *entity.Actor, id uint) (*entity.Actor, error) {
 	ret := _m.Called(actor, id)
 
+	var r0 *entity.Actor
+	var r1 error
 	if rf, ok := ret.Get(0).(func(*entity.Actor, uint) (*entity.Actor, error)); ok {
 		return rf(actor, id)
 	}
+	if rf, ok := ret.Get(0).(func(*entity.Actor, uint) *entity.Actor); ok {
+		r0 = rf(actor, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Actor)
+		}
+	}
 
-	if ret.Get(0) != nil {
-		r0 := ret.Get(0).(*entity.Actor)
-		r1 := ret.Error(1)
-		return r0, r1
+	if rf, ok := ret.Get(1).(func(*entity.Actor, uint) error); ok {
+		r1 = rf(actor, id)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return nil, ret.Error(1)
+	return r0, r1
 }
 
 type mockConstructorTestingTNewActorInterfaceRepo interface {
@@ -4,7 +4,9 @@ package mocks
 
 import (
 	entity "github.com/alkamalp/crm-golang/entity"
+	repository "github.com/alkamalp/crm-golang/repository"
 	mock "github.com/stretchr/testify/mock"
+	time "time"
 )
 
 // CustomerInterfaceRepo is an autogenerated mock type for the CustomerInterfaceRepo type
@@ -38,25 +40,77 @@ func (_m *CustomerInterfaceRepo) CreateCustomer(Customer *entity.Customer) (*ent
 	return r0, r1
 }
 
-// DeleteCustomer provides a mock function with given fields: id
-func (_m *CustomerInterfaceRepo) DeleteCustomer(id uint) (interface{}, error) {
-	ret := _m.Called(id)
+// DeleteCustomer provides a mock function with given fields: id, deletedBy
+func (_m *CustomerInterfaceRepo) DeleteCustomer(id uint, deletedBy uint) (interface{}, error) {
+	ret := _m.Called(id, deletedBy)
 
 	var r0 interface{}
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (interface{}, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(uint, uint) (interface{}, error)); ok {
+		return rf(id, deletedBy)
 	}
-	if rf, ok := ret.Get(0).(func(uint) interface{}); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(uint, uint) interface{}); ok {
+		r0 = rf(id, deletedBy)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(interface{})
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(id, deletedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EachCustomerBatch provides a mock function with given fields: size, fn
+func (_m *CustomerInterfaceRepo) EachCustomerBatch(size int, fn func([]entity.Customer) error) error {
+	ret := _m.Called(size, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, func([]entity.Customer) error) error); ok {
+		r0 = rf(size, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportCustomers provides a mock function with given fields: filter, fn
+func (_m *CustomerInterfaceRepo) ExportCustomers(filter repository.CustomerFilter, fn func(entity.Customer) error) error {
+	ret := _m.Called(filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(repository.CustomerFilter, func(entity.Customer) error) error); ok {
+		r0 = rf(filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCustomerByEmail provides a mock function with given fields: email
+func (_m *CustomerInterfaceRepo) GetCustomerByEmail(email string) (entity.Customer, error) {
+	ret := _m.Called(email)
+
+	var r0 entity.Customer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (entity.Customer, error)); ok {
+		return rf(email)
+	}
+	if rf, ok := ret.Get(0).(func(string) entity.Customer); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Get(0).(entity.Customer)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(email)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -88,6 +142,309 @@ func (_m *CustomerInterfaceRepo) GetCustomerById(id uint) (entity.Customer, erro
 	return r0, r1
 }
 
+// GetCustomerRedirect provides a mock function with given fields: oldId
+func (_m *CustomerInterfaceRepo) GetCustomerRedirect(oldId uint) (entity.CustomerRedirect, error) {
+	ret := _m.Called(oldId)
+
+	var r0 entity.CustomerRedirect
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (entity.CustomerRedirect, error)); ok {
+		return rf(oldId)
+	}
+	if rf, ok := ret.Get(0).(func(uint) entity.CustomerRedirect); ok {
+		r0 = rf(oldId)
+	} else {
+		r0 = ret.Get(0).(entity.CustomerRedirect)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(oldId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCustomerVersion provides a mock function with given fields: customerId, version
+func (_m *CustomerInterfaceRepo) GetCustomerVersion(customerId uint, version uint) (entity.CustomerVersion, error) {
+	ret := _m.Called(customerId, version)
+
+	var r0 entity.CustomerVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint) (entity.CustomerVersion, error)); ok {
+		return rf(customerId, version)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint) entity.CustomerVersion); ok {
+		r0 = rf(customerId, version)
+	} else {
+		r0 = ret.Get(0).(entity.CustomerVersion)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(customerId, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCustomerVersionAt provides a mock function with given fields: customerId, at
+func (_m *CustomerInterfaceRepo) GetCustomerVersionAt(customerId uint, at time.Time) (entity.CustomerVersion, error) {
+	ret := _m.Called(customerId, at)
+
+	var r0 entity.CustomerVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) (entity.CustomerVersion, error)); ok {
+		return rf(customerId, at)
+	}
+	if rf, ok := ret.Get(0).(func(uint, time.Time) entity.CustomerVersion); ok {
+		r0 = rf(customerId, at)
+	} else {
+		r0 = ret.Get(0).(entity.CustomerVersion)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, time.Time) error); ok {
+		r1 = rf(customerId, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCustomersByEmails provides a mock function with given fields: emails
+func (_m *CustomerInterfaceRepo) GetCustomersByEmails(emails []string) ([]entity.Customer, error) {
+	ret := _m.Called(emails)
+
+	var r0 []entity.Customer
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string) ([]entity.Customer, error)); ok {
+		return rf(emails)
+	}
+	if rf, ok := ret.Get(0).(func([]string) []entity.Customer); ok {
+		r0 = rf(emails)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Customer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(emails)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportCustomers provides a mock function with given fields: creates, updates
+func (_m *CustomerInterfaceRepo) ImportCustomers(creates []*entity.Customer, updates []*entity.Customer) error {
+	ret := _m.Called(creates, updates)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*entity.Customer, []*entity.Customer) error); ok {
+		r0 = rf(creates, updates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListCustomerVersions provides a mock function with given fields: customerId, limit, offset
+func (_m *CustomerInterfaceRepo) ListCustomerVersions(customerId uint, limit int, offset int) ([]entity.CustomerVersion, int64, error) {
+	ret := _m.Called(customerId, limit, offset)
+
+	var r0 []entity.CustomerVersion
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(uint, int, int) ([]entity.CustomerVersion, int64, error)); ok {
+		return rf(customerId, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int, int) []entity.CustomerVersion); ok {
+		r0 = rf(customerId, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.CustomerVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int, int) int64); ok {
+		r1 = rf(customerId, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(uint, int, int) error); ok {
+		r2 = rf(customerId, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListCustomers provides a mock function with given fields: filter
+func (_m *CustomerInterfaceRepo) ListCustomers(filter repository.CustomerFilter) ([]entity.Customer, int64, error) {
+	ret := _m.Called(filter)
+
+	var r0 []entity.Customer
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(repository.CustomerFilter) ([]entity.Customer, int64, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(repository.CustomerFilter) []entity.Customer); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Customer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(repository.CustomerFilter) int64); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(repository.CustomerFilter) error); ok {
+		r2 = rf(filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListDeletedCustomers provides a mock function with given fields: limit, offset
+func (_m *CustomerInterfaceRepo) ListDeletedCustomers(limit int, offset int) ([]entity.Customer, int64, error) {
+	ret := _m.Called(limit, offset)
+
+	var r0 []entity.Customer
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int, int) ([]entity.Customer, int64, error)); ok {
+		return rf(limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) []entity.Customer); ok {
+		r0 = rf(limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Customer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) int64); ok {
+		r1 = rf(limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(int, int) error); ok {
+		r2 = rf(limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MergeCustomers provides a mock function with given fields: survivor, victimIds, audit
+func (_m *CustomerInterfaceRepo) MergeCustomers(survivor *entity.Customer, victimIds []uint, audit *entity.AuditLog) error {
+	ret := _m.Called(survivor, victimIds, audit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Customer, []uint, *entity.AuditLog) error); ok {
+		r0 = rf(survivor, victimIds, audit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeCustomers provides a mock function with given fields: deletedBefore
+func (_m *CustomerInterfaceRepo) PurgeCustomers(deletedBefore time.Time) ([]entity.Customer, error) {
+	ret := _m.Called(deletedBefore)
+
+	var r0 []entity.Customer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]entity.Customer, error)); ok {
+		return rf(deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []entity.Customer); ok {
+		r0 = rf(deletedBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Customer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreCustomer provides a mock function with given fields: id
+func (_m *CustomerInterfaceRepo) RestoreCustomer(id uint) (entity.Customer, error) {
+	ret := _m.Called(id)
+
+	var r0 entity.Customer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (entity.Customer, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) entity.Customer); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(entity.Customer)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevertCustomer provides a mock function with given fields: id, version
+func (_m *CustomerInterfaceRepo) RevertCustomer(id uint, version uint) error {
+	ret := _m.Called(id, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetCustomerAvatar provides a mock function with given fields: id, avatar, key
+func (_m *CustomerInterfaceRepo) SetCustomerAvatar(id uint, avatar string, key *string) error {
+	ret := _m.Called(id, avatar, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, string, *string) error); ok {
+		r0 = rf(id, avatar, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateCustomer provides a mock function with given fields: customer, id
 func (_m *CustomerInterfaceRepo) UpdateCustomer(customer *entity.Customer, id uint) (interface{}, error) {
 	ret := _m.Called(customer, id)
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"gorm.io/gorm"
+)
+
+type Role struct {
+	db *gorm.DB
+}
+
+func NewRole(dbCrud *gorm.DB) Role {
+	return Role{
+		db: dbCrud,
+	}
+}
+
+type RoleInterfaceRepo interface {
+	GetPermissionsByRoleId(roleId uint) ([]string, error)
+}
+
+// GetPermissionsByRoleId list permission names granted to a role
+func (repo Role) GetPermissionsByRoleId(roleId uint) ([]string, error) {
+	var permissions []string
+	err := repo.db.Model(&entity.RolePermission{}).
+		Where("role_id = ?", roleId).
+		Pluck("permission", &permissions).
+		Error
+	return permissions, err
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRole_SeededPermissions(t *testing.T) {
+	repo := NewRole(newTestDB(t))
+
+	permissions, err := repo.GetPermissionsByRoleId(2)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"customer:create", "customer:read", "customer:update", "customer:delete",
+		"customer:merge", "tag:manage", "segment:manage", "field:manage",
+		"account:create", "account:read", "account:update", "account:delete",
+		"deal:create", "deal:read", "deal:update", "deal:delete",
+		"task:create", "task:read", "task:update", "task:delete",
+	}, permissions)
+}
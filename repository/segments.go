@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/alkamalp/crm-golang/entity"
+	"gorm.io/gorm"
+)
+
+type Segment struct {
+	db *gorm.DB
+}
+
+func NewSegment(dbCrud *gorm.DB) Segment {
+	return Segment{
+		db: dbCrud,
+	}
+}
+
+type SegmentInterfaceRepo interface {
+	CreateSegment(segment *entity.Segment) error
+	GetSegmentById(id uint) (entity.Segment, error)
+	ListSegments(limit int, offset int) ([]entity.Segment, int64, error)
+	UpdateSegment(segment *entity.Segment, id uint) error
+	DeleteSegment(id uint) error
+}
+
+// CreateSegment new Segment
+func (repo Segment) CreateSegment(segment *entity.Segment) error {
+	err := repo.db.Create(segment).Error
+	return translateError(err, "segment")
+}
+
+// GetSegmentById get single Segment by id
+func (repo Segment) GetSegmentById(id uint) (entity.Segment, error) {
+	var segment entity.Segment
+	err := repo.db.First(&segment, "id = ?", id).Error
+	return segment, translateError(err, "segment")
+}
+
+// ListSegments page of segments in name order
+func (repo Segment) ListSegments(limit int, offset int) ([]entity.Segment, int64, error) {
+	var total int64
+	err := repo.db.Model(&entity.Segment{}).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var segments []entity.Segment
+	err = repo.db.Order("name").Limit(limit).Offset(offset).Find(&segments).Error
+	return segments, total, err
+}
+
+// UpdateSegment name and filter
+func (repo Segment) UpdateSegment(segment *entity.Segment, id uint) error {
+	res := repo.db.Model(&entity.Segment{}).Where("id = ?", id).
+		Select("name", "filter", "updated_at").
+		Updates(segment)
+	return affectedOrNotFound(res, &entity.Segment{}, "segment", "id = ?", id)
+}
+
+// DeleteSegment customers of the segment are not touched
+func (repo Segment) DeleteSegment(id uint) error {
+	res := repo.db.Delete(&entity.Segment{}, id)
+	return affectedOrNotFound(res, &entity.Segment{}, "segment", "id = ?", id)
+}
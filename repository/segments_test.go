@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegment_CRUD(t *testing.T) {
+	repo := NewSegment(newTestDB(t))
+	segment := entity.Segment{Name: "New VIPs", Filter: entity.SegmentFilter{Created_within_days: 30, Tag_ids: []uint{1}}}
+	require.NoError(t, repo.CreateSegment(&segment))
+
+	found, err := repo.GetSegmentById(segment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, segment.Filter, found.Filter)
+	assert.ErrorIs(t, repo.CreateSegment(&entity.Segment{Name: "New VIPs"}), apperror.ErrConflict)
+
+	err = repo.UpdateSegment(&entity.Segment{Name: "Leads", Filter: entity.SegmentFilter{Email: "example.com"}}, segment.ID)
+	require.NoError(t, err)
+	segments, total, err := repo.ListSegments(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "Leads", segments[0].Name)
+	assert.Equal(t, entity.SegmentFilter{Email: "example.com"}, segments[0].Filter)
+
+	require.NoError(t, repo.DeleteSegment(segment.ID))
+	assert.ErrorIs(t, repo.DeleteSegment(segment.ID), apperror.ErrNotFound)
+}
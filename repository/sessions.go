@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"gorm.io/gorm"
+)
+
+var ErrRefreshTokenUsed = apperror.Unauthorized("refresh token has already been used")
+
+type Session struct {
+	db *gorm.DB
+}
+
+func NewSession(dbCrud *gorm.DB) Session {
+	return Session{
+		db: dbCrud,
+	}
+}
+
+type SessionInterfaceRepo interface {
+	CreateSession(session *entity.Session, refreshToken *entity.RefreshToken) error
+	GetRefreshToken(tokenHash string) (entity.RefreshToken, error)
+	RotateRefreshToken(used *entity.RefreshToken, next *entity.RefreshToken) error
+	RevokeSession(id string) error
+	GetSessionById(id string) (entity.Session, error)
+}
+
+// CreateSession new login session with its first refresh token
+func (repo Session) CreateSession(session *entity.Session, refreshToken *entity.RefreshToken) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(session).Error
+		if err != nil {
+			return err
+		}
+		refreshToken.Session_id = session.ID
+		return tx.Create(refreshToken).Error
+	})
+}
+
+// GetRefreshToken get refresh token by hash together with its session
+func (repo Session) GetRefreshToken(tokenHash string) (entity.RefreshToken, error) {
+	var refreshToken entity.RefreshToken
+	err := repo.db.Preload("Session").First(&refreshToken, "token_hash = ?", tokenHash).Error
+	return refreshToken, translateError(err, "refresh token")
+}
+
+// RotateRefreshToken mark used as used and store next in the same session,
+// only one of two concurrent rotations of the same token succeeds
+func (repo Session) RotateRefreshToken(used *entity.RefreshToken, next *entity.RefreshToken) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", used.ID).
+			Update("used_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRefreshTokenUsed
+		}
+		next.Session_id = used.Session_id
+		return tx.Create(next).Error
+	})
+}
+
+// RevokeSession log the session out, revoking an already revoked session is
+// a no-op
+func (repo Session) RevokeSession(id string) error {
+	return repo.db.Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).
+		Error
+}
+
+// GetSessionById get single Session by id
+func (repo Session) GetSessionById(id string) (entity.Session, error) {
+	var session entity.Session
+	err := repo.db.First(&session, "id = ?", id).Error
+	return session, translateError(err, "session")
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedSession(t *testing.T, repo Session, actorId uint, tokenHash string) entity.RefreshToken {
+	t.Helper()
+	refreshToken := &entity.RefreshToken{
+		Token_hash: tokenHash,
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	require.NoError(t, repo.CreateSession(&entity.Session{ID: "session-" + tokenHash, Actor_id: actorId}, refreshToken))
+	return *refreshToken
+}
+
+func TestSession_RotateOnce(t *testing.T) {
+	repo := NewSession(newTestDB(t))
+	first := seedSession(t, repo, 1, "hash-1")
+
+	found, err := repo.GetRefreshToken("hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Equal(t, uint(1), found.Session.Actor_id)
+
+	next := &entity.RefreshToken{Token_hash: "hash-2", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.RotateRefreshToken(&found, next))
+	assert.Equal(t, found.Session_id, next.Session_id)
+
+	used, err := repo.GetRefreshToken("hash-1")
+	require.NoError(t, err)
+	assert.NotNil(t, used.UsedAt)
+
+	// the same token cannot be rotated twice
+	again := &entity.RefreshToken{Token_hash: "hash-3", ExpiresAt: time.Now().Add(time.Hour)}
+	assert.ErrorIs(t, repo.RotateRefreshToken(&found, again), ErrRefreshTokenUsed)
+	_, err = repo.GetRefreshToken("hash-3")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestSession_Revoke(t *testing.T) {
+	repo := NewSession(newTestDB(t))
+	refreshToken := seedSession(t, repo, 1, "hash-1")
+
+	session, err := repo.GetSessionById(refreshToken.Session_id)
+	require.NoError(t, err)
+	assert.Nil(t, session.RevokedAt)
+
+	require.NoError(t, repo.RevokeSession(refreshToken.Session_id))
+	require.NoError(t, repo.RevokeSession(refreshToken.Session_id))
+
+	session, err = repo.GetSessionById(refreshToken.Session_id)
+	require.NoError(t, err)
+	assert.NotNil(t, session.RevokedAt)
+
+	_, err = repo.GetSessionById("unknown")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
+
+func TestSession_RevokedWithActorAndPurged(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewSession(dbCrud)
+	actor := seedActor(t, NewActor(dbCrud), "admin1")
+	refreshToken := seedSession(t, repo, actor.ID, "hash-1")
+
+	_, err := NewActor(dbCrud).DeleteActor("admin1", 1)
+	require.NoError(t, err)
+
+	session, err := repo.GetSessionById(refreshToken.Session_id)
+	require.NoError(t, err)
+	assert.NotNil(t, session.RevokedAt)
+
+	_, err = NewActor(dbCrud).PurgeActors(time.Now().Add(time.Second))
+	require.NoError(t, err)
+	_, err = repo.GetRefreshToken("hash-1")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/alkamalp/crm-golang/utils/db"
+	"github.com/alkamalp/crm-golang/utils/migration"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestDB fresh in-memory sqlite database with every migration applied
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dbCrud, err := db.Open(config.Database{
+		Driver: "sqlite",
+		DSN:    "file::memory:?_pragma=foreign_keys(1)",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := dbCrud.DB()
+		if err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := migration.New(dbCrud, migration.All())
+	require.NoError(t, err)
+	_, err = migrator.Up()
+	require.NoError(t, err)
+	return dbCrud
+}
+
+func sqliteSchema(t *testing.T, dbCrud *gorm.DB) []string {
+	t.Helper()
+	var names []string
+	err := dbCrud.Raw("SELECT type || ' ' || name FROM sqlite_master WHERE name NOT LIKE 'sqlite_%' ORDER BY 1").
+		Scan(&names).Error
+	require.NoError(t, err)
+	return names
+}
+
+func TestMigrations_DownAndUp(t *testing.T) {
+	dbCrud := newTestDB(t)
+	migrated := sqliteSchema(t, dbCrud)
+	migrator, err := migration.New(dbCrud, migration.All())
+	require.NoError(t, err)
+
+	reverted, err := migrator.Down(len(migration.All()))
+	require.NoError(t, err)
+	require.Len(t, reverted, len(migration.All()))
+	require.Equal(t, []string{"table schema_migrations"}, sqliteSchema(t, dbCrud))
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	require.Len(t, applied, len(migration.All()))
+	require.Equal(t, migrated, sqliteSchema(t, dbCrud))
+}
+
+func TestMigrations_PartialDownAndUp(t *testing.T) {
+	migrations := migration.All()
+	for steps := 1; steps < len(migrations); steps++ {
+		version := migrations[len(migrations)-steps].Version
+		t.Run(fmt.Sprintf("down to %d", version-1), func(t *testing.T) {
+			dbCrud := newTestDB(t)
+			migrated := sqliteSchema(t, dbCrud)
+			migrator, err := migration.New(dbCrud, migrations)
+			require.NoError(t, err)
+
+			reverted, err := migrator.Down(steps)
+			require.NoError(t, err)
+			require.Len(t, reverted, steps)
+
+			applied, err := migrator.Up()
+			require.NoError(t, err)
+			require.Len(t, applied, steps)
+			require.Equal(t, migrated, sqliteSchema(t, dbCrud))
+		})
+	}
+}
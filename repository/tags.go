@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Tag struct {
+	db *gorm.DB
+}
+
+func NewTag(dbCrud *gorm.DB) Tag {
+	return Tag{
+		db: dbCrud,
+	}
+}
+
+type TagInterfaceRepo interface {
+	CreateTag(tag *entity.Tag) error
+	GetTagById(id uint) (entity.Tag, error)
+	GetTagByName(name string) (entity.Tag, error)
+	GetTagsByIds(ids []uint) ([]entity.Tag, error)
+	ListTags(name string, limit int, offset int) ([]entity.Tag, int64, error)
+	UpdateTag(tag *entity.Tag, id uint) error
+	DeleteTag(id uint) error
+	AddCustomerTags(customerId uint, tagIds []uint) error
+	RemoveCustomerTags(customerId uint, tagIds []uint) error
+}
+
+// CreateTag new Tag
+func (repo Tag) CreateTag(tag *entity.Tag) error {
+	err := repo.db.Create(tag).Error
+	return translateError(err, "tag")
+}
+
+// GetTagById get single Tag by id
+func (repo Tag) GetTagById(id uint) (entity.Tag, error) {
+	var tag entity.Tag
+	err := repo.db.First(&tag, "id = ?", id).Error
+	return tag, translateError(err, "tag")
+}
+
+// GetTagByName tag whose name equals name ignoring case
+func (repo Tag) GetTagByName(name string) (entity.Tag, error) {
+	var tag entity.Tag
+	err := repo.db.First(&tag, "LOWER(name) = ?", strings.ToLower(name)).Error
+	return tag, translateError(err, "tag")
+}
+
+// GetTagsByIds tags among ids that exist, in name order
+func (repo Tag) GetTagsByIds(ids []uint) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	err := repo.db.Where("id IN ?", ids).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+// ListTags page of tags in name order, name filters on a part of the name
+func (repo Tag) ListTags(name string, limit int, offset int) ([]entity.Tag, int64, error) {
+	query := repo.db.Model(&entity.Tag{})
+	if name != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(name)+"%")
+	}
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var tags []entity.Tag
+	err = query.Order("name").Limit(limit).Offset(offset).Find(&tags).Error
+	return tags, total, err
+}
+
+// UpdateTag name and color
+func (repo Tag) UpdateTag(tag *entity.Tag, id uint) error {
+	res := repo.db.Model(&entity.Tag{}).Where("id = ?", id).
+		Select("name", "color", "updated_at").
+		Updates(tag)
+	return affectedOrNotFound(res, &entity.Tag{}, "tag", "id = ?", id)
+}
+
+// DeleteTag the tag is taken off every customer too
+func (repo Tag) DeleteTag(id uint) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("tag_id = ?", id).Delete(&entity.CustomerTag{}).Error
+		if err != nil {
+			return err
+		}
+		res := tx.Delete(&entity.Tag{}, id)
+		return affectedOrNotFound(res, &entity.Tag{}, "tag", "id = ?", id)
+	})
+}
+
+// AddCustomerTags tags already on the customer are left as they are
+func (repo Tag) AddCustomerTags(customerId uint, tagIds []uint) error {
+	return addCustomerTags(repo.db, customerId, tagIds)
+}
+
+// RemoveCustomerTags tags not on the customer are ignored
+func (repo Tag) RemoveCustomerTags(customerId uint, tagIds []uint) error {
+	return repo.db.Where("customer_id = ? AND tag_id IN ?", customerId, tagIds).
+		Delete(&entity.CustomerTag{}).Error
+}
+
+func addCustomerTags(tx *gorm.DB, customerId uint, tagIds []uint) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]entity.CustomerTag, len(tagIds))
+	for i, tagId := range tagIds {
+		rows[i] = entity.CustomerTag{Customer_id: customerId, Tag_id: tagId, CreatedAt: now}
+	}
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	return translateError(err, "customer tag")
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedTags(t *testing.T, repo Tag, names ...string) []entity.Tag {
+	t.Helper()
+	var tags []entity.Tag
+	for _, name := range names {
+		tag := entity.Tag{Name: name}
+		require.NoError(t, repo.CreateTag(&tag))
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func TestTag_CRUD(t *testing.T) {
+	repo := NewTag(newTestDB(t))
+	tag := seedTags(t, repo, "VIP")[0]
+
+	found, err := repo.GetTagByName("vip")
+	require.NoError(t, err)
+	assert.Equal(t, tag.ID, found.ID)
+
+	err = repo.CreateTag(&entity.Tag{Name: "VIP"})
+	assert.ErrorIs(t, err, apperror.ErrConflict)
+
+	err = repo.UpdateTag(&entity.Tag{Name: "Gold", Color: "#ffd700"}, tag.ID)
+	require.NoError(t, err)
+	found, err = repo.GetTagById(tag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Gold", found.Name)
+	assert.Equal(t, "#ffd700", found.Color)
+
+	require.NoError(t, repo.DeleteTag(tag.ID))
+	_, err = repo.GetTagById(tag.ID)
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.ErrorIs(t, repo.DeleteTag(tag.ID), apperror.ErrNotFound)
+}
+
+func TestTag_ListTags(t *testing.T) {
+	repo := NewTag(newTestDB(t))
+	seedTags(t, repo, "lead", "vip", "Leader", "churned")
+
+	tags, total, err := repo.ListTags("LEA", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, tags, 1)
+	assert.Equal(t, "lead", tags[0].Name)
+}
+
+func TestTag_CustomerTags(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewTag(dbCrud)
+	customerRepo := NewCustomer(dbCrud)
+	customer := seedCustomers(t, customerRepo, "john")[0]
+	tags := seedTags(t, repo, "vip", "lead")
+
+	require.NoError(t, repo.AddCustomerTags(customer.ID, []uint{tags[0].ID, tags[1].ID}))
+	require.NoError(t, repo.AddCustomerTags(customer.ID, []uint{tags[0].ID}))
+	found, err := customerRepo.GetCustomerById(customer.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Tags, 2)
+	assert.Equal(t, "lead", found.Tags[0].Name)
+
+	require.NoError(t, repo.RemoveCustomerTags(customer.ID, []uint{tags[1].ID, 42}))
+	require.NoError(t, repo.DeleteTag(tags[0].ID))
+	found, err = customerRepo.GetCustomerById(customer.ID)
+	require.NoError(t, err)
+	assert.Empty(t, found.Tags)
+}
+
+func TestCustomer_ListByTagsAndSegment(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	tagRepo := NewTag(dbCrud)
+	seeded := seedCustomers(t, repo, "john", "jane", "jack")
+	tags := seedTags(t, tagRepo, "vip", "lead")
+	require.NoError(t, tagRepo.AddCustomerTags(seeded[0].ID, []uint{tags[0].ID, tags[1].ID}))
+	require.NoError(t, tagRepo.AddCustomerTags(seeded[1].ID, []uint{tags[0].ID}))
+	require.NoError(t, tagRepo.AddCustomerTags(seeded[2].ID, []uint{tags[1].ID}))
+	sort := []SortField{{Column: "id"}}
+
+	customers, total, err := repo.ListCustomers(CustomerFilter{Tag_ids: []uint{tags[0].ID}, Sort: sort, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []uint{seeded[0].ID, seeded[1].ID}, customerIds(customers))
+	assert.Len(t, customers[0].Tags, 2)
+
+	customers, _, err = repo.ListCustomers(CustomerFilter{Tag_ids: []uint{tags[0].ID, tags[1].ID}, Sort: sort, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{seeded[0].ID}, customerIds(customers))
+
+	// the segment is combined with the list's own criteria
+	filter := CustomerFilter{
+		First_name: "ja",
+		Segment:    &CustomerFilter{Tag_ids: []uint{tags[1].ID}},
+		Sort:       sort,
+	}
+	var exported []uint
+	err = repo.ExportCustomers(filter, func(customer entity.Customer) error {
+		exported = append(exported, customer.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{seeded[2].ID}, exported)
+}
+
+func TestCustomer_MergeCustomersMovesTags(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewCustomer(dbCrud)
+	tagRepo := NewTag(dbCrud)
+	seeded := seedCustomers(t, repo, "john", "jon")
+	tags := seedTags(t, tagRepo, "vip", "lead")
+	require.NoError(t, tagRepo.AddCustomerTags(seeded[0].ID, []uint{tags[0].ID}))
+	require.NoError(t, tagRepo.AddCustomerTags(seeded[1].ID, []uint{tags[0].ID, tags[1].ID}))
+
+	survivor, err := repo.GetCustomerById(seeded[0].ID)
+	require.NoError(t, err)
+	err = repo.MergeCustomers(&survivor, []uint{seeded[1].ID}, &entity.AuditLog{Action: entity.AuditActionMerge, Entity_type: "customer"})
+	require.NoError(t, err)
+
+	found, err := repo.GetCustomerById(survivor.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Tags, 2)
+	var rows int64
+	require.NoError(t, dbCrud.Model(&entity.CustomerTag{}).Count(&rows).Error)
+	assert.Equal(t, int64(2), rows)
+}
+
+func customerIds(customers []entity.Customer) []uint {
+	ids := make([]uint, len(customers))
+	for i, customer := range customers {
+		ids[i] = customer.ID
+	}
+	return ids
+}
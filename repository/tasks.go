@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"gorm.io/gorm"
+)
+
+// TaskFilter zero fields match every task, Statuses matches any of them and
+// DueFrom and DueBefore keep tasks due on or after DueFrom and before
+// DueBefore
+type TaskFilter struct {
+	Assignee_id uint
+	Customer_id uint
+	Deal_id     uint
+	Statuses    []string
+	Priority    string
+	DueFrom     *time.Time
+	DueBefore   *time.Time
+	Limit       int
+	Offset      int
+}
+
+type Task struct {
+	db *gorm.DB
+}
+
+func NewTask(dbCrud *gorm.DB) Task {
+	return Task{
+		db: dbCrud,
+	}
+}
+
+type TaskInterfaceRepo interface {
+	CreateTask(task *entity.Task) error
+	GetTaskById(id uint) (entity.Task, error)
+	ListTasks(filter TaskFilter) ([]entity.Task, int64, error)
+	UpdateTask(task *entity.Task, id uint) error
+	DeleteTask(id uint) error
+	ListDueReminders(now time.Time, limit int) ([]entity.Task, error)
+	MarkReminded(id uint, at time.Time) (bool, error)
+	UnmarkReminded(id uint) error
+	ListNewlyOverdue(now time.Time, limit int) ([]entity.Task, error)
+	MarkOverdue(id uint, at time.Time) (bool, error)
+	UnmarkOverdue(id uint) error
+}
+
+// CreateTask new Task
+func (repo Task) CreateTask(task *entity.Task) error {
+	err := repo.db.Create(task).Error
+	return translateError(err, "task")
+}
+
+// GetTaskById get single Task by id
+func (repo Task) GetTaskById(id uint) (entity.Task, error) {
+	var task entity.Task
+	err := repo.db.First(&task, "id = ?", id).Error
+	return task, translateError(err, "task")
+}
+
+// ListTasks page of tasks, the earliest due first
+func (repo Task) ListTasks(filter TaskFilter) ([]entity.Task, int64, error) {
+	query := repo.db.Model(&entity.Task{})
+	if filter.Assignee_id != 0 {
+		query = query.Where("assignee_id = ?", filter.Assignee_id)
+	}
+	if filter.Customer_id != 0 {
+		query = query.Where("customer_id = ?", filter.Customer_id)
+	}
+	if filter.Deal_id != 0 {
+		query = query.Where("deal_id = ?", filter.Deal_id)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Priority != "" {
+		query = query.Where("priority = ?", filter.Priority)
+	}
+	if filter.DueFrom != nil {
+		query = query.Where("due_at >= ?", filter.DueFrom.UTC())
+	}
+	if filter.DueBefore != nil {
+		query = query.Where("due_at < ?", filter.DueBefore.UTC())
+	}
+	var total int64
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []entity.Task
+	err = query.Order("due_at").Order("id").Limit(filter.Limit).Offset(filter.Offset).Find(&tasks).Error
+	return tasks, total, err
+}
+
+// UpdateTask multiple fields, the reminder job columns included so moving the
+// due date or reminder can rearm them
+func (repo Task) UpdateTask(task *entity.Task, id uint) error {
+	res := repo.db.Model(&entity.Task{}).Where("id = ?", id).
+		Select("title", "description", "due_at", "remind_at", "priority", "status", "assignee_id",
+			"customer_id", "deal_id", "completed_at", "reminded_at", "overdue_at", "updated_at").
+		Updates(task)
+	return affectedOrNotFound(res, &entity.Task{}, "task", "id = ?", id)
+}
+
+// DeleteTask delete single Task by id
+func (repo Task) DeleteTask(id uint) error {
+	res := repo.db.Delete(&entity.Task{}, id)
+	return affectedOrNotFound(res, &entity.Task{}, "task", "id = ?", id)
+}
+
+// ListDueReminders up to limit open tasks whose reminder time has come and
+// whose reminder was not sent yet, the earliest reminder first
+func (repo Task) ListDueReminders(now time.Time, limit int) ([]entity.Task, error) {
+	var tasks []entity.Task
+	err := repo.db.
+		Where("remind_at <= ? AND reminded_at IS NULL AND status IN ?", now.UTC(), entity.TaskOpenStatuses).
+		Order("remind_at").Order("id").Limit(limit).Find(&tasks).Error
+	return tasks, err
+}
+
+// MarkReminded record the reminder of the task as sent, false when it already
+// was, by a concurrent run
+func (repo Task) MarkReminded(id uint, at time.Time) (bool, error) {
+	res := repo.db.Model(&entity.Task{}).Where("id = ? AND reminded_at IS NULL", id).
+		Update("reminded_at", at.UTC())
+	return res.RowsAffected == 1, res.Error
+}
+
+// UnmarkReminded let the next run send the reminder again
+func (repo Task) UnmarkReminded(id uint) error {
+	return repo.db.Model(&entity.Task{}).Where("id = ?", id).Update("reminded_at", nil).Error
+}
+
+// ListNewlyOverdue up to limit open tasks past their due time that were not
+// marked overdue yet, the earliest due first
+func (repo Task) ListNewlyOverdue(now time.Time, limit int) ([]entity.Task, error) {
+	var tasks []entity.Task
+	err := repo.db.
+		Where("due_at < ? AND overdue_at IS NULL AND status IN ?", now.UTC(), entity.TaskOpenStatuses).
+		Order("due_at").Order("id").Limit(limit).Find(&tasks).Error
+	return tasks, err
+}
+
+// MarkOverdue mark the task overdue, false when it already was, by a
+// concurrent run
+func (repo Task) MarkOverdue(id uint, at time.Time) (bool, error) {
+	res := repo.db.Model(&entity.Task{}).Where("id = ? AND overdue_at IS NULL", id).
+		Update("overdue_at", at.UTC())
+	return res.RowsAffected == 1, res.Error
+}
+
+// UnmarkOverdue let the next run mark the task overdue again
+func (repo Task) UnmarkOverdue(id uint) error {
+	return repo.db.Model(&entity.Task{}).Where("id = ?", id).Update("overdue_at", nil).Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedTask(t *testing.T, repo Task, task entity.Task) entity.Task {
+	t.Helper()
+	if task.Priority == "" {
+		task.Priority = entity.TaskPriorityNormal
+	}
+	if task.Status == "" {
+		task.Status = entity.TaskStatusOpen
+	}
+	require.NoError(t, repo.CreateTask(&task))
+	return task
+}
+
+func TestTask_CRUD(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewTask(dbCrud)
+	assignee := seedActor(t, NewActor(dbCrud), "alice")
+	customer := seedCustomers(t, NewCustomer(dbCrud), "john")[0]
+	due := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	task := seedTask(t, repo, entity.Task{Title: "Call back", Due_at: due, Assignee_id: &assignee.ID, Customer_id: &customer.ID})
+
+	found, err := repo.GetTaskById(task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Call back", found.Title)
+	assert.True(t, due.Equal(found.Due_at))
+
+	found.Status = entity.TaskStatusDone
+	completed := due.Add(-time.Hour)
+	found.Completed_at = &completed
+	require.NoError(t, repo.UpdateTask(&found, task.ID))
+	found, err = repo.GetTaskById(task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.TaskStatusDone, found.Status)
+	assert.NotNil(t, found.Completed_at)
+
+	require.NoError(t, repo.DeleteTask(task.ID))
+	assert.ErrorIs(t, repo.DeleteTask(task.ID), apperror.ErrNotFound)
+}
+
+func TestTask_ListTasks(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewTask(dbCrud)
+	alice := seedActor(t, NewActor(dbCrud), "alice")
+	bob := seedActor(t, NewActor(dbCrud), "bob")
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	late := seedTask(t, repo, entity.Task{Title: "late", Due_at: day.Add(-time.Hour), Assignee_id: &alice.ID})
+	today := seedTask(t, repo, entity.Task{Title: "today", Due_at: day.Add(10 * time.Hour), Assignee_id: &alice.ID, Priority: entity.TaskPriorityHigh})
+	seedTask(t, repo, entity.Task{Title: "done", Due_at: day.Add(9 * time.Hour), Assignee_id: &alice.ID, Status: entity.TaskStatusDone})
+	seedTask(t, repo, entity.Task{Title: "bob", Due_at: day.Add(8 * time.Hour), Assignee_id: &bob.ID})
+
+	tasks, total, err := repo.ListTasks(TaskFilter{Assignee_id: alice.ID, Statuses: entity.TaskOpenStatuses, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []uint{late.ID, today.ID}, []uint{tasks[0].ID, tasks[1].ID})
+
+	tomorrow := day.AddDate(0, 0, 1)
+	tasks, total, err = repo.ListTasks(TaskFilter{Assignee_id: alice.ID, Statuses: entity.TaskOpenStatuses, DueFrom: &day, DueBefore: &tomorrow, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, today.ID, tasks[0].ID)
+
+	_, total, err = repo.ListTasks(TaskFilter{Priority: entity.TaskPriorityHigh, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestTask_ReminderJobQueries(t *testing.T) {
+	dbCrud := newTestDB(t)
+	repo := NewTask(dbCrud)
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	remindAt := now.Add(-time.Minute)
+	later := now.Add(time.Hour)
+	past := seedTask(t, repo, entity.Task{Title: "past", Due_at: now.Add(-time.Hour)})
+	soon := seedTask(t, repo, entity.Task{Title: "soon", Due_at: later, Remind_at: &remindAt})
+	seedTask(t, repo, entity.Task{Title: "not yet", Due_at: later.Add(time.Hour), Remind_at: &later})
+	seedTask(t, repo, entity.Task{Title: "done", Due_at: now.Add(-time.Hour), Remind_at: &remindAt, Status: entity.TaskStatusDone})
+
+	reminders, err := repo.ListDueReminders(now, 10)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, soon.ID, reminders[0].ID)
+
+	marked, err := repo.MarkReminded(soon.ID, now)
+	require.NoError(t, err)
+	assert.True(t, marked)
+	marked, err = repo.MarkReminded(soon.ID, now)
+	require.NoError(t, err)
+	assert.False(t, marked)
+	reminders, err = repo.ListDueReminders(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, reminders)
+	require.NoError(t, repo.UnmarkReminded(soon.ID))
+	reminders, err = repo.ListDueReminders(now, 10)
+	require.NoError(t, err)
+	assert.Len(t, reminders, 1)
+
+	overdue, err := repo.ListNewlyOverdue(now, 10)
+	require.NoError(t, err)
+	require.Len(t, overdue, 1)
+	assert.Equal(t, past.ID, overdue[0].ID)
+
+	marked, err = repo.MarkOverdue(past.ID, now)
+	require.NoError(t, err)
+	assert.True(t, marked)
+	overdue, err = repo.ListNewlyOverdue(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, overdue)
+}
@@ -1,164 +0,0 @@
--- phpMyAdmin SQL Dump
--- version 5.1.1
--- https://www.phpmyadmin.net/
---
--- Host: 127.0.0.1
--- Waktu pembuatan: 09 Jun 2023 pada 09.29
--- Versi server: 10.4.22-MariaDB
--- Versi PHP: 8.1.1
-
-SET SQL_MODE = "NO_AUTO_VALUE_ON_ZERO";
-START TRANSACTION;
-SET time_zone = "+00:00";
-
-
-/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
-/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
-/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
-/*!40101 SET NAMES utf8mb4 */;
-
---
--- Database: `tugas_sql_bri`
---
-
--- --------------------------------------------------------
-
---
--- Struktur dari tabel `actors`
---
-
-CREATE TABLE `actors` (
-  `id` int(10) UNSIGNED NOT NULL,
-  `username` varchar(16) DEFAULT NULL,
-  `password` varchar(255) DEFAULT NULL,
-  `role_id` int(10) UNSIGNED DEFAULT NULL,
-  `verified` tinyint(1) DEFAULT NULL,
-  `active` tinyint(1) DEFAULT NULL,
-  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
-  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
-
---
--- Dumping data untuk tabel `actors`
---
-
-INSERT INTO `actors` (`id`, `username`, `password`, `role_id`, `verified`, `active`, `created_at`, `updated_at`) VALUES
-(2, 'superadmin', 'password123', 1, 1, 1, '2023-05-30 07:31:08', '2023-05-30 07:31:08'),
-(3, 'kamal', '123456', 1, 1, 1, '2023-06-06 09:06:21', '2023-06-06 09:18:50');
-
--- --------------------------------------------------------
-
---
--- Struktur dari tabel `customer`
---
-
-CREATE TABLE `customer` (
-  `id` int(10) UNSIGNED NOT NULL,
-  `first_name` varchar(255) DEFAULT NULL,
-  `last_name` varchar(255) DEFAULT NULL,
-  `email` varchar(255) DEFAULT NULL,
-  `avatar` varchar(255) DEFAULT '',
-  `created_at` timestamp NOT NULL DEFAULT current_timestamp(),
-  `updated_at` timestamp NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp()
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
-
--- --------------------------------------------------------
-
---
--- Struktur dari tabel `register_approval`
---
-
-CREATE TABLE `register_approval` (
-  `id` int(10) UNSIGNED NOT NULL,
-  `admin_id` int(10) UNSIGNED DEFAULT NULL,
-  `super_admin_id` int(10) UNSIGNED DEFAULT NULL,
-  `status` varchar(255) DEFAULT NULL
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
-
--- --------------------------------------------------------
-
---
--- Struktur dari tabel `role`
---
-
-CREATE TABLE `role` (
-  `id_role` int(10) UNSIGNED NOT NULL,
-  `role_name` varchar(50) DEFAULT ''
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
-
---
--- Dumping data untuk tabel `role`
---
-
-INSERT INTO `role` (`id_role`, `role_name`) VALUES
-(1, 'super admin');
-
---
--- Indexes for dumped tables
---
-
---
--- Indeks untuk tabel `actors`
---
-ALTER TABLE `actors`
-  ADD PRIMARY KEY (`id`),
-  ADD UNIQUE KEY `username` (`username`),
-  ADD KEY `fk_actors_role` (`role_id`);
-
---
--- Indeks untuk tabel `customer`
---
-ALTER TABLE `customer`
-  ADD PRIMARY KEY (`id`);
-
---
--- Indeks untuk tabel `register_approval`
---
-ALTER TABLE `register_approval`
-  ADD PRIMARY KEY (`id`),
-  ADD KEY `fk_admin_role` (`admin_id`),
-  ADD KEY `fk_super_admin_role` (`super_admin_id`);
-
---
--- Indeks untuk tabel `role`
---
-ALTER TABLE `role`
-  ADD PRIMARY KEY (`id_role`);
-
---
--- AUTO_INCREMENT untuk tabel yang dibuang
---
-
---
--- AUTO_INCREMENT untuk tabel `actors`
---
-ALTER TABLE `actors`
-  MODIFY `id` int(10) UNSIGNED NOT NULL AUTO_INCREMENT, AUTO_INCREMENT=6;
-
---
--- AUTO_INCREMENT untuk tabel `role`
---
-ALTER TABLE `role`
-  MODIFY `id_role` int(10) UNSIGNED NOT NULL AUTO_INCREMENT, AUTO_INCREMENT=2;
-
---
--- Ketidakleluasaan untuk tabel pelimpahan (Dumped Tables)
---
-
---
--- Ketidakleluasaan untuk tabel `actors`
---
-ALTER TABLE `actors`
-  ADD CONSTRAINT `fk_actors_role` FOREIGN KEY (`role_id`) REFERENCES `role` (`id_role`);
-
---
--- Ketidakleluasaan untuk tabel `register_approval`
---
-ALTER TABLE `register_approval`
-  ADD CONSTRAINT `fk_admin_role` FOREIGN KEY (`admin_id`) REFERENCES `actors` (`id`),
-  ADD CONSTRAINT `fk_super_admin_role` FOREIGN KEY (`super_admin_id`) REFERENCES `actors` (`id`);
-COMMIT;
-
-/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
-/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
-/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
@@ -0,0 +1,48 @@
+package apperror
+
+import "errors"
+
+// Kinds of domain error, match with errors.Is(err, apperror.ErrNotFound)
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
+
+// Error domain error safe to show to the client, Details carries extra
+// structured data such as invalid fields or the conflicting record
+type Error struct {
+	Kind    error
+	Message string
+	Details any
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NotFound(message string) *Error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+func Conflict(message string, details any) *Error {
+	return &Error{Kind: ErrConflict, Message: message, Details: details}
+}
+
+func Validation(message string, details any) *Error {
+	return &Error{Kind: ErrValidation, Message: message, Details: details}
+}
+
+func Unauthorized(message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+func Forbidden(message string) *Error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
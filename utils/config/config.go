@@ -0,0 +1,479 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server     Server     `yaml:"server"`
+	Database   Database   `yaml:"database"`
+	JWT        JWT        `yaml:"jwt"`
+	Log        Log        `yaml:"log"`
+	Trash      Trash      `yaml:"trash"`
+	Storage    Storage    `yaml:"storage"`
+	Avatar     Avatar     `yaml:"avatar"`
+	Account    Account    `yaml:"account"`
+	Pipeline   Pipeline   `yaml:"pipeline"`
+	Reminder   Reminder   `yaml:"reminder"`
+	Duplicates Duplicates `yaml:"duplicates"`
+}
+
+type Server struct {
+	Address string `yaml:"address"`
+}
+
+type Database struct {
+	// Driver mysql, postgres or sqlite
+	Driver          string        `yaml:"driver"`
+	DSN             string        `yaml:"dsn"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	// AutoMigrate apply pending migrations before serving
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+type JWT struct {
+	// Secret HS256 key, only required when no SigningKeyFile is set
+	Secret string `yaml:"secret"`
+	// SigningKeyFile PEM RSA (RS256) or P-256 EC (ES256) private key
+	SigningKeyFile string `yaml:"signing_key_file"`
+	// PreviousKeyFiles PEM keys of rotated out signing keys, tokens they
+	// signed are still accepted until they expire
+	PreviousKeyFiles []string      `yaml:"previous_key_files"`
+	AccessTTL        time.Duration `yaml:"access_ttl"`
+	// RefreshTTL lifetime of a refresh token, each refresh issues a new one
+	RefreshTTL time.Duration `yaml:"refresh_ttl"`
+}
+
+type Log struct {
+	// Level debug, info, warn or error, debug also puts gin in debug mode
+	Level string `yaml:"level"`
+	// Format text or json for the request log
+	Format string `yaml:"format"`
+}
+
+type Trash struct {
+	// Retention how long deleted customers and actors stay restorable before
+	// the purge job removes them for good, 0 keeps them forever
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+type Storage struct {
+	// Driver local or s3, where uploaded files such as avatars are kept
+	Driver string `yaml:"driver"`
+	// Path root directory of the local driver
+	Path string `yaml:"path"`
+	S3   S3     `yaml:"s3"`
+}
+
+type S3 struct {
+	// Endpoint base URL of AWS S3 or any compatible service such as MinIO,
+	// buckets are addressed path style
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+type Avatar struct {
+	// MaxSize largest accepted upload in bytes
+	MaxSize int `yaml:"max_size"`
+}
+
+// Policies for deleting an account that still has contacts
+const (
+	AccountDeleteBlock   = "block"
+	AccountDeleteCascade = "cascade"
+)
+
+type Account struct {
+	// DeletePolicy block refuses to delete an account with contacts,
+	// cascade moves its contacts to the trash along with it
+	DeletePolicy string `yaml:"delete_policy"`
+}
+
+type Pipeline struct {
+	// Stages in pipeline order, a new deal starts in the first one
+	Stages []Stage `yaml:"stages"`
+}
+
+type Stage struct {
+	Name string `yaml:"name"`
+	// Probability chance in percent that a deal in this stage is won, it
+	// weighs the value of the pipeline summary
+	Probability int `yaml:"probability"`
+	// Next stages a deal in this stage may move to, none closes the deal
+	Next []string `yaml:"next"`
+}
+
+type Reminder struct {
+	// Interval how often the reminder job marks tasks overdue and sends due
+	// reminders, 0 turns the job off
+	Interval time.Duration `yaml:"interval"`
+	// Notifier log or webhook, how reminders reach the assignee
+	Notifier string `yaml:"notifier"`
+	// WebhookURL receives every reminder as a JSON POST with the webhook
+	// notifier
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+type Duplicates struct {
+	// ScanInterval how often every customer is compared for the duplicates
+	// report, 0 turns the job off and only the first request scans
+	ScanInterval time.Duration `yaml:"scan_interval"`
+}
+
+const minSecretLength = 16
+
+// Default values used for anything the file, environment and flags leave empty
+func Default() Config {
+	return Config{
+		Server: Server{
+			Address: ":8081",
+		},
+		Database: Database{
+			Driver:          "mysql",
+			DSN:             "root@tcp(127.0.0.1:3306)/tugas_sql_bri?parseTime=true",
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: time.Hour,
+		},
+		JWT: JWT{
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 7 * 24 * time.Hour,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "text",
+		},
+		Trash: Trash{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Storage: Storage{
+			Driver: "local",
+			Path:   "data/storage",
+			S3: S3{
+				Region: "us-east-1",
+			},
+		},
+		Avatar: Avatar{
+			MaxSize: 5 << 20,
+		},
+		Account: Account{
+			DeletePolicy: AccountDeleteBlock,
+		},
+		Pipeline: Pipeline{
+			Stages: []Stage{
+				{Name: "lead", Probability: 10, Next: []string{"qualified", "lost"}},
+				{Name: "qualified", Probability: 25, Next: []string{"proposal", "lost"}},
+				{Name: "proposal", Probability: 50, Next: []string{"negotiation", "won", "lost"}},
+				{Name: "negotiation", Probability: 75, Next: []string{"proposal", "won", "lost"}},
+				{Name: "won", Probability: 100},
+				{Name: "lost", Probability: 0, Next: []string{"lead"}},
+			},
+		},
+		Reminder: Reminder{
+			Interval: time.Minute,
+			Notifier: "log",
+		},
+		Duplicates: Duplicates{
+			ScanInterval: time.Hour,
+		},
+	}
+}
+
+// Load build the configuration from, in increasing priority, defaults, the
+// YAML file given by -config or CRM_CONFIG_FILE, CRM_* environment variables
+// and command line flags. Arguments left after the flags stay in fs.Args()
+func Load(fs *flag.FlagSet, args []string) (Config, error) {
+	cfg := Default()
+
+	configFile := fs.String("config", os.Getenv("CRM_CONFIG_FILE"), "path to YAML config file")
+	address := fs.String("addr", "", "HTTP listen address")
+	driver := fs.String("db-driver", "", "database driver: mysql, postgres or sqlite")
+	dsn := fs.String("db-dsn", "", "database DSN")
+	secret := fs.String("jwt-secret", "", "JWT signing secret")
+	signingKey := fs.String("jwt-signing-key", "", "PEM private key file for RS256 or ES256 tokens")
+	logLevel := fs.String("log-level", "", "log level: debug, info, warn or error")
+	err := fs.Parse(args)
+	if err != nil {
+		return cfg, err
+	}
+
+	if *configFile != "" {
+		err = loadFile(&cfg, *configFile)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	err = loadEnv(&cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	setString(&cfg.Server.Address, *address)
+	setString(&cfg.Database.Driver, *driver)
+	setString(&cfg.Database.DSN, *dsn)
+	setString(&cfg.JWT.Secret, *secret)
+	setString(&cfg.JWT.SigningKeyFile, *signingKey)
+	setString(&cfg.Log.Level, *logLevel)
+
+	return cfg, cfg.Validate()
+}
+
+// Validate report every missing or malformed value at once
+func (cfg Config) Validate() error {
+	var errs []error
+	if cfg.Server.Address == "" {
+		errs = append(errs, errors.New("server address is required (CRM_SERVER_ADDRESS)"))
+	}
+	switch cfg.Database.Driver {
+	case "mysql", "postgres", "sqlite":
+	default:
+		errs = append(errs, fmt.Errorf("unknown database driver %q (CRM_DB_DRIVER)", cfg.Database.Driver))
+	}
+	if cfg.Database.DSN == "" {
+		errs = append(errs, errors.New("database dsn is required (CRM_DB_DSN)"))
+	}
+	if (cfg.JWT.SigningKeyFile == "" || cfg.JWT.Secret != "") && len(cfg.JWT.Secret) < minSecretLength {
+		errs = append(errs, fmt.Errorf("jwt secret of at least %d characters is required (CRM_JWT_SECRET), or a signing key file (CRM_JWT_SIGNING_KEY_FILE)", minSecretLength))
+	}
+	if cfg.JWT.AccessTTL <= 0 {
+		errs = append(errs, errors.New("jwt access ttl must be positive (CRM_JWT_ACCESS_TTL)"))
+	}
+	if cfg.JWT.RefreshTTL <= cfg.JWT.AccessTTL {
+		errs = append(errs, errors.New("jwt refresh ttl must be longer than the access ttl (CRM_JWT_REFRESH_TTL)"))
+	}
+	switch cfg.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("unknown log level %q (CRM_LOG_LEVEL)", cfg.Log.Level))
+	}
+	switch cfg.Log.Format {
+	case "text", "json":
+	default:
+		errs = append(errs, fmt.Errorf("unknown log format %q (CRM_LOG_FORMAT)", cfg.Log.Format))
+	}
+	if cfg.Trash.Retention < 0 {
+		errs = append(errs, errors.New("trash retention must not be negative (CRM_TRASH_RETENTION)"))
+	}
+	if cfg.Trash.Retention > 0 && cfg.Trash.PurgeInterval <= 0 {
+		errs = append(errs, errors.New("trash purge interval must be positive (CRM_TRASH_PURGE_INTERVAL)"))
+	}
+	switch cfg.Storage.Driver {
+	case "local":
+		if cfg.Storage.Path == "" {
+			errs = append(errs, errors.New("storage path is required for the local driver (CRM_STORAGE_PATH)"))
+		}
+	case "s3":
+		s3 := cfg.Storage.S3
+		if s3.Endpoint == "" || s3.Region == "" || s3.Bucket == "" || s3.AccessKey == "" || s3.SecretKey == "" {
+			errs = append(errs, errors.New("s3 endpoint, region, bucket, access key and secret key are required for the s3 driver (CRM_S3_*)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown storage driver %q (CRM_STORAGE_DRIVER)", cfg.Storage.Driver))
+	}
+	if cfg.Avatar.MaxSize <= 0 {
+		errs = append(errs, errors.New("avatar max size must be positive (CRM_AVATAR_MAX_SIZE)"))
+	}
+	switch cfg.Account.DeletePolicy {
+	case AccountDeleteBlock, AccountDeleteCascade:
+	default:
+		errs = append(errs, fmt.Errorf("unknown account delete policy %q (CRM_ACCOUNT_DELETE_POLICY)", cfg.Account.DeletePolicy))
+	}
+	errs = append(errs, cfg.Pipeline.validate()...)
+	if cfg.Reminder.Interval < 0 {
+		errs = append(errs, errors.New("reminder interval must not be negative (CRM_REMINDER_INTERVAL)"))
+	}
+	switch cfg.Reminder.Notifier {
+	case "log":
+	case "webhook":
+		if cfg.Reminder.WebhookURL == "" {
+			errs = append(errs, errors.New("reminder webhook url is required for the webhook notifier (CRM_REMINDER_WEBHOOK_URL)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown reminder notifier %q (CRM_REMINDER_NOTIFIER)", cfg.Reminder.Notifier))
+	}
+	if cfg.Duplicates.ScanInterval < 0 {
+		errs = append(errs, errors.New("duplicate scan interval must not be negative (CRM_DUPLICATES_SCAN_INTERVAL)"))
+	}
+	return errors.Join(errs...)
+}
+
+// validate stage names are unique and every next stage is one of them
+func (pipeline Pipeline) validate() []error {
+	if len(pipeline.Stages) == 0 {
+		return []error{errors.New("pipeline needs at least one stage")}
+	}
+	var errs []error
+	names := make(map[string]bool, len(pipeline.Stages))
+	for _, stage := range pipeline.Stages {
+		switch {
+		case stage.Name == "":
+			errs = append(errs, errors.New("pipeline stage name is required"))
+		case len(stage.Name) > 64:
+			errs = append(errs, fmt.Errorf("pipeline stage name %q is longer than 64 characters", stage.Name))
+		case names[stage.Name]:
+			errs = append(errs, fmt.Errorf("pipeline stage %q is defined twice", stage.Name))
+		}
+		names[stage.Name] = true
+		if stage.Probability < 0 || stage.Probability > 100 {
+			errs = append(errs, fmt.Errorf("pipeline stage %q probability must be between 0 and 100", stage.Name))
+		}
+	}
+	for _, stage := range pipeline.Stages {
+		for _, next := range stage.Next {
+			if !names[next] {
+				errs = append(errs, fmt.Errorf("pipeline stage %q moves to unknown stage %q", stage.Name, next))
+			}
+			if next == stage.Name {
+				errs = append(errs, fmt.Errorf("pipeline stage %q moves to itself", stage.Name))
+			}
+		}
+	}
+	return errs
+}
+
+// Stage stage with name, false when the pipeline has none
+func (pipeline Pipeline) Stage(name string) (Stage, bool) {
+	for _, stage := range pipeline.Stages {
+		if stage.Name == name {
+			return stage, true
+		}
+	}
+	return Stage{}, false
+}
+
+// CanMove whether a deal in stage from may move to stage to
+func (pipeline Pipeline) CanMove(from string, to string) bool {
+	stage, ok := pipeline.Stage(from)
+	if !ok {
+		return false
+	}
+	for _, next := range stage.Next {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func loadFile(cfg *Config, path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	err = yaml.Unmarshal(raw, cfg)
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	setString(&cfg.Server.Address, os.Getenv("CRM_SERVER_ADDRESS"))
+	setString(&cfg.Database.Driver, os.Getenv("CRM_DB_DRIVER"))
+	setString(&cfg.Database.DSN, os.Getenv("CRM_DB_DSN"))
+	setString(&cfg.JWT.Secret, os.Getenv("CRM_JWT_SECRET"))
+	setString(&cfg.JWT.SigningKeyFile, os.Getenv("CRM_JWT_SIGNING_KEY_FILE"))
+	setList(&cfg.JWT.PreviousKeyFiles, os.Getenv("CRM_JWT_PREVIOUS_KEY_FILES"))
+	setString(&cfg.Log.Level, os.Getenv("CRM_LOG_LEVEL"))
+	setString(&cfg.Log.Format, os.Getenv("CRM_LOG_FORMAT"))
+	setString(&cfg.Storage.Driver, os.Getenv("CRM_STORAGE_DRIVER"))
+	setString(&cfg.Storage.Path, os.Getenv("CRM_STORAGE_PATH"))
+	setString(&cfg.Storage.S3.Endpoint, os.Getenv("CRM_S3_ENDPOINT"))
+	setString(&cfg.Storage.S3.Region, os.Getenv("CRM_S3_REGION"))
+	setString(&cfg.Storage.S3.Bucket, os.Getenv("CRM_S3_BUCKET"))
+	setString(&cfg.Storage.S3.AccessKey, os.Getenv("CRM_S3_ACCESS_KEY"))
+	setString(&cfg.Storage.S3.SecretKey, os.Getenv("CRM_S3_SECRET_KEY"))
+	setString(&cfg.Account.DeletePolicy, os.Getenv("CRM_ACCOUNT_DELETE_POLICY"))
+	setString(&cfg.Reminder.Notifier, os.Getenv("CRM_REMINDER_NOTIFIER"))
+	setString(&cfg.Reminder.WebhookURL, os.Getenv("CRM_REMINDER_WEBHOOK_URL"))
+
+	var errs []error
+	errs = append(errs, setInt(&cfg.Database.MaxOpenConns, "CRM_DB_MAX_OPEN_CONNS"))
+	errs = append(errs, setInt(&cfg.Database.MaxIdleConns, "CRM_DB_MAX_IDLE_CONNS"))
+	errs = append(errs, setDuration(&cfg.Database.ConnMaxLifetime, "CRM_DB_CONN_MAX_LIFETIME"))
+	errs = append(errs, setBool(&cfg.Database.AutoMigrate, "CRM_DB_AUTO_MIGRATE"))
+	errs = append(errs, setDuration(&cfg.JWT.AccessTTL, "CRM_JWT_ACCESS_TTL"))
+	errs = append(errs, setDuration(&cfg.JWT.RefreshTTL, "CRM_JWT_REFRESH_TTL"))
+	errs = append(errs, setDuration(&cfg.Trash.Retention, "CRM_TRASH_RETENTION"))
+	errs = append(errs, setDuration(&cfg.Trash.PurgeInterval, "CRM_TRASH_PURGE_INTERVAL"))
+	errs = append(errs, setInt(&cfg.Avatar.MaxSize, "CRM_AVATAR_MAX_SIZE"))
+	errs = append(errs, setDuration(&cfg.Reminder.Interval, "CRM_REMINDER_INTERVAL"))
+	errs = append(errs, setDuration(&cfg.Duplicates.ScanInterval, "CRM_DUPLICATES_SCAN_INTERVAL"))
+	return errors.Join(errs...)
+}
+
+func setString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+// setList comma separated value, blank entries are dropped
+func setList(target *[]string, value string) {
+	if value == "" {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	*target = list
+}
+
+func setInt(target *int, env string) error {
+	value := os.Getenv(env)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func setBool(target *bool, env string) error {
+	value := os.Getenv(env)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+	*target = parsed
+	return nil
+}
+
+func setDuration(target *time.Duration, env string) error {
+	value := os.Getenv(env)
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", env, err)
+	}
+	*target = parsed
+	return nil
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Precedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte(`
+server:
+  address: ":9000"
+database:
+  dsn: "from-file"
+jwt:
+  secret: "file-secret-0123456789"
+  access_ttl: 15m
+`), 0o600)
+	assert.NoError(t, err)
+
+	t.Setenv("CRM_CONFIG_FILE", file)
+	t.Setenv("CRM_DB_DSN", "from-env")
+	t.Setenv("CRM_LOG_LEVEL", "warn")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg, err := Load(fs, []string{"-log-level", "debug", "migrate"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ":9000", cfg.Server.Address)
+	assert.Equal(t, "from-env", cfg.Database.DSN)
+	assert.Equal(t, "file-secret-0123456789", cfg.JWT.Secret)
+	assert.Equal(t, 15*time.Minute, cfg.JWT.AccessTTL)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, []string{"migrate"}, fs.Args())
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "short")
+	t.Setenv("CRM_LOG_FORMAT", "xml")
+
+	_, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+
+	assert.ErrorContains(t, err, "jwt secret")
+	assert.ErrorContains(t, err, "log format")
+}
+
+func TestLoad_InvalidDuration(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "env-secret-0123456789")
+	t.Setenv("CRM_JWT_ACCESS_TTL", "an hour")
+
+	_, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+
+	assert.ErrorContains(t, err, "CRM_JWT_ACCESS_TTL")
+}
+
+func TestLoad_Driver(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "env-secret-0123456789")
+	t.Setenv("CRM_DB_DRIVER", "postgres")
+
+	cfg, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), []string{"-db-driver", "sqlite"})
+	assert.NoError(t, err)
+	assert.Equal(t, "sqlite", cfg.Database.Driver)
+
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), []string{"-db-driver", "oracle"})
+	assert.ErrorContains(t, err, "database driver")
+}
+
+func TestLoad_SigningKeyFile(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "")
+	t.Setenv("CRM_JWT_SIGNING_KEY_FILE", "/keys/current.pem")
+	t.Setenv("CRM_JWT_PREVIOUS_KEY_FILES", "/keys/old.pem, ,/keys/older.pem")
+
+	cfg, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/keys/current.pem", cfg.JWT.SigningKeyFile)
+	assert.Equal(t, []string{"/keys/old.pem", "/keys/older.pem"}, cfg.JWT.PreviousKeyFiles)
+}
+
+func TestLoad_TrashRetention(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "env-secret-0123456789")
+
+	cfg, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, cfg.Trash.Retention)
+
+	t.Setenv("CRM_TRASH_RETENTION", "0")
+	t.Setenv("CRM_TRASH_PURGE_INTERVAL", "0")
+	cfg, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Zero(t, cfg.Trash.Retention)
+
+	t.Setenv("CRM_TRASH_RETENTION", "-1h")
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.ErrorContains(t, err, "trash retention")
+}
+
+func TestLoad_Storage(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "env-secret-0123456789")
+
+	cfg, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "local", cfg.Storage.Driver)
+	assert.Equal(t, 5<<20, cfg.Avatar.MaxSize)
+
+	t.Setenv("CRM_STORAGE_DRIVER", "s3")
+	t.Setenv("CRM_S3_ENDPOINT", "http://127.0.0.1:9000")
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.ErrorContains(t, err, "s3 endpoint, region, bucket")
+
+	t.Setenv("CRM_S3_BUCKET", "crm")
+	t.Setenv("CRM_S3_ACCESS_KEY", "minio")
+	t.Setenv("CRM_S3_SECRET_KEY", "minio-secret")
+	cfg, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", cfg.Storage.S3.Region)
+
+	t.Setenv("CRM_STORAGE_DRIVER", "ftp")
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.ErrorContains(t, err, "unknown storage driver")
+}
+
+func TestLoad_AccountDeletePolicy(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "env-secret-0123456789")
+
+	cfg, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountDeleteBlock, cfg.Account.DeletePolicy)
+
+	t.Setenv("CRM_ACCOUNT_DELETE_POLICY", "cascade")
+	cfg, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountDeleteCascade, cfg.Account.DeletePolicy)
+
+	t.Setenv("CRM_ACCOUNT_DELETE_POLICY", "orphan")
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.ErrorContains(t, err, "unknown account delete policy")
+}
+
+func TestLoad_PipelineStages(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte(`
+jwt:
+  secret: "file-secret-0123456789"
+pipeline:
+  stages:
+    - {name: new, probability: 20, next: [won, lost]}
+    - {name: won, probability: 100}
+    - {name: lost}
+`), 0o600)
+	assert.NoError(t, err)
+	t.Setenv("CRM_CONFIG_FILE", file)
+
+	cfg, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Pipeline.Stages, 3)
+	assert.True(t, cfg.Pipeline.CanMove("new", "won"))
+	assert.False(t, cfg.Pipeline.CanMove("won", "lost"))
+	assert.False(t, cfg.Pipeline.CanMove("open", "won"))
+
+	err = os.WriteFile(file, []byte(`
+jwt:
+  secret: "file-secret-0123456789"
+pipeline:
+  stages:
+    - {name: new, probability: 120, next: [won, new]}
+    - {name: new}
+`), 0o600)
+	assert.NoError(t, err)
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.ErrorContains(t, err, `pipeline stage "new" probability must be between 0 and 100`)
+	assert.ErrorContains(t, err, `pipeline stage "new" is defined twice`)
+	assert.ErrorContains(t, err, `pipeline stage "new" moves to unknown stage "won"`)
+	assert.ErrorContains(t, err, `pipeline stage "new" moves to itself`)
+}
+
+func TestLoad_Reminder(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "env-secret-0123456789")
+
+	cfg, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.Reminder.Interval)
+	assert.Equal(t, "log", cfg.Reminder.Notifier)
+
+	t.Setenv("CRM_REMINDER_NOTIFIER", "webhook")
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.ErrorContains(t, err, "reminder webhook url is required")
+
+	t.Setenv("CRM_REMINDER_WEBHOOK_URL", "https://hooks.example.com/crm")
+	t.Setenv("CRM_REMINDER_INTERVAL", "30s")
+	cfg, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.Reminder.Interval)
+	assert.Equal(t, "https://hooks.example.com/crm", cfg.Reminder.WebhookURL)
+
+	t.Setenv("CRM_REMINDER_NOTIFIER", "sms")
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.ErrorContains(t, err, `unknown reminder notifier "sms"`)
+}
+
+func TestLoad_Duplicates(t *testing.T) {
+	t.Setenv("CRM_CONFIG_FILE", "")
+	t.Setenv("CRM_JWT_SECRET", "env-secret-0123456789")
+
+	cfg, err := Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, cfg.Duplicates.ScanInterval)
+
+	t.Setenv("CRM_DUPLICATES_SCAN_INTERVAL", "0")
+	cfg, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.NoError(t, err)
+	assert.Zero(t, cfg.Duplicates.ScanInterval)
+
+	t.Setenv("CRM_DUPLICATES_SCAN_INTERVAL", "-1m")
+	_, err = Load(flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	assert.ErrorContains(t, err, "duplicate scan interval must not be negative")
+}
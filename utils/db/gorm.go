@@ -1,20 +1,59 @@
 package db
 
 import (
-	"log"
+	"fmt"
+	"time"
 
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func GormMysql() *gorm.DB {
-	// Original localhost
-	db, err := gorm.Open(mysql.Open("root@tcp(127.0.0.1:3306)/tugas_sql_bri"), &gorm.Config{})
-	// Docker localhost
-	// db, err := gorm.Open(mysql.Open("root@tcp(host.docker.internal:3306)/tugas_sql_bri"), &gorm.Config{})
+// Open connect to the database selected by cfg.Driver
+func Open(cfg config.Database) (*gorm.DB, error) {
+	dialector, err := newDialector(cfg)
 	if err != nil {
-		log.Println("gorm.open", err)
+		return nil, err
 	}
-	return db
 
+	db, err := gorm.Open(dialector, &gorm.Config{
+		TranslateError: true,
+		// keep every backend on UTC, postgres and sqlite timestamps carry no zone
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	if cfg.Driver == "sqlite" {
+		// sqlite allows a single writer, and every new connection to
+		// ":memory:" would get its own empty database, so keep exactly one
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
+	return db, nil
+}
+
+func newDialector(cfg config.Database) (gorm.Dialector, error) {
+	switch cfg.Driver {
+	case "mysql":
+		return mysql.Open(cfg.DSN), nil
+	case "postgres":
+		return postgres.Open(cfg.DSN), nil
+	case "sqlite":
+		return sqlite.Open(cfg.DSN), nil
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
 }
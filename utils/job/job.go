@@ -0,0 +1,38 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Func one run of a background job
+type Func func(ctx context.Context) error
+
+// Every run fn right away and then every interval until ctx is done. Runs
+// never overlap, a failed or panicking run is logged and the next tick
+// tries again
+func Every(ctx context.Context, name string, interval time.Duration, fn Func) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		run(ctx, name, fn)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func run(ctx context.Context, name string, fn Func) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("job %s panicked: %v", name, recovered)
+		}
+	}()
+	err := fn(ctx)
+	if err != nil {
+		log.Printf("job %s failed: %v", name, err)
+	}
+}
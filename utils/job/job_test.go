@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var runs atomic.Int32
+	done := make(chan struct{})
+
+	go func() {
+		Every(ctx, "test", time.Millisecond, func(ctx context.Context) error {
+			n := runs.Add(1)
+			if n == 1 {
+				return errors.New("first run fails")
+			}
+			if n == 2 {
+				panic("second run panics")
+			}
+			if n == 3 {
+				cancel()
+			}
+			return nil
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after cancel")
+	}
+	assert.Equal(t, int32(3), runs.Load())
+}
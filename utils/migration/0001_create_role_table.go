@@ -0,0 +1,49 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type roleV1 struct {
+	IdRole   uint32 `gorm:"column:id_role;primaryKey;autoIncrement"`
+	RoleName string `gorm:"column:role_name;size:50;default:''"`
+}
+
+func (roleV1) TableName() string {
+	return "role"
+}
+
+var createRoleTable = Migration{
+	Version: 1,
+	Name:    "create_role_table",
+	Up: func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&roleV1{})
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&[]roleV1{
+			{IdRole: 1, RoleName: "super admin"},
+			{IdRole: 2, RoleName: "admin"},
+		}).Error
+		if err != nil {
+			return err
+		}
+		return syncSequence(tx, "role", "id_role")
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&roleV1{})
+	},
+}
+
+// syncSequence move a postgres serial sequence past rows seeded with explicit
+// ids, other backends derive the next id from the table itself
+func syncSequence(tx *gorm.DB, table, column string) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Exec(
+		"SELECT setval(pg_get_serial_sequence(?, ?), (SELECT MAX("+column+") FROM "+table+"))",
+		table, column,
+	).Error
+}
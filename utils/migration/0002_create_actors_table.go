@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type actorV1 struct {
+	ID        uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	Username  *string   `gorm:"column:username;size:16;uniqueIndex:username"`
+	Password  *string   `gorm:"column:password;size:255"`
+	RoleId    *uint32   `gorm:"column:role_id;index:fk_actors_role"`
+	Verified  *int8     `gorm:"column:verified"`
+	Active    *int8     `gorm:"column:active"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Role      *roleV1   `gorm:"foreignKey:RoleId;references:IdRole"`
+}
+
+func (actorV1) TableName() string {
+	return "actors"
+}
+
+var createActorsTable = Migration{
+	Version: 2,
+	Name:    "create_actors_table",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&actorV1{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&actorV1{})
+	},
+}
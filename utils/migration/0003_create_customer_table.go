@@ -0,0 +1,41 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type customerV1 struct {
+	ID        uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	FirstName *string   `gorm:"column:first_name;size:255"`
+	LastName  *string   `gorm:"column:last_name;size:255"`
+	Email     *string   `gorm:"column:email;size:255"`
+	Avatar    *string   `gorm:"column:avatar;size:255;default:''"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (customerV1) TableName() string {
+	return "customer"
+}
+
+var createCustomerTable = Migration{
+	Version: 3,
+	Name:    "create_customer_table",
+	Up: func(tx *gorm.DB) error {
+		existed := tx.Migrator().HasTable(&customerV1{})
+		err := tx.AutoMigrate(&customerV1{})
+		if err != nil {
+			return err
+		}
+		// the phpMyAdmin dump created customer.id without AUTO_INCREMENT
+		if existed && tx.Dialector.Name() == "mysql" {
+			return tx.Migrator().AlterColumn(&customerV1{}, "ID")
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&customerV1{})
+	},
+}
@@ -0,0 +1,33 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type registerApprovalV1 struct {
+	ID           uint32     `gorm:"column:id;primaryKey;autoIncrement"`
+	AdminId      *uint32    `gorm:"column:admin_id;index:fk_admin_role"`
+	SuperAdminId *uint32    `gorm:"column:super_admin_id;index:fk_super_admin_role"`
+	Status       *string    `gorm:"column:status;size:255;default:pending"`
+	CreatedAt    time.Time  `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DecidedAt    *time.Time `gorm:"column:decided_at"`
+	Admin        *actorV1   `gorm:"foreignKey:AdminId"`
+	SuperAdmin   *actorV1   `gorm:"foreignKey:SuperAdminId"`
+}
+
+func (registerApprovalV1) TableName() string {
+	return "register_approval"
+}
+
+var createRegisterApprovalTable = Migration{
+	Version: 4,
+	Name:    "create_register_approval_table",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&registerApprovalV1{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&registerApprovalV1{})
+	},
+}
@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type rolePermissionV1 struct {
+	ID         uint32  `gorm:"column:id;primaryKey;autoIncrement"`
+	RoleId     uint32  `gorm:"column:role_id;not null;uniqueIndex:uq_role_permission"`
+	Permission string  `gorm:"column:permission;size:100;not null;uniqueIndex:uq_role_permission"`
+	Role       *roleV1 `gorm:"foreignKey:RoleId;references:IdRole"`
+}
+
+func (rolePermissionV1) TableName() string {
+	return "role_permission"
+}
+
+var createRolePermissionTable = Migration{
+	Version: 5,
+	Name:    "create_role_permission_table",
+	Up: func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&rolePermissionV1{})
+		if err != nil {
+			return err
+		}
+		return grantPermissions(tx, map[uint32][]string{
+			1: {
+				"actor:read", "actor:update", "actor:delete",
+				"customer:create", "customer:read", "customer:update", "customer:delete",
+				"approval:manage",
+			},
+			2: {
+				"customer:create", "customer:read", "customer:update", "customer:delete",
+			},
+		})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&rolePermissionV1{})
+	},
+}
+
+// grantPermissions insert role_permission rows, skipping existing grants
+func grantPermissions(tx *gorm.DB, grants map[uint32][]string) error {
+	var rows []rolePermissionV1
+	for roleId, permissions := range grants {
+		for _, permission := range permissions {
+			rows = append(rows, rolePermissionV1{RoleId: roleId, Permission: permission})
+		}
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
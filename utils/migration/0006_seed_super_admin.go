@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// superadmin / password123, same account the phpMyAdmin dump shipped with,
+// change the password after the first login
+const superAdminPasswordHash = "$2a$10$BY4qswohtm.mbftHF7W1nOqIkmjc4ZCxAwujz/98lbscgYVUXFWeq"
+
+var seedSuperAdmin = Migration{
+	Version: 6,
+	Name:    "seed_super_admin",
+	Up: func(tx *gorm.DB) error {
+		username := "superadmin"
+		password := superAdminPasswordHash
+		roleId := uint32(1)
+		verified := int8(1)
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&actorV1{
+			Username: &username,
+			Password: &password,
+			RoleId:   &roleId,
+			Verified: &verified,
+			Active:   &verified,
+		}).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Where("username = ?", "superadmin").Delete(&actorV1{}).Error
+	},
+}
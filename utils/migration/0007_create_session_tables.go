@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type sessionV1 struct {
+	ID        string     `gorm:"column:id;size:32;primaryKey"`
+	ActorId   uint32     `gorm:"column:actor_id;not null;index:fk_session_actor"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Actor     *actorV1   `gorm:"foreignKey:ActorId;constraint:OnDelete:CASCADE"`
+}
+
+func (sessionV1) TableName() string {
+	return "session"
+}
+
+type refreshTokenV1 struct {
+	ID        uint32     `gorm:"column:id;primaryKey;autoIncrement"`
+	SessionId string     `gorm:"column:session_id;size:32;not null;index:fk_refresh_token_session"`
+	TokenHash string     `gorm:"column:token_hash;size:64;not null;uniqueIndex:uq_refresh_token_hash"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Session   *sessionV1 `gorm:"foreignKey:SessionId;constraint:OnDelete:CASCADE"`
+}
+
+func (refreshTokenV1) TableName() string {
+	return "refresh_token"
+}
+
+var createSessionTables = Migration{
+	Version: 7,
+	Name:    "create_session_tables",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&sessionV1{}, &refreshTokenV1{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&refreshTokenV1{}, &sessionV1{})
+	},
+}
@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type customerV2 struct {
+	ID              uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	FirstName       *string   `gorm:"column:first_name;size:255"`
+	LastName        *string   `gorm:"column:last_name;size:255"`
+	Email           *string   `gorm:"column:email;size:255"`
+	EmailNormalized *string   `gorm:"column:email_normalized;size:255;uniqueIndex:uq_customer_email_normalized"`
+	Avatar          *string   `gorm:"column:avatar;size:255;default:''"`
+	CreatedAt       time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (customerV2) TableName() string {
+	return "customer"
+}
+
+var addCustomerEmailNormalized = Migration{
+	Version: 8,
+	Name:    "add_customer_email_normalized",
+	Up: func(tx *gorm.DB) error {
+		// not AddColumn, once gorm has parsed the unique index of customerV2
+		// it declares the column UNIQUE, which sqlite cannot add, so running
+		// Up again after Down in one process would fail
+		err := tx.Exec("ALTER TABLE ? ADD COLUMN ? varchar(255)",
+			clause.Table{Name: "customer"}, clause.Column{Name: "email_normalized"}).Error
+		if err != nil {
+			return err
+		}
+		err = backfillEmailNormalized(tx)
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().CreateIndex(&customerV2{}, "uq_customer_email_normalized")
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Migrator().DropIndex(&customerV2{}, "uq_customer_email_normalized")
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropColumn(&customerV2{}, "EmailNormalized")
+	},
+}
+
+// backfillEmailNormalized fill email_normalized for the oldest customer of
+// every email, later duplicates stay NULL so the unique index can be built
+// and they show up in the duplicates report instead
+func backfillEmailNormalized(tx *gorm.DB) error {
+	seen := map[string]bool{}
+	var batch []customerV2
+	return tx.Select("id", "email").Order("id").FindInBatches(&batch, 500, func(batchTx *gorm.DB, _ int) error {
+		for _, customer := range batch {
+			if customer.Email == nil {
+				continue
+			}
+			normalized := strings.ToLower(strings.TrimSpace(*customer.Email))
+			if normalized == "" || seen[normalized] {
+				continue
+			}
+			seen[normalized] = true
+			err := tx.Session(&gorm.Session{NewDB: true}).Model(&customerV2{}).
+				Where("id = ?", customer.ID).
+				Update("email_normalized", normalized).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
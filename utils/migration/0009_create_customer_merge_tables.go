@@ -0,0 +1,57 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type customerRedirectV1 struct {
+	OldId      uint32      `gorm:"column:old_id;primaryKey;autoIncrement:false"`
+	SurvivorId uint32      `gorm:"column:survivor_id;not null;index:fk_customer_redirect_survivor"`
+	CreatedAt  time.Time   `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Survivor   *customerV2 `gorm:"foreignKey:SurvivorId;constraint:OnDelete:CASCADE"`
+}
+
+func (customerRedirectV1) TableName() string {
+	return "customer_redirect"
+}
+
+// auditLogV1 has no foreign keys, entries outlive the actors and records
+// they mention
+type auditLogV1 struct {
+	ID         uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	ActorId    *uint32   `gorm:"column:actor_id;index:idx_audit_log_actor"`
+	Action     string    `gorm:"column:action;size:32;not null"`
+	EntityType string    `gorm:"column:entity_type;size:32;not null;index:idx_audit_log_entity"`
+	EntityId   uint32    `gorm:"column:entity_id;not null;index:idx_audit_log_entity"`
+	Before     *string   `gorm:"column:before;type:text"`
+	After      *string   `gorm:"column:after;type:text"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (auditLogV1) TableName() string {
+	return "audit_log"
+}
+
+var createCustomerMergeTables = Migration{
+	Version: 9,
+	Name:    "create_customer_merge_tables",
+	Up: func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&customerRedirectV1{}, &auditLogV1{})
+		if err != nil {
+			return err
+		}
+		return grantPermissions(tx, map[uint32][]string{
+			1: {"customer:merge"},
+			2: {"customer:merge"},
+		})
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Where("permission = ?", "customer:merge").Delete(&rolePermissionV1{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&auditLogV1{}, &customerRedirectV1{})
+	},
+}
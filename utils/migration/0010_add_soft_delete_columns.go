@@ -0,0 +1,81 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type customerV3 struct {
+	ID              uint32     `gorm:"column:id;primaryKey;autoIncrement"`
+	FirstName       *string    `gorm:"column:first_name;size:255"`
+	LastName        *string    `gorm:"column:last_name;size:255"`
+	Email           *string    `gorm:"column:email;size:255"`
+	EmailNormalized *string    `gorm:"column:email_normalized;size:255;uniqueIndex:uq_customer_email_normalized"`
+	Avatar          *string    `gorm:"column:avatar;size:255;default:''"`
+	CreatedAt       time.Time  `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt       *time.Time `gorm:"column:deleted_at;index:idx_customer_deleted_at"`
+	DeletedBy       *uint32    `gorm:"column:deleted_by"`
+}
+
+func (customerV3) TableName() string {
+	return "customer"
+}
+
+type actorV2 struct {
+	ID        uint32     `gorm:"column:id;primaryKey;autoIncrement"`
+	Username  *string    `gorm:"column:username;size:16;uniqueIndex:username"`
+	Password  *string    `gorm:"column:password;size:255"`
+	RoleId    *uint32    `gorm:"column:role_id;index:fk_actors_role"`
+	Verified  *int8      `gorm:"column:verified"`
+	Active    *int8      `gorm:"column:active"`
+	CreatedAt time.Time  `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `gorm:"column:deleted_at;index:idx_actors_deleted_at"`
+	DeletedBy *uint32    `gorm:"column:deleted_by"`
+	Role      *roleV1    `gorm:"foreignKey:RoleId;references:IdRole"`
+}
+
+func (actorV2) TableName() string {
+	return "actors"
+}
+
+var addSoftDeleteColumns = Migration{
+	Version: 10,
+	Name:    "add_soft_delete_columns",
+	Up: func(tx *gorm.DB) error {
+		for _, model := range []any{&customerV3{}, &actorV2{}} {
+			for _, field := range []string{"DeletedAt", "DeletedBy"} {
+				err := tx.Migrator().AddColumn(model, field)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err := tx.Migrator().CreateIndex(&customerV3{}, "idx_customer_deleted_at")
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().CreateIndex(&actorV2{}, "idx_actors_deleted_at")
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Migrator().DropIndex(&customerV3{}, "idx_customer_deleted_at")
+		if err != nil {
+			return err
+		}
+		err = tx.Migrator().DropIndex(&actorV2{}, "idx_actors_deleted_at")
+		if err != nil {
+			return err
+		}
+		for _, model := range []any{&customerV3{}, &actorV2{}} {
+			for _, field := range []string{"DeletedAt", "DeletedBy"} {
+				err = dropColumn(tx, model, field)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type customerV4 struct {
+	ID              uint32     `gorm:"column:id;primaryKey;autoIncrement"`
+	FirstName       *string    `gorm:"column:first_name;size:255"`
+	LastName        *string    `gorm:"column:last_name;size:255"`
+	Email           *string    `gorm:"column:email;size:255"`
+	EmailNormalized *string    `gorm:"column:email_normalized;size:255;uniqueIndex:uq_customer_email_normalized"`
+	Avatar          *string    `gorm:"column:avatar;size:255;default:''"`
+	AvatarKey       *string    `gorm:"column:avatar_key;size:255"`
+	CreatedAt       time.Time  `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt       *time.Time `gorm:"column:deleted_at;index:idx_customer_deleted_at"`
+	DeletedBy       *uint32    `gorm:"column:deleted_by"`
+}
+
+func (customerV4) TableName() string {
+	return "customer"
+}
+
+var addCustomerAvatarKey = Migration{
+	Version: 11,
+	Name:    "add_customer_avatar_key",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AddColumn(&customerV4{}, "AvatarKey")
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropColumn(tx, &customerV4{}, "AvatarKey")
+	},
+}
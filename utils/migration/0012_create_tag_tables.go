@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type tagV1 struct {
+	ID        uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string    `gorm:"column:name;size:64;not null;uniqueIndex:uq_tag_name"`
+	Color     string    `gorm:"column:color;size:7;not null;default:''"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (tagV1) TableName() string {
+	return "tag"
+}
+
+type customerTagV1 struct {
+	CustomerId uint32      `gorm:"column:customer_id;primaryKey;autoIncrement:false"`
+	TagId      uint32      `gorm:"column:tag_id;primaryKey;autoIncrement:false;index:fk_customer_tag_tag"`
+	CreatedAt  time.Time   `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Customer   *customerV4 `gorm:"foreignKey:CustomerId;constraint:OnDelete:CASCADE"`
+	Tag        *tagV1      `gorm:"foreignKey:TagId;constraint:OnDelete:CASCADE"`
+}
+
+func (customerTagV1) TableName() string {
+	return "customer_tag"
+}
+
+// segmentV1 filter is a JSON document with the criteria of the customer list
+type segmentV1 struct {
+	ID        uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string    `gorm:"column:name;size:100;not null;uniqueIndex:uq_segment_name"`
+	Filter    string    `gorm:"column:filter;type:text;not null"`
+	CreatedBy *uint32   `gorm:"column:created_by"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (segmentV1) TableName() string {
+	return "segment"
+}
+
+var createTagTables = Migration{
+	Version: 12,
+	Name:    "create_tag_tables",
+	Up: func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&tagV1{}, &customerTagV1{}, &segmentV1{})
+		if err != nil {
+			return err
+		}
+		return grantPermissions(tx, map[uint32][]string{
+			1: {"tag:manage", "segment:manage"},
+			2: {"tag:manage", "segment:manage"},
+		})
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Where("permission IN ?", []string{"tag:manage", "segment:manage"}).Delete(&rolePermissionV1{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&segmentV1{}, &customerTagV1{}, &tagV1{})
+	},
+}
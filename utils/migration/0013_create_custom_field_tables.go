@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// customFieldV1 rules is a JSON document with the checks of the field type
+type customFieldV1 struct {
+	ID        uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string    `gorm:"column:name;size:64;not null;uniqueIndex:uq_custom_field_name"`
+	Label     string    `gorm:"column:label;size:100;not null"`
+	Type      string    `gorm:"column:type;size:16;not null"`
+	Required  bool      `gorm:"column:required;not null;default:false"`
+	Rules     string    `gorm:"column:rules;type:text;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (customFieldV1) TableName() string {
+	return "custom_field"
+}
+
+// customFieldValueV1 number repeats the value of number fields so filters
+// compare numbers rather than text
+type customFieldValueV1 struct {
+	CustomerId uint32         `gorm:"column:customer_id;primaryKey;autoIncrement:false"`
+	FieldId    uint32         `gorm:"column:field_id;primaryKey;autoIncrement:false;index:idx_custom_field_value_number,priority:1"`
+	Value      string         `gorm:"column:value;type:text;not null"`
+	Number     *float64       `gorm:"column:number;index:idx_custom_field_value_number,priority:2"`
+	UpdatedAt  time.Time      `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Customer   *customerV4    `gorm:"foreignKey:CustomerId;constraint:OnDelete:CASCADE"`
+	Field      *customFieldV1 `gorm:"foreignKey:FieldId;constraint:OnDelete:CASCADE"`
+}
+
+func (customFieldValueV1) TableName() string {
+	return "custom_field_value"
+}
+
+var createCustomFieldTables = Migration{
+	Version: 13,
+	Name:    "create_custom_field_tables",
+	Up: func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&customFieldV1{}, &customFieldValueV1{})
+		if err != nil {
+			return err
+		}
+		return grantPermissions(tx, map[uint32][]string{
+			1: {"field:manage"},
+			2: {"field:manage"},
+		})
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Where("permission = ?", "field:manage").Delete(&rolePermissionV1{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&customFieldValueV1{}, &customFieldV1{})
+	},
+}
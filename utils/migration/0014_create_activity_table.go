@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// activityV1 a note, call, meeting or email with a customer, occurred_at is
+// when it happened and drives the timeline order
+type activityV1 struct {
+	ID         uint32      `gorm:"column:id;primaryKey;autoIncrement"`
+	CustomerId uint32      `gorm:"column:customer_id;not null;index:idx_activity_customer,priority:1"`
+	ActorId    *uint32     `gorm:"column:actor_id;index:fk_activity_actor"`
+	Type       string      `gorm:"column:type;size:16;not null"`
+	Subject    string      `gorm:"column:subject;size:255;not null;default:''"`
+	Body       string      `gorm:"column:body;type:text;not null"`
+	OccurredAt time.Time   `gorm:"column:occurred_at;type:timestamp;not null;index:idx_activity_customer,priority:2"`
+	CreatedAt  time.Time   `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time   `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Customer   *customerV4 `gorm:"foreignKey:CustomerId;constraint:OnDelete:CASCADE"`
+	Actor      *actorV2    `gorm:"foreignKey:ActorId;constraint:OnDelete:SET NULL"`
+}
+
+func (activityV1) TableName() string {
+	return "activity"
+}
+
+var createActivityTable = Migration{
+	Version: 14,
+	Name:    "create_activity_table",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&activityV1{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&activityV1{})
+	},
+}
@@ -0,0 +1,107 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type accountV1 struct {
+	ID        uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	Name      string    `gorm:"column:name;size:255;not null;index:idx_account_name"`
+	Domain    string    `gorm:"column:domain;size:255;not null;default:''"`
+	Industry  string    `gorm:"column:industry;size:100;not null;default:''"`
+	CreatedAt time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (accountV1) TableName() string {
+	return "account"
+}
+
+// customerV5 account_id links a contact to the company it works for
+type customerV5 struct {
+	ID              uint32     `gorm:"column:id;primaryKey;autoIncrement"`
+	FirstName       *string    `gorm:"column:first_name;size:255"`
+	LastName        *string    `gorm:"column:last_name;size:255"`
+	Email           *string    `gorm:"column:email;size:255"`
+	EmailNormalized *string    `gorm:"column:email_normalized;size:255;uniqueIndex:uq_customer_email_normalized"`
+	Avatar          *string    `gorm:"column:avatar;size:255;default:''"`
+	AvatarKey       *string    `gorm:"column:avatar_key;size:255"`
+	AccountId       *uint32    `gorm:"column:account_id;index:fk_customer_account"`
+	CreatedAt       time.Time  `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	DeletedAt       *time.Time `gorm:"column:deleted_at;index:idx_customer_deleted_at"`
+	DeletedBy       *uint32    `gorm:"column:deleted_by"`
+	Account         *accountV1 `gorm:"foreignKey:AccountId;constraint:OnDelete:SET NULL"`
+}
+
+func (customerV5) TableName() string {
+	return "customer"
+}
+
+var createAccountTable = Migration{
+	Version: 15,
+	Name:    "create_account_table",
+	Up: func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&accountV1{})
+		if err != nil {
+			return err
+		}
+		// sqlite cannot add a foreign key to an existing table, gorm would
+		// rebuild it and drop its indexes, the column declares it instead
+		if tx.Dialector.Name() == "sqlite" {
+			err = tx.Exec("ALTER TABLE customer ADD COLUMN account_id integer REFERENCES account(id) ON DELETE SET NULL").Error
+		} else {
+			err = tx.Migrator().AddColumn(&customerV5{}, "AccountId")
+			if err == nil {
+				err = tx.Migrator().CreateConstraint(&customerV5{}, "Account")
+			}
+		}
+		if err != nil {
+			return err
+		}
+		err = tx.Migrator().CreateIndex(&customerV5{}, "fk_customer_account")
+		if err != nil {
+			return err
+		}
+		return grantPermissions(tx, map[uint32][]string{
+			1: {"account:create", "account:read", "account:update", "account:delete"},
+			2: {"account:create", "account:read", "account:update", "account:delete"},
+		})
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Where("permission LIKE ?", "account:%").Delete(&rolePermissionV1{}).Error
+		if err != nil {
+			return err
+		}
+		// on sqlite gorm rebuilds customer to drop the constraint, which
+		// loses its indexes, they are put back once the column is gone
+		if tx.Migrator().HasConstraint(&customerV5{}, "Account") {
+			err = tx.Migrator().DropConstraint(&customerV5{}, "Account")
+			if err != nil {
+				return err
+			}
+		}
+		if tx.Migrator().HasIndex(&customerV5{}, "fk_customer_account") {
+			err = tx.Migrator().DropIndex(&customerV5{}, "fk_customer_account")
+			if err != nil {
+				return err
+			}
+		}
+		err = dropColumn(tx, &customerV5{}, "AccountId")
+		if err != nil {
+			return err
+		}
+		for _, index := range []string{"uq_customer_email_normalized", "idx_customer_deleted_at"} {
+			if tx.Migrator().HasIndex(&customerV5{}, index) {
+				continue
+			}
+			err = tx.Migrator().CreateIndex(&customerV5{}, index)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropTable(&accountV1{})
+	},
+}
@@ -0,0 +1,68 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dealV1 revenue opportunity with a customer, stage is the name of one of the
+// configured pipeline stages
+type dealV1 struct {
+	ID                uint32      `gorm:"column:id;primaryKey;autoIncrement"`
+	Title             string      `gorm:"column:title;size:255;not null"`
+	CustomerId        uint32      `gorm:"column:customer_id;not null;index:fk_deal_customer"`
+	OwnerId           *uint32     `gorm:"column:owner_id;index:idx_deal_owner"`
+	Stage             string      `gorm:"column:stage;size:64;not null;index:idx_deal_stage"`
+	Value             float64     `gorm:"column:value;type:decimal(15,2);not null;default:0"`
+	Currency          string      `gorm:"column:currency;size:3;not null"`
+	ExpectedCloseDate *time.Time  `gorm:"column:expected_close_date;type:date"`
+	StageChangedAt    time.Time   `gorm:"column:stage_changed_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	CreatedAt         time.Time   `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt         time.Time   `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Customer          *customerV5 `gorm:"foreignKey:CustomerId;constraint:OnDelete:CASCADE"`
+	Owner             *actorV2    `gorm:"foreignKey:OwnerId;constraint:OnDelete:SET NULL"`
+}
+
+func (dealV1) TableName() string {
+	return "deal"
+}
+
+// dealStageChangeV1 one move of a deal between stages, from_stage is NULL
+// for the stage the deal was created in
+type dealStageChangeV1 struct {
+	ID        uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	DealId    uint32    `gorm:"column:deal_id;not null;index:idx_deal_stage_change_deal,priority:1"`
+	FromStage *string   `gorm:"column:from_stage;size:64"`
+	ToStage   string    `gorm:"column:to_stage;size:64;not null"`
+	ActorId   *uint32   `gorm:"column:actor_id;index:fk_deal_stage_change_actor"`
+	ChangedAt time.Time `gorm:"column:changed_at;type:timestamp;not null;index:idx_deal_stage_change_deal,priority:2"`
+	Deal      *dealV1   `gorm:"foreignKey:DealId;constraint:OnDelete:CASCADE"`
+	Actor     *actorV2  `gorm:"foreignKey:ActorId;constraint:OnDelete:SET NULL"`
+}
+
+func (dealStageChangeV1) TableName() string {
+	return "deal_stage_change"
+}
+
+var createDealTables = Migration{
+	Version: 16,
+	Name:    "create_deal_tables",
+	Up: func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&dealV1{}, &dealStageChangeV1{})
+		if err != nil {
+			return err
+		}
+		return grantPermissions(tx, map[uint32][]string{
+			1: {"deal:create", "deal:read", "deal:update", "deal:delete"},
+			2: {"deal:create", "deal:read", "deal:update", "deal:delete"},
+		})
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Where("permission LIKE ?", "deal:%").Delete(&rolePermissionV1{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&dealStageChangeV1{}, &dealV1{})
+	},
+}
@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// taskV1 to do assigned to an actor, optionally about a customer or one of
+// its deals. overdue_at and reminded_at are set once by the reminder job
+type taskV1 struct {
+	ID          uint32      `gorm:"column:id;primaryKey;autoIncrement"`
+	Title       string      `gorm:"column:title;size:255;not null"`
+	Description string      `gorm:"column:description;type:text"`
+	DueAt       time.Time   `gorm:"column:due_at;type:timestamp;not null;index:idx_task_assignee_due,priority:2;index:idx_task_status_due,priority:2"`
+	RemindAt    *time.Time  `gorm:"column:remind_at;type:timestamp"`
+	Priority    string      `gorm:"column:priority;size:16;not null;default:'normal'"`
+	Status      string      `gorm:"column:status;size:16;not null;default:'open';index:idx_task_status_due,priority:1"`
+	AssigneeId  *uint32     `gorm:"column:assignee_id;index:idx_task_assignee_due,priority:1"`
+	CustomerId  *uint32     `gorm:"column:customer_id;index:fk_task_customer"`
+	DealId      *uint32     `gorm:"column:deal_id;index:fk_task_deal"`
+	CreatedBy   *uint32     `gorm:"column:created_by;index:fk_task_created_by"`
+	CompletedAt *time.Time  `gorm:"column:completed_at;type:timestamp"`
+	RemindedAt  *time.Time  `gorm:"column:reminded_at;type:timestamp"`
+	OverdueAt   *time.Time  `gorm:"column:overdue_at;type:timestamp"`
+	CreatedAt   time.Time   `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time   `gorm:"column:updated_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	Assignee    *actorV2    `gorm:"foreignKey:AssigneeId;constraint:OnDelete:SET NULL"`
+	Customer    *customerV5 `gorm:"foreignKey:CustomerId;constraint:OnDelete:CASCADE"`
+	Deal        *dealV1     `gorm:"foreignKey:DealId;constraint:OnDelete:SET NULL"`
+	Creator     *actorV2    `gorm:"foreignKey:CreatedBy;constraint:OnDelete:SET NULL"`
+}
+
+func (taskV1) TableName() string {
+	return "task"
+}
+
+var createTaskTable = Migration{
+	Version: 17,
+	Name:    "create_task_table",
+	Up: func(tx *gorm.DB) error {
+		// CreateTable rather than AutoMigrate, which would also migrate the
+		// referenced deal table whose decimal(15,2) column the sqlite driver
+		// cannot parse back
+		err := tx.Migrator().CreateTable(&taskV1{})
+		if err != nil {
+			return err
+		}
+		return grantPermissions(tx, map[uint32][]string{
+			1: {"task:create", "task:read", "task:update", "task:delete"},
+			2: {"task:create", "task:read", "task:update", "task:delete"},
+		})
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Where("permission LIKE ?", "task:%").Delete(&rolePermissionV1{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(&taskV1{})
+	},
+}
@@ -0,0 +1,107 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// auditLogV2 auditLogV1 with the request that made the change
+type auditLogV2 struct {
+	ID         uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	ActorId    *uint32   `gorm:"column:actor_id;index:idx_audit_log_actor"`
+	Action     string    `gorm:"column:action;size:32;not null"`
+	EntityType string    `gorm:"column:entity_type;size:32;not null;index:idx_audit_log_entity"`
+	EntityId   uint32    `gorm:"column:entity_id;not null;index:idx_audit_log_entity"`
+	Before     *string   `gorm:"column:before;type:text"`
+	After      *string   `gorm:"column:after;type:text"`
+	RequestId  string    `gorm:"column:request_id;size:64;not null;default:'';index:idx_audit_log_request"`
+	ClientIp   string    `gorm:"column:client_ip;size:45;not null;default:''"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP;index:idx_audit_log_created"`
+}
+
+func (auditLogV2) TableName() string {
+	return "audit_log"
+}
+
+// appendOnlyTriggers statements making the database refuse to change or
+// remove an audit entry, per dialect
+var appendOnlyTriggers = map[string][]string{
+	"sqlite": {
+		"CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log BEGIN SELECT RAISE(ABORT, 'audit_log is append-only'); END",
+		"CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log BEGIN SELECT RAISE(ABORT, 'audit_log is append-only'); END",
+	},
+	"mysql": {
+		"CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only'",
+		"CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only'",
+	},
+	"postgres": {
+		"CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$ BEGIN RAISE EXCEPTION 'audit_log is append-only'; END $$ LANGUAGE plpgsql",
+		"CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log FOR EACH ROW EXECUTE FUNCTION audit_log_append_only()",
+		"CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log FOR EACH ROW EXECUTE FUNCTION audit_log_append_only()",
+	},
+}
+
+var extendAuditLog = Migration{
+	Version: 18,
+	Name:    "extend_audit_log",
+	Up: func(tx *gorm.DB) error {
+		for _, column := range []string{"RequestId", "ClientIp"} {
+			err := tx.Migrator().AddColumn(&auditLogV2{}, column)
+			if err != nil {
+				return err
+			}
+		}
+		for _, index := range []string{"idx_audit_log_request", "idx_audit_log_created"} {
+			err := tx.Migrator().CreateIndex(&auditLogV2{}, index)
+			if err != nil {
+				return err
+			}
+		}
+		for _, statement := range appendOnlyTriggers[tx.Dialector.Name()] {
+			err := tx.Exec(statement).Error
+			if err != nil {
+				return err
+			}
+		}
+		return grantPermissions(tx, map[uint32][]string{
+			1: {"audit:read"},
+		})
+	},
+	Down: func(tx *gorm.DB) error {
+		err := tx.Where("permission = ?", "audit:read").Delete(&rolePermissionV1{}).Error
+		if err != nil {
+			return err
+		}
+		drop := []string{
+			"DROP TRIGGER IF EXISTS audit_log_no_update",
+			"DROP TRIGGER IF EXISTS audit_log_no_delete",
+		}
+		if tx.Dialector.Name() == "postgres" {
+			drop = []string{
+				"DROP TRIGGER IF EXISTS audit_log_no_update ON audit_log",
+				"DROP TRIGGER IF EXISTS audit_log_no_delete ON audit_log",
+				"DROP FUNCTION IF EXISTS audit_log_append_only()",
+			}
+		}
+		for _, statement := range drop {
+			err = tx.Exec(statement).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, index := range []string{"idx_audit_log_request", "idx_audit_log_created"} {
+			err = tx.Migrator().DropIndex(&auditLogV2{}, index)
+			if err != nil {
+				return err
+			}
+		}
+		for _, column := range []string{"RequestId", "ClientIp"} {
+			err = dropColumn(tx, &auditLogV2{}, column)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// customerVersionV1 state of a customer from created_at until the next
+// version, version 1 is the state before its first recorded update
+type customerVersionV1 struct {
+	ID           uint32      `gorm:"column:id;primaryKey;autoIncrement"`
+	CustomerId   uint32      `gorm:"column:customer_id;not null;uniqueIndex:uq_customer_version,priority:1"`
+	Version      uint32      `gorm:"column:version;not null;uniqueIndex:uq_customer_version,priority:2"`
+	FirstName    string      `gorm:"column:first_name;size:255;not null;default:''"`
+	LastName     string      `gorm:"column:last_name;size:255;not null;default:''"`
+	Email        string      `gorm:"column:email;size:255;not null;default:''"`
+	Avatar       string      `gorm:"column:avatar;size:255;not null;default:''"`
+	AccountId    *uint32     `gorm:"column:account_id"`
+	RevertedFrom *uint32     `gorm:"column:reverted_from"`
+	CreatedAt    time.Time   `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP;index:idx_customer_version_created"`
+	Customer     *customerV5 `gorm:"foreignKey:CustomerId;constraint:OnDelete:CASCADE"`
+}
+
+func (customerVersionV1) TableName() string {
+	return "customer_version"
+}
+
+var createCustomerVersionTable = Migration{
+	Version: 19,
+	Name:    "create_customer_version_table",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&customerVersionV1{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&customerVersionV1{})
+	},
+}
@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// duplicateScanV1 one run of the duplicate detection, only the pairs of the
+// latest run are kept
+type duplicateScanV1 struct {
+	ID              uint32    `gorm:"column:id;primaryKey;autoIncrement"`
+	Customers       int       `gorm:"column:customers;not null;default:0"`
+	Pairs           int       `gorm:"column:pairs;not null;default:0"`
+	TruncatedBlocks int       `gorm:"column:truncated_blocks;not null;default:0"`
+	CreatedAt       time.Time `gorm:"column:created_at;type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (duplicateScanV1) TableName() string {
+	return "customer_duplicate_scan"
+}
+
+// customerDuplicateV1 two customers a scan found likely to be the same
+// person, customer_id is the lower of both ids
+type customerDuplicateV1 struct {
+	ID         uint32           `gorm:"column:id;primaryKey;autoIncrement"`
+	ScanId     uint32           `gorm:"column:scan_id;not null;index:idx_customer_duplicate_scan_score,priority:1"`
+	CustomerId uint32           `gorm:"column:customer_id;not null;index:fk_customer_duplicate_customer"`
+	OtherId    uint32           `gorm:"column:other_id;not null;index:fk_customer_duplicate_other"`
+	Score      float64          `gorm:"column:score;not null;index:idx_customer_duplicate_scan_score,priority:2"`
+	NameScore  float64          `gorm:"column:name_score;not null"`
+	EmailScore float64          `gorm:"column:email_score;not null"`
+	Reasons    string           `gorm:"column:reasons;size:255;not null;default:''"`
+	Scan       *duplicateScanV1 `gorm:"foreignKey:ScanId;constraint:OnDelete:CASCADE"`
+	Customer   *customerV5      `gorm:"foreignKey:CustomerId;constraint:OnDelete:CASCADE"`
+	Other      *customerV5      `gorm:"foreignKey:OtherId;constraint:OnDelete:CASCADE"`
+}
+
+func (customerDuplicateV1) TableName() string {
+	return "customer_duplicate"
+}
+
+var createCustomerDuplicateTables = Migration{
+	Version: 20,
+	Name:    "create_customer_duplicate_tables",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&duplicateScanV1{}, &customerDuplicateV1{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&customerDuplicateV1{}, &duplicateScanV1{})
+	},
+}
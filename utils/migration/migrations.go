@@ -0,0 +1,27 @@
+package migration
+
+// All migrations of the CRM schema, append new ones with the next version
+func All() []Migration {
+	return []Migration{
+		createRoleTable,
+		createActorsTable,
+		createCustomerTable,
+		createRegisterApprovalTable,
+		createRolePermissionTable,
+		seedSuperAdmin,
+		createSessionTables,
+		addCustomerEmailNormalized,
+		createCustomerMergeTables,
+		addSoftDeleteColumns,
+		addCustomerAvatarKey,
+		createTagTables,
+		createCustomFieldTables,
+		createActivityTable,
+		createAccountTable,
+		createDealTables,
+		createTaskTable,
+		extendAuditLog,
+		createCustomerVersionTable,
+		createCustomerDuplicateTables,
+	}
+}
@@ -0,0 +1,16 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Log writes notifications to the standard logger, for development and
+// deployments without anything to deliver them to
+type Log struct{}
+
+func (Log) Notify(ctx context.Context, n Notification) error {
+	log.Printf("%s for %q: %s %d %q due %s", n.Kind, n.Recipient, n.Entity_type, n.Entity_id, n.Subject, n.Due_at.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alkamalp/crm-golang/utils/config"
+)
+
+// Kinds of a notification
+const (
+	KindReminder = "reminder"
+	KindOverdue  = "overdue"
+)
+
+// Notifier delivers notifications to actors
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Notification what an actor is told about, Recipient is the username of the
+// actor and empty when nobody is assigned
+type Notification struct {
+	Kind        string    `json:"kind"`
+	Recipient   string    `json:"recipient"`
+	Subject     string    `json:"subject"`
+	Entity_type string    `json:"entity_type"`
+	Entity_id   uint      `json:"entity_id"`
+	Due_at      time.Time `json:"due_at"`
+}
+
+// New notifier of the configured driver
+func New(cfg config.Reminder) (Notifier, error) {
+	switch cfg.Notifier {
+	case "log":
+		return Log{}, nil
+	case "webhook":
+		return NewWebhook(cfg.WebhookURL)
+	}
+	return nil, fmt.Errorf("unknown notifier %q", cfg.Notifier)
+}
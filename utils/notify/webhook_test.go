@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(server.URL)
+	require.NoError(t, err)
+	sent := Notification{
+		Kind:        KindReminder,
+		Recipient:   "alice",
+		Subject:     "Call back",
+		Entity_type: "task",
+		Entity_id:   3,
+		Due_at:      time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, webhook.Notify(context.Background(), sent))
+	assert.Equal(t, sent, received)
+}
+
+func TestWebhook_NotifyFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(server.URL)
+	require.NoError(t, err)
+	err = webhook.Notify(context.Background(), Notification{Kind: KindOverdue})
+	assert.ErrorContains(t, err, "503 Service Unavailable try later")
+}
+
+func TestNewWebhook_InvalidURL(t *testing.T) {
+	_, err := NewWebhook("ftp://example.com/hook")
+	assert.Error(t, err)
+}
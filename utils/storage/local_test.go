@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocal(root)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "avatars/1/a.png", strings.NewReader("png"), 3, "image/png"))
+
+	body, object, err := s.Get(ctx, "avatars/1/a.png")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "png", string(content))
+	assert.Equal(t, "image/png", object.ContentType)
+	assert.Equal(t, int64(3), object.Size)
+	assert.NotEmpty(t, object.ETag)
+
+	require.NoError(t, s.Delete(ctx, "avatars/1/a.png"))
+	_, _, err = s.Get(ctx, "avatars/1/a.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, s.Delete(ctx, "avatars/1/a.png"))
+}
+
+func TestLocal_KeyStaysInRoot(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocal(filepath.Join(root, "blobs"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(context.Background(), "../../escape.txt", strings.NewReader("x"), 1, "text/plain"))
+
+	_, err = os.Stat(filepath.Join(root, "blobs", "escape.txt"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(root, "escape.txt"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Error(t, s.Put(context.Background(), "/", strings.NewReader("x"), 1, "text/plain"))
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/alkamalp/crm-golang/utils/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Storage blob store for uploaded files, keys are slash separated paths such
+// as avatars/12/3f9a.jpg
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get ErrNotFound when nothing is stored under key, the caller closes
+	// the body
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	// Delete a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Object what is known about a stored blob
+type Object struct {
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+	ETag        string
+}
+
+// New storage of the configured driver
+func New(cfg config.Storage) (Storage, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocal(cfg.Path)
+	case "s3":
+		return NewS3(cfg.S3)
+	}
+	return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+}
@@ -0,0 +1,37 @@
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSquare(t *testing.T) {
+	// 40x20, left half red and right half blue, offset bounds like a sub image
+	img := image.NewRGBA(image.Rect(10, 10, 50, 30))
+	for y := 10; y < 30; y++ {
+		for x := 10; x < 50; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 30 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	thumb := Square(img, 4)
+
+	assert.Equal(t, image.Rect(0, 0, 4, 4), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, thumb.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, thumb.RGBAAt(3, 3))
+}
+
+func TestSquare_SmallImageOnlyCropped(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 6, 3))
+
+	thumb := Square(img, 64)
+
+	assert.Equal(t, image.Rect(0, 0, 3, 3), thumb.Bounds())
+}
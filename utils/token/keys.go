@@ -0,0 +1,234 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/golang-jwt/jwt"
+)
+
+// Key one signing key, sign is nil for keys that are only kept to verify
+// tokens issued before a rotation
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   any
+	verify any
+}
+
+// KeySet the current signing key and every key a token may still carry
+type KeySet struct {
+	current Key
+	keys    []Key
+}
+
+// LoadKeySet build the keys described by cfg. With a signing key file the
+// HMAC secret, when still configured, is only used to verify old tokens
+func LoadKeySet(cfg config.JWT) (KeySet, error) {
+	var set KeySet
+	if cfg.SigningKeyFile != "" {
+		key, err := loadKeyFile(cfg.SigningKeyFile)
+		if err != nil {
+			return set, err
+		}
+		if key.sign == nil {
+			return set, fmt.Errorf("jwt signing key %s: private key required", cfg.SigningKeyFile)
+		}
+		set.current = key
+		set.keys = append(set.keys, key)
+	}
+	if cfg.Secret != "" {
+		key := hmacKey(cfg.Secret)
+		if set.current.ID == "" {
+			set.current = key
+		} else {
+			key.sign = nil
+		}
+		set.keys = append(set.keys, key)
+	}
+	if set.current.ID == "" {
+		return set, errors.New("jwt: no signing key or secret configured")
+	}
+
+	for _, path := range cfg.PreviousKeyFiles {
+		key, err := loadKeyFile(path)
+		if err != nil {
+			return set, err
+		}
+		if _, found := set.key(key.ID); found {
+			continue
+		}
+		key.sign = nil
+		set.keys = append(set.keys, key)
+	}
+	return set, nil
+}
+
+func (set KeySet) key(id string) (Key, bool) {
+	for _, key := range set.keys {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// JWKS public half of every asymmetric key, current key first. HMAC
+// secrets are never published
+func (set KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range set.keys {
+		jwk, ok := publicJWK(key)
+		if ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+	return jwks
+}
+
+func hmacKey(secret string) Key {
+	sum := sha256.Sum256([]byte(secret))
+	return Key{
+		ID:     "hs-" + hex.EncodeToString(sum[:6]),
+		Method: jwt.SigningMethodHS256,
+		sign:   []byte(secret),
+		verify: []byte(secret),
+	}
+}
+
+// loadKeyFile accept PEM private keys (PKCS#1, SEC 1, PKCS#8) or public
+// keys (PKIX, certificate) of type RSA or P-256 EC
+func loadKeyFile(path string) (Key, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("jwt key: %w", err)
+	}
+
+	var key Key
+	if private, err := jwt.ParseRSAPrivateKeyFromPEM(raw); err == nil {
+		key, err = asymmetricKey(&private.PublicKey)
+		key.sign = private
+		return key, wrapKeyError(path, err)
+	}
+	if private, err := jwt.ParseECPrivateKeyFromPEM(raw); err == nil {
+		key, err = asymmetricKey(&private.PublicKey)
+		key.sign = private
+		return key, wrapKeyError(path, err)
+	}
+	if public, err := jwt.ParseRSAPublicKeyFromPEM(raw); err == nil {
+		key, err = asymmetricKey(public)
+		return key, wrapKeyError(path, err)
+	}
+	if public, err := jwt.ParseECPublicKeyFromPEM(raw); err == nil {
+		key, err = asymmetricKey(public)
+		return key, wrapKeyError(path, err)
+	}
+	return Key{}, fmt.Errorf("jwt key %s: not a PEM encoded RSA or EC key", path)
+}
+
+func wrapKeyError(path string, err error) error {
+	if err != nil {
+		return fmt.Errorf("jwt key %s: %w", path, err)
+	}
+	return nil
+}
+
+func asymmetricKey(public crypto.PublicKey) (Key, error) {
+	key := Key{verify: public}
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < 2048 {
+			return Key{}, errors.New("RSA keys must be at least 2048 bits")
+		}
+		key.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		if public.Curve != elliptic.P256() {
+			return Key{}, errors.New("EC keys must use the P-256 curve for ES256")
+		}
+		key.Method = jwt.SigningMethodES256
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T", public)
+	}
+	jwk, _ := publicJWK(key)
+	key.ID = thumbprint(jwk)
+	return key, nil
+}
+
+// JWK public key as published at /.well-known/jwks.json (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func publicJWK(key Key) (JWK, bool) {
+	jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+	switch public := key.verify.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeInt(public.N, 0)
+		jwk.E = encodeInt(big.NewInt(int64(public.E)), 0)
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = public.Curve.Params().Name
+		jwk.X = encodeInt(public.X, size)
+		jwk.Y = encodeInt(public.Y, size)
+	default:
+		return JWK{}, false
+	}
+	return jwk, true
+}
+
+// thumbprint RFC 7638 JWK thumbprint, used as kid so it is stable across
+// restarts and identical on every instance
+func thumbprint(jwk JWK) string {
+	var members any
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	}
+	raw, _ := json.Marshal(members)
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// encodeInt base64url big-endian bytes, left padded to size when given
+func encodeInt(n *big.Int, size int) string {
+	raw := n.Bytes()
+	if len(raw) < size {
+		raw = append(make([]byte, size-len(raw)), raw...)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
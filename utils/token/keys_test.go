@@ -0,0 +1,195 @@
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret-0123456789"
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err)
+	return path
+}
+
+// writeRSAKey private key file in PKCS#1 and public key file in PKIX form
+func writeRSAKey(t *testing.T, bits int) (string, string) {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	public, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	return writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private)),
+		writePEM(t, "rsa.pub.pem", "PUBLIC KEY", public)
+}
+
+// writeECKey private key file in PKCS#8 form
+func writeECKey(t *testing.T, curve elliptic.Curve) string {
+	t.Helper()
+	private, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	return writePEM(t, "ec.pem", "PRIVATE KEY", der)
+}
+
+func newIssuer(t *testing.T, cfg config.JWT) Issuer {
+	t.Helper()
+	cfg.AccessTTL = time.Minute
+	issuer, err := NewIssuer(cfg)
+	require.NoError(t, err)
+	return issuer
+}
+
+func sign(t *testing.T, issuer Issuer) (string, *jwt.Token) {
+	t.Helper()
+	signed, _, err := issuer.AccessToken(entity.Actor{Username: "admin1", Role_id: 2}, "session-1")
+	require.NoError(t, err)
+	parsed, _, err := new(jwt.Parser).ParseUnverified(signed, &Claims{})
+	require.NoError(t, err)
+	return signed, parsed
+}
+
+func TestIssuer_Asymmetric(t *testing.T) {
+	rsaKey, _ := writeRSAKey(t, 2048)
+	ecKey := writeECKey(t, elliptic.P256())
+
+	for alg, path := range map[string]string{"RS256": rsaKey, "ES256": ecKey} {
+		issuer := newIssuer(t, config.JWT{SigningKeyFile: path})
+
+		signed, parsed := sign(t, issuer)
+		assert.Equal(t, alg, parsed.Method.Alg())
+		assert.Equal(t, issuer.keys.current.ID, parsed.Header["kid"])
+
+		claims, err := issuer.Parse(signed)
+		assert.NoError(t, err)
+		assert.Equal(t, "admin1", claims.Name)
+		assert.Equal(t, uint(2), claims.Role)
+	}
+}
+
+func TestIssuer_Rotation(t *testing.T) {
+	oldKey, oldPublic := writeRSAKey(t, 2048)
+	newKey := writeECKey(t, elliptic.P256())
+	before := newIssuer(t, config.JWT{SigningKeyFile: oldKey})
+	oldToken, _ := sign(t, before)
+
+	// the retired key only needs its public half
+	after := newIssuer(t, config.JWT{SigningKeyFile: newKey, PreviousKeyFiles: []string{oldPublic}})
+	_, err := after.Parse(oldToken)
+	assert.NoError(t, err)
+	_, parsed := sign(t, after)
+	assert.Equal(t, "ES256", parsed.Method.Alg())
+
+	// once the previous key is dropped its tokens are refused
+	_, err = newIssuer(t, config.JWT{SigningKeyFile: newKey}).Parse(oldToken)
+	assert.Error(t, err)
+}
+
+func TestIssuer_SecretKeptForVerification(t *testing.T) {
+	rsaKey, _ := writeRSAKey(t, 2048)
+	hmacToken, _ := sign(t, newIssuer(t, config.JWT{Secret: testSecret}))
+
+	issuer := newIssuer(t, config.JWT{Secret: testSecret, SigningKeyFile: rsaKey})
+
+	_, err := issuer.Parse(hmacToken)
+	assert.NoError(t, err)
+	_, parsed := sign(t, issuer)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	assert.Len(t, issuer.JWKS().Keys, 1)
+}
+
+func TestIssuer_RejectsAlgorithmMismatch(t *testing.T) {
+	rsaKey, rsaPublic := writeRSAKey(t, 2048)
+	issuer := newIssuer(t, config.JWT{SigningKeyFile: rsaKey})
+	publicPEM, err := os.ReadFile(rsaPublic)
+	require.NoError(t, err)
+
+	// HS256 keyed with the published RSA public key must not verify
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		Role:      1,
+		SessionId: "session-1",
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		},
+	})
+	forged.Header["kid"] = issuer.keys.current.ID
+	signed, err := forged.SignedString(publicPEM)
+	require.NoError(t, err)
+
+	_, err = issuer.Parse(signed)
+	assert.Error(t, err)
+}
+
+func TestIssuer_UnknownKid(t *testing.T) {
+	issuer := newIssuer(t, config.JWT{Secret: testSecret})
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{SessionId: "session-1"})
+	unsigned.Header["kid"] = "unknown"
+	signed, err := unsigned.SignedString([]byte(testSecret))
+	require.NoError(t, err)
+
+	_, err = issuer.Parse(signed)
+	assert.ErrorContains(t, err, "unknown key id")
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	rsaKey, _ := writeRSAKey(t, 2048)
+	ecKey := writeECKey(t, elliptic.P256())
+
+	issuer := newIssuer(t, config.JWT{
+		Secret:           testSecret,
+		SigningKeyFile:   ecKey,
+		PreviousKeyFiles: []string{rsaKey},
+	})
+	jwks := issuer.JWKS()
+
+	require.Len(t, jwks.Keys, 2)
+	current := jwks.Keys[0]
+	assert.Equal(t, "EC", current.Kty)
+	assert.Equal(t, "P-256", current.Crv)
+	assert.Equal(t, "ES256", current.Alg)
+	assert.Len(t, current.X, 43)
+	assert.Len(t, current.Y, 43)
+	assert.Equal(t, issuer.keys.current.ID, current.Kid)
+	previous := jwks.Keys[1]
+	assert.Equal(t, "RSA", previous.Kty)
+	assert.Equal(t, "AQAB", previous.E)
+
+	assert.Empty(t, newIssuer(t, config.JWT{Secret: testSecret}).JWKS().Keys)
+}
+
+func TestLoadKeySet_Invalid(t *testing.T) {
+	_, publicOnly := writeRSAKey(t, 2048)
+	weak, _ := writeRSAKey(t, 1024)
+	p384 := writeECKey(t, elliptic.P384())
+	garbage := writePEM(t, "garbage.pem", "CERTIFICATE", []byte("nope"))
+
+	for name, cfg := range map[string]config.JWT{
+		"nothing":     {},
+		"public only": {SigningKeyFile: publicOnly},
+		"weak rsa":    {SigningKeyFile: weak},
+		"p-384":       {SigningKeyFile: p384},
+		"garbage":     {SigningKeyFile: garbage},
+		"missing":     {SigningKeyFile: filepath.Join(t.TempDir(), "missing.pem")},
+	} {
+		_, err := LoadKeySet(cfg)
+		assert.Error(t, err, name)
+	}
+}
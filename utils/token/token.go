@@ -0,0 +1,105 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/alkamalp/crm-golang/entity"
+	"github.com/alkamalp/crm-golang/utils/config"
+	"github.com/golang-jwt/jwt"
+)
+
+// Claims of a CRM access token, sub keeps the actor's Role_id as before
+type Claims struct {
+	Role      uint   `json:"sub"`
+	Name      string `json:"name"`
+	SessionId string `json:"sid"`
+	jwt.StandardClaims
+}
+
+// Issuer sign and verify access tokens
+type Issuer struct {
+	keys      KeySet
+	accessTTL time.Duration
+}
+
+// NewIssuer load the signing keys once at startup, the issuer is then shared
+// by every module that issues or checks tokens
+func NewIssuer(cfg config.JWT) (Issuer, error) {
+	keys, err := LoadKeySet(cfg)
+	if err != nil {
+		return Issuer{}, err
+	}
+	return Issuer{
+		keys:      keys,
+		accessTTL: cfg.AccessTTL,
+	}, nil
+}
+
+// AccessToken signed token for actor in session, valid for the access ttl
+func (i Issuer) AccessToken(actor entity.Actor, sessionId string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(i.accessTTL)
+	claims := Claims{
+		Role:      actor.Role_id,
+		Name:      actor.Username,
+		SessionId: sessionId,
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	key := i.keys.current
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.sign)
+	return signed, expiresAt, err
+}
+
+// Parse verify signature and expiry of an access token against the key
+// named by its kid, the alg header has to match that key
+func (i Issuer) Parse(signed string) (Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(signed, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := i.keys.key(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return key.verify, nil
+	})
+	return claims, err
+}
+
+// JWKS public keys other services verify tokens with
+func (i Issuer) JWKS() JWKS {
+	return i.keys.JWKS()
+}
+
+// NewSessionId random id for a new login session
+func NewSessionId() (string, error) {
+	raw := make([]byte, 16)
+	_, err := rand.Read(raw)
+	return hex.EncodeToString(raw), err
+}
+
+// NewRefreshToken random opaque refresh token, only its hash is stored
+func NewRefreshToken() (string, error) {
+	raw := make([]byte, 32)
+	_, err := rand.Read(raw)
+	return base64.RawURLEncoding.EncodeToString(raw), err
+}
+
+// HashRefreshToken SHA-256 hex digest, refresh tokens carry enough entropy
+// that a slow hash is not needed
+func HashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
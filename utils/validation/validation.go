@@ -0,0 +1,207 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/alkamalp/crm-golang/dto"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Message the same text in Indonesian and English
+type Message struct {
+	ID string `json:"id"`
+	EN string `json:"en"`
+}
+
+// FieldError one failed rule, Field is the name the client sent
+type FieldError struct {
+	Field   string  `json:"field"`
+	Rule    string  `json:"rule"`
+	Param   string  `json:"param,omitempty"`
+	Message Message `json:"message"`
+}
+
+func init() {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	validate.RegisterTagNameFunc(fieldName)
+	validate.RegisterValidation("password", isPassword)
+	validate.RegisterValidation("username", isUsername)
+}
+
+// NewBindErrorResponse status and body for a failed ShouldBind, rule
+// violations are listed per field with 422, anything else (malformed JSON,
+// a number that is not a number) is a plain 400
+func NewBindErrorResponse(err error) (int, dto.ErrorResponse) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return http.StatusBadRequest, dto.DefaultBadRequestResponse()
+	}
+
+	return http.StatusUnprocessableEntity, dto.DefaultDataInvalidResponse(fieldErrors(validationErrors))
+}
+
+// Struct check v, a struct, against its binding tags outside of a request
+// such as a row of an uploaded file, nil when v is valid
+func Struct(v any) []FieldError {
+	err := binding.Validator.ValidateStruct(v)
+	if err == nil {
+		return nil
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		panic(err)
+	}
+	return fieldErrors(validationErrors)
+}
+
+func fieldErrors(validationErrors validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, FieldError{
+			Field:   fieldErr.Field(),
+			Rule:    fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: message(fieldErr.Field(), fieldErr.Tag(), fieldErr.Param(), fieldErr.Kind() == reflect.String),
+		})
+	}
+	return fields
+}
+
+// NewFieldError rule violation found outside of the validator, such as a
+// value of a custom field, text tells whether param counts characters
+func NewFieldError(field string, rule string, param string, text bool) FieldError {
+	return FieldError{
+		Field:   field,
+		Rule:    rule,
+		Param:   param,
+		Message: message(field, rule, param, text),
+	}
+}
+
+// fieldName report fields by their form or json name instead of the Go name
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"form", "json"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// isPassword at least one letter and one digit, the length is checked with
+// min and max so the client learns which bound it missed
+func isPassword(fl validator.FieldLevel) bool {
+	var letter, digit bool
+	for _, r := range fl.Field().String() {
+		switch {
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return letter && digit
+}
+
+// isUsername letters, digits, dot, dash and underscore
+func isUsername(fl validator.FieldLevel) bool {
+	for _, r := range fl.Field().String() {
+		if !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '_')) {
+			return false
+		}
+	}
+	return true
+}
+
+func message(field string, rule string, param string, text bool) Message {
+	unit := Message{ID: "karakter", EN: "characters"}
+	if !text {
+		unit = Message{}
+	}
+
+	switch rule {
+	case "required":
+		return Message{
+			ID: fmt.Sprintf("%s wajib diisi", field),
+			EN: fmt.Sprintf("%s is required", field),
+		}
+	case "email":
+		return Message{
+			ID: fmt.Sprintf("%s harus berupa alamat email yang valid", field),
+			EN: fmt.Sprintf("%s must be a valid email address", field),
+		}
+	case "min", "gte":
+		return Message{
+			ID: strings.TrimSpace(fmt.Sprintf("%s minimal %s %s", field, param, unit.ID)),
+			EN: strings.TrimSpace(fmt.Sprintf("%s must be at least %s %s", field, param, unit.EN)),
+		}
+	case "max", "lte":
+		return Message{
+			ID: strings.TrimSpace(fmt.Sprintf("%s maksimal %s %s", field, param, unit.ID)),
+			EN: strings.TrimSpace(fmt.Sprintf("%s must be at most %s %s", field, param, unit.EN)),
+		}
+	case "oneof":
+		options := strings.ReplaceAll(param, " ", ", ")
+		return Message{
+			ID: fmt.Sprintf("%s harus salah satu dari: %s", field, options),
+			EN: fmt.Sprintf("%s must be one of: %s", field, options),
+		}
+	case "password":
+		return Message{
+			ID: fmt.Sprintf("%s harus mengandung huruf dan angka", field),
+			EN: fmt.Sprintf("%s must contain both letters and digits", field),
+		}
+	case "username":
+		return Message{
+			ID: fmt.Sprintf("%s hanya boleh berisi huruf, angka, titik, strip dan garis bawah", field),
+			EN: fmt.Sprintf("%s may only contain letters, digits, dots, dashes and underscores", field),
+		}
+	case "string":
+		return Message{
+			ID: fmt.Sprintf("%s harus berupa teks", field),
+			EN: fmt.Sprintf("%s must be text", field),
+		}
+	case "number":
+		return Message{
+			ID: fmt.Sprintf("%s harus berupa angka", field),
+			EN: fmt.Sprintf("%s must be a number", field),
+		}
+	case "integer":
+		return Message{
+			ID: fmt.Sprintf("%s harus berupa bilangan bulat", field),
+			EN: fmt.Sprintf("%s must be a whole number", field),
+		}
+	case "boolean":
+		return Message{
+			ID: fmt.Sprintf("%s harus berupa true atau false", field),
+			EN: fmt.Sprintf("%s must be true or false", field),
+		}
+	case "date":
+		return Message{
+			ID: fmt.Sprintf("%s harus berupa tanggal YYYY-MM-DD", field),
+			EN: fmt.Sprintf("%s must be a date as YYYY-MM-DD", field),
+		}
+	case "unknown":
+		return Message{
+			ID: fmt.Sprintf("%s tidak dikenal", field),
+			EN: fmt.Sprintf("%s is not a known field", field),
+		}
+	}
+	return Message{
+		ID: fmt.Sprintf("%s tidak valid", field),
+		EN: fmt.Sprintf("%s is invalid", field),
+	}
+}
@@ -0,0 +1,138 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	contentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	rootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	workbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	workbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	sheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetEnd = `</sheetData></worksheet>`
+)
+
+// ContentType of a workbook written by Writer
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Writer streams a workbook with a single sheet, rows go straight into the
+// zip so memory stays flat however many rows are written. Nothing reaches
+// the underlying writer before the first row or Close
+type Writer struct {
+	zip       *zip.Writer
+	sheet     io.Writer
+	sheetName string
+	rows      int
+}
+
+func NewWriter(w io.Writer, sheetName string) *Writer {
+	return &Writer{zip: zip.NewWriter(w), sheetName: sheetName}
+}
+
+// WriteRow one row, numbers become number cells, times are written as
+// RFC 3339 text and anything else as text
+func (w *Writer) WriteRow(values []any) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.rows++
+	var row strings.Builder
+	fmt.Fprintf(&row, `<row r="%d">`, w.rows)
+	for _, value := range values {
+		writeCell(&row, value)
+	}
+	row.WriteString(`</row>`)
+	_, err := io.WriteString(w.sheet, row.String())
+	return err
+}
+
+// Close finish the sheet and the zip, it does not close the underlying writer
+func (w *Writer) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w.sheet, sheetEnd); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
+
+// start write every part but the rows, the sheet is left open and last
+func (w *Writer) start() error {
+	if w.sheet != nil {
+		return nil
+	}
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(w.sheetName))
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", fmt.Sprintf(workbook, name.String())},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+	}
+	for _, part := range parts {
+		pw, err := w.create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return err
+		}
+	}
+	sheet, err := w.create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(sheet, sheetStart); err != nil {
+		return err
+	}
+	w.sheet = sheet
+	return nil
+}
+
+func (w *Writer) create(name string) (io.Writer, error) {
+	return w.zip.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+}
+
+func writeCell(b *strings.Builder, value any) {
+	var number string
+	switch v := value.(type) {
+	case nil:
+		b.WriteString(`<c/>`)
+		return
+	case int:
+		number = strconv.Itoa(v)
+	case int64:
+		number = strconv.FormatInt(v, 10)
+	case uint:
+		number = strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		number = strconv.FormatUint(v, 10)
+	case float64:
+		number = strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		value = v.Format(time.RFC3339)
+	}
+	if number != "" {
+		fmt.Fprintf(b, `<c><v>%s</v></c>`, number)
+		return
+	}
+	b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+	xml.EscapeText(b, []byte(fmt.Sprint(value)))
+	b.WriteString(`</t></is></c>`)
+}
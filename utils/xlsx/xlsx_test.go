@@ -0,0 +1,52 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPart(t *testing.T, archive *zip.Reader, name string) string {
+	t.Helper()
+	part, err := archive.Open(name)
+	require.NoError(t, err)
+	defer part.Close()
+	content, err := io.ReadAll(part)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(&out, "Customers & co")
+
+	require.NoError(t, w.WriteRow([]any{"id", "name"}))
+	assert.Zero(t, out.Len(), "rows are buffered until the zip is flushed")
+	require.NoError(t, w.WriteRow([]any{uint(7), " <John> ", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), nil}))
+	require.NoError(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	assert.Contains(t, readPart(t, archive, "xl/workbook.xml"), `name="Customers &amp; co"`)
+	assert.Contains(t, readPart(t, archive, "[Content_Types].xml"), "/xl/worksheets/sheet1.xml")
+
+	sheet := readPart(t, archive, "xl/worksheets/sheet1.xml")
+	assert.Contains(t, sheet, `<row r="1"><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, sheet, `<row r="2"><c><v>7</v></c><c t="inlineStr"><is><t xml:space="preserve"> &lt;John&gt; </t></is></c>`)
+	assert.Contains(t, sheet, `2024-01-02T03:04:05Z`)
+	assert.Contains(t, sheet, `<c/></row></sheetData></worksheet>`)
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, NewWriter(&out, "Sheet1").Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	assert.Contains(t, readPart(t, archive, "xl/worksheets/sheet1.xml"), `<sheetData></sheetData>`)
+}